@@ -166,7 +166,7 @@ var sampleAppsAndroidSDK22SubdirResultYML = fmt.Sprintf(`options:
           and Android manifest file. Each module can be independently built, tested,
           and debugged. You can add new modules to your Bitrise builds at any time.
         env_key: MODULE
-        type: user_input
+        type: selector
         value_map:
           app:
             title: Variant
@@ -251,7 +251,7 @@ configs:
               inputs:
               - project_location: $PROJECT_LOCATION
               - module: $MODULE
-              - variant: $VARIANT
+              - variant: release
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
@@ -354,7 +354,7 @@ var sampleAppsAndroid22ResultYML = fmt.Sprintf(`options:
           and Android manifest file. Each module can be independently built, tested,
           and debugged. You can add new modules to your Bitrise builds at any time.
         env_key: MODULE
-        type: user_input
+        type: selector
         value_map:
           app:
             title: Variant
@@ -439,7 +439,7 @@ configs:
               inputs:
               - project_location: $PROJECT_LOCATION
               - module: $MODULE
-              - variant: $VARIANT
+              - variant: release
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
@@ -515,7 +515,7 @@ var androidNonExecutableGradlewResultYML = fmt.Sprintf(`options:
           and Android manifest file. Each module can be independently built, tested,
           and debugged. You can add new modules to your Bitrise builds at any time.
         env_key: MODULE
-        type: user_input
+        type: selector
         value_map:
           app:
             title: Variant
@@ -600,7 +600,7 @@ configs:
               inputs:
               - project_location: $PROJECT_LOCATION
               - module: $MODULE
-              - variant: $VARIANT
+              - variant: release
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
@@ -650,7 +650,7 @@ var sampleAppsKotlinDSLResultYML = fmt.Sprintf(`options:
           and Android manifest file. Each module can be independently built, tested,
           and debugged. You can add new modules to your Bitrise builds at any time.
         env_key: MODULE
-        type: user_input
+        type: selector
         value_map:
           app:
             title: Variant
@@ -736,7 +736,7 @@ configs:
               inputs:
               - project_location: $PROJECT_LOCATION
               - module: $MODULE
-              - variant: $VARIANT
+              - variant: release
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
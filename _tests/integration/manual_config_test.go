@@ -166,6 +166,66 @@ var customConfigVersions = []interface{}{
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -247,6 +307,72 @@ var customConfigVersions = []interface{}{
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.CachePullVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
 	// ionic
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
@@ -283,6 +409,82 @@ var customConfigVersions = []interface{}{
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
+	// kmp
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.GradleRunnerVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.GradleRunnerVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.GradleRunnerVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.GradleRunnerVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.GradleRunnerVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.GradleRunnerVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.GradleRunnerVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
 	// macos
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
@@ -308,6 +510,24 @@ var customConfigVersions = []interface{}{
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
+	// maui
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+
 	// other
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
@@ -315,8 +535,7 @@ var customConfigVersions = []interface{}{
 	steps.ScriptVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// react native
-	// default-react-native-config/deploy
+	// react-native
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -328,7 +547,6 @@ var customConfigVersions = []interface{}{
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// default-react-native-config/primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -336,21 +554,19 @@ var customConfigVersions = []interface{}{
 	steps.NpmVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// default-react-native-expo-config/deploy
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
 	steps.YarnVersion,
 	steps.ScriptVersion,
-	steps.ExpoDetachVersion,
+	steps.ScriptVersion,
 	steps.InstallMissingAndroidToolsVersion,
 	steps.AndroidBuildVersion,
 	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// default-react-native-expo-config/primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -458,8 +674,8 @@ var customConfigResultYML = fmt.Sprintf(`options:
           "no":
             title: Platform
             summary: The target platform for your first build. Your options are iOS,
-              Android, both, or neither. You can change this in your Env Vars at any
-              time.
+              Android, both, web, macOS, Linux, or neither. You can change this in
+              your Env Vars at any time.
             type: selector
             value_map:
               android:
@@ -534,13 +750,19 @@ var customConfigResultYML = fmt.Sprintf(`options:
                             config: flutter-config-app-ios
                           enterprise:
                             config: flutter-config-app-ios
+              linux:
+                config: flutter-config-app-linux
+              macos:
+                config: flutter-config-app-macos
               none:
                 config: flutter-config
+              web:
+                config: flutter-config-app-web
           "yes":
             title: Platform
             summary: The target platform for your first build. Your options are iOS,
-              Android, both, or neither. You can change this in your Env Vars at any
-              time.
+              Android, both, web, macOS, Linux, or neither. You can change this in
+              your Env Vars at any time.
             type: selector
             value_map:
               android:
@@ -615,8 +837,14 @@ var customConfigResultYML = fmt.Sprintf(`options:
                             config: flutter-config-test-app-ios
                           enterprise:
                             config: flutter-config-test-app-ios
+              linux:
+                config: flutter-config-test-app-linux
+              macos:
+                config: flutter-config-test-app-macos
               none:
                 config: flutter-config-test
+              web:
+                config: flutter-config-test-app-web
   ionic:
     title: Directory of the Ionic config.xml file
     summary: The working directory of your Ionic project is where you store your config.xml
@@ -673,58 +901,237 @@ var customConfigResultYML = fmt.Sprintf(`options:
                 config: default-ios-config
               enterprise:
                 config: default-ios-config
-  macos:
-    title: Project or Workspace path
-    summary: The location of your Xcode project or Xcode workspace files, stored as
-      an Environment Variable. In your Workflows, you can specify paths relative to
-      this path.
-    env_key: BITRISE_PROJECT_PATH
+  kmp:
+    title: Kotlin Multiplatform project location
+    summary: The root directory of the Gradle project holding the Kotlin Multiplatform
+      modules, stored as an Environment Variable. In your Workflows, you can specify
+      paths relative to this path. You can change this at any time.
+    env_key: KMP_PROJECT_LOCATION
     type: user_input
     value_map:
       "":
-        title: Scheme name
-        summary: An Xcode scheme defines a collection of targets to build, a configuration
-          to use when building, and a collection of tests to execute. Only shared
-          schemes are detected automatically but you can use any scheme as a target
-          on Bitrise. You can change the scheme at any time in your Env Vars.
-        env_key: BITRISE_SCHEME
+        title: Shared module
+        summary: The Kotlin Multiplatform module, which holds the code shared by the
+          Android and iOS apps. Its allTests task runs the tests of every target.
+          You can change this at any time.
+        env_key: KMP_SHARED_MODULE
         type: user_input
         value_map:
           "":
-            title: |-
-              Application export method
-              NOTE: `+"`none`"+` means: Export a copy of the application without re-signing.
-            summary: The export method used to create an .app file in your builds,
-              stored as an Environment Variable. You can change this at any time,
-              or even create several .app files with different export methods in the
-              same build.
-            env_key: BITRISE_EXPORT_METHOD
+            title: Platform
+            summary: The apps built by the deploy Workflow. Your options are Android,
+              iOS, both, or neither.
             type: selector
             value_map:
-              app-store:
-                config: default-macos-config
-              developer-id:
-                config: default-macos-config
-              development:
-                config: default-macos-config
-              none:
-                config: default-macos-config
-  react-native:
-    title: Was your React Native app created with the Expo CLI and using Managed Workflow?
-    summary: Will include *Expo Eject** Step if using Expo Managed Workflow (https://docs.expo.io/introduction/managed-vs-bare/).
-      If ios/android native projects are present in the repository, choose No.
-    type: selector
-    value_map:
-      "no":
-        title: The root directory of an Android project
-        summary: The root directory of your Android project, stored as an Environment
-          Variable. In your Workflows, you can specify paths relative to this path.
-          You can change this at any time.
-        env_key: PROJECT_LOCATION
-        type: user_input
-        value_map:
-          "":
-            title: Module
+              android:
+                title: Module
+                summary: Modules provide a container for your Android project's source
+                  code, resource files, and app level settings, such as the module-level
+                  build file and Android manifest file. Each module can be independently
+                  built, tested, and debugged. You can add new modules to your Bitrise
+                  builds at any time.
+                env_key: MODULE
+                type: user_input
+                value_map:
+                  "":
+                    title: Variant
+                    summary: Your Android build variant. You can add variants at any
+                      time, as well as further configure your existing variants later.
+                    env_key: VARIANT
+                    type: user_input_optional
+                    value_map:
+                      "":
+                        config: kmp-android-config
+              both:
+                title: Module
+                summary: Modules provide a container for your Android project's source
+                  code, resource files, and app level settings, such as the module-level
+                  build file and Android manifest file. Each module can be independently
+                  built, tested, and debugged. You can add new modules to your Bitrise
+                  builds at any time.
+                env_key: MODULE
+                type: user_input
+                value_map:
+                  "":
+                    title: Variant
+                    summary: Your Android build variant. You can add variants at any
+                      time, as well as further configure your existing variants later.
+                    env_key: VARIANT
+                    type: user_input_optional
+                    value_map:
+                      "":
+                        title: Project or Workspace path
+                        summary: The location of your Xcode project or Xcode workspace
+                          files, stored as an Environment Variable. In your Workflows,
+                          you can specify paths relative to this path.
+                        env_key: BITRISE_PROJECT_PATH
+                        type: user_input
+                        value_map:
+                          "":
+                            title: Scheme name
+                            summary: An Xcode scheme defines a collection of targets
+                              to build, a configuration to use when building, and
+                              a collection of tests to execute. Only shared schemes
+                              are detected automatically but you can use any scheme
+                              as a target on Bitrise. You can change the scheme at
+                              any time in your Env Vars.
+                            env_key: BITRISE_SCHEME
+                            type: user_input
+                            value_map:
+                              "":
+                                title: ipa export method
+                                summary: The export method used to create an .ipa
+                                  file in your builds, stored as an Environment Variable.
+                                  You can change this at any time, or even create
+                                  several .ipa files with different export methods
+                                  in the same build.
+                                env_key: BITRISE_EXPORT_METHOD
+                                type: selector
+                                value_map:
+                                  ad-hoc:
+                                    config: kmp-android-ios-config
+                                  app-store:
+                                    config: kmp-android-ios-config
+                                  development:
+                                    config: kmp-android-ios-config
+                                  enterprise:
+                                    config: kmp-android-ios-config
+              ios:
+                title: Project or Workspace path
+                summary: The location of your Xcode project or Xcode workspace files,
+                  stored as an Environment Variable. In your Workflows, you can specify
+                  paths relative to this path.
+                env_key: BITRISE_PROJECT_PATH
+                type: user_input
+                value_map:
+                  "":
+                    title: Scheme name
+                    summary: An Xcode scheme defines a collection of targets to build,
+                      a configuration to use when building, and a collection of tests
+                      to execute. Only shared schemes are detected automatically but
+                      you can use any scheme as a target on Bitrise. You can change
+                      the scheme at any time in your Env Vars.
+                    env_key: BITRISE_SCHEME
+                    type: user_input
+                    value_map:
+                      "":
+                        title: ipa export method
+                        summary: The export method used to create an .ipa file in
+                          your builds, stored as an Environment Variable. You can
+                          change this at any time, or even create several .ipa files
+                          with different export methods in the same build.
+                        env_key: BITRISE_EXPORT_METHOD
+                        type: selector
+                        value_map:
+                          ad-hoc:
+                            config: kmp-ios-config
+                          app-store:
+                            config: kmp-ios-config
+                          development:
+                            config: kmp-ios-config
+                          enterprise:
+                            config: kmp-ios-config
+              none:
+                config: kmp-config
+  macos:
+    title: Project or Workspace path
+    summary: The location of your Xcode project or Xcode workspace files, stored as
+      an Environment Variable. In your Workflows, you can specify paths relative to
+      this path.
+    env_key: BITRISE_PROJECT_PATH
+    type: user_input
+    value_map:
+      "":
+        title: Scheme name
+        summary: An Xcode scheme defines a collection of targets to build, a configuration
+          to use when building, and a collection of tests to execute. Only shared
+          schemes are detected automatically but you can use any scheme as a target
+          on Bitrise. You can change the scheme at any time in your Env Vars.
+        env_key: BITRISE_SCHEME
+        type: user_input
+        value_map:
+          "":
+            title: |-
+              Application export method
+              NOTE: `+"`none`"+` means: Export a copy of the application without re-signing.
+            summary: The export method used to create an .app file in your builds,
+              stored as an Environment Variable. You can change this at any time,
+              or even create several .app files with different export methods in the
+              same build.
+            env_key: BITRISE_EXPORT_METHOD
+            type: selector
+            value_map:
+              app-store:
+                config: default-macos-config
+              developer-id:
+                config: default-macos-config
+              development:
+                config: default-macos-config
+              none:
+                config: default-macos-config
+  maui:
+    title: Path to the .NET MAUI project file
+    summary: The csproj file of your .NET MAUI app, which uses <UseMaui>true</UseMaui>.
+      It is published by the dotnet CLI.
+    env_key: BITRISE_PROJECT_PATH
+    type: user_input
+    value_map:
+      "":
+        title: Platform
+        summary: The platform of the target framework. Your options are iOS or Android.
+        type: selector
+        value_map:
+          android:
+            title: Target framework
+            summary: The target framework of the app you wish to publish in your first
+              build, like net8.0-ios or net8.0-android. You can change this at any
+              time in your Workflows.
+            env_key: BITRISE_MAUI_TARGET_FRAMEWORK
+            type: user_input
+            value_map:
+              "":
+                title: .NET MAUI build configuration
+                summary: The build configuration that you wish to run in your first
+                  build. You can change this at any time in your Workflows.
+                env_key: BITRISE_MAUI_CONFIGURATION
+                type: user_input
+                value_map:
+                  "":
+                    config: default-maui-android-config
+          ios:
+            title: Target framework
+            summary: The target framework of the app you wish to publish in your first
+              build, like net8.0-ios or net8.0-android. You can change this at any
+              time in your Workflows.
+            env_key: BITRISE_MAUI_TARGET_FRAMEWORK
+            type: user_input
+            value_map:
+              "":
+                title: .NET MAUI build configuration
+                summary: The build configuration that you wish to run in your first
+                  build. You can change this at any time in your Workflows.
+                env_key: BITRISE_MAUI_CONFIGURATION
+                type: user_input
+                value_map:
+                  "":
+                    config: default-maui-ios-config
+  react-native:
+    title: Was your React Native app created with the Expo CLI and using Managed Workflow?
+    summary: Will generate the native projects with Expo prebuild if using Expo Managed
+      Workflow (https://docs.expo.dev/archive/managed-vs-bare/). If ios/android native
+      projects are present in the repository, choose No.
+    type: selector
+    value_map:
+      "no":
+        title: The root directory of an Android project
+        summary: The root directory of your Android project, stored as an Environment
+          Variable. In your Workflows, you can specify paths relative to this path.
+          You can change this at any time.
+        env_key: PROJECT_LOCATION
+        type: user_input
+        value_map:
+          "":
+            title: Module
             summary: Modules provide a container for your Android project's source
               code, resource files, and app level settings, such as the module-level
               build file and Android manifest file. Each module can be independently
@@ -777,10 +1184,10 @@ var customConfigResultYML = fmt.Sprintf(`options:
                               enterprise:
                                 config: default-react-native-config
       "yes":
-        title: The iOS project path generated by running 'expo eject' locally
+        title: The iOS project path generated by running 'expo prebuild' locally
         summary: |-
-          Will add the Expo Eject Step to the Workflow to generate the native iOS project, so it can be built and archived.
-          Run 'expo eject' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories. See https://docs.expo.io/bare/customizing/ for more details.
+          Will run 'expo prebuild' in the Workflow to generate the native iOS project, so it can be built and archived.
+          Run 'npx expo prebuild' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories. See https://docs.expo.dev/workflow/prebuild/ for more details.
           For example: './ios/myproject.xcworkspace'.
         env_key: BITRISE_PROJECT_PATH
         type: user_input
@@ -790,8 +1197,8 @@ var customConfigResultYML = fmt.Sprintf(`options:
             summary: |-
               Optional, only needs to be entered if the key expo/ios/bundleIdentifier is not set in 'app.json'.
 
-              Will add the Expo Eject Step to the Workflow to generate the native iOS project, so the IPA can be exported.
-              For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+              Will run 'expo prebuild' in the Workflow to generate the native iOS project, so the IPA can be exported.
+              For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
               For example: 'com.sample.myapp'.
             env_key: EXPO_BARE_IOS_BUNLDE_ID
             type: user_input
@@ -801,8 +1208,8 @@ var customConfigResultYML = fmt.Sprintf(`options:
                 summary: |-
                   An Xcode scheme defines a collection of targets to build, a configuration to use when building, and a collection of tests to execute. You can change the scheme at any time.
 
-                  Will add the Expo Eject Step to the Workflow to generate the native iOS project, so it can be built and archived.
-                  Run 'expo eject' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories.
+                  Will run 'expo prebuild' in the Workflow to generate the native iOS project, so it can be built and archived.
+                  Run 'npx expo prebuild' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories.
                 env_key: BITRISE_SCHEME
                 type: user_input
                 value_map:
@@ -820,15 +1227,16 @@ var customConfigResultYML = fmt.Sprintf(`options:
                         summary: |-
                           Optional, only needs to be entered if the key expo/android/package is not set in 'app.json'.
 
-                          Will add the Expo Eject Step to the Workflow to generate the native Android project, so the bundle (AAB) can be built.
-                          For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+                          Will run 'expo prebuild' in the Workflow to generate the native Android project, so the bundle (AAB) can be built.
+                          For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
                           For example: 'com.sample.myapp'.
                         env_key: EXPO_BARE_ANDROID_PACKAGE
                         type: user_input_optional
                         value_map:
                           "":
                             title: Project root directory
-                            summary: The directory of the 'app.json' or 'package.json'
+                            summary: The directory of the app config ('app.json',
+                              'app.config.js' or 'app.config.ts') or 'package.json'
                               file of your React Native project.
                             env_key: WORKDIR
                             type: user_input
@@ -869,15 +1277,16 @@ var customConfigResultYML = fmt.Sprintf(`options:
                         summary: |-
                           Optional, only needs to be entered if the key expo/android/package is not set in 'app.json'.
 
-                          Will add the Expo Eject Step to the Workflow to generate the native Android project, so the bundle (AAB) can be built.
-                          For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+                          Will run 'expo prebuild' in the Workflow to generate the native Android project, so the bundle (AAB) can be built.
+                          For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
                           For example: 'com.sample.myapp'.
                         env_key: EXPO_BARE_ANDROID_PACKAGE
                         type: user_input_optional
                         value_map:
                           "":
                             title: Project root directory
-                            summary: The directory of the 'app.json' or 'package.json'
+                            summary: The directory of the app config ('app.json',
+                              'app.config.js' or 'app.config.ts') or 'package.json'
                               file of your React Native project.
                             env_key: WORKDIR
                             type: user_input
@@ -918,15 +1327,16 @@ var customConfigResultYML = fmt.Sprintf(`options:
                         summary: |-
                           Optional, only needs to be entered if the key expo/android/package is not set in 'app.json'.
 
-                          Will add the Expo Eject Step to the Workflow to generate the native Android project, so the bundle (AAB) can be built.
-                          For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+                          Will run 'expo prebuild' in the Workflow to generate the native Android project, so the bundle (AAB) can be built.
+                          For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
                           For example: 'com.sample.myapp'.
                         env_key: EXPO_BARE_ANDROID_PACKAGE
                         type: user_input_optional
                         value_map:
                           "":
                             title: Project root directory
-                            summary: The directory of the 'app.json' or 'package.json'
+                            summary: The directory of the app config ('app.json',
+                              'app.config.js' or 'app.config.ts') or 'package.json'
                               file of your React Native project.
                             env_key: WORKDIR
                             type: user_input
@@ -967,15 +1377,16 @@ var customConfigResultYML = fmt.Sprintf(`options:
                         summary: |-
                           Optional, only needs to be entered if the key expo/android/package is not set in 'app.json'.
 
-                          Will add the Expo Eject Step to the Workflow to generate the native Android project, so the bundle (AAB) can be built.
-                          For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+                          Will run 'expo prebuild' in the Workflow to generate the native Android project, so the bundle (AAB) can be built.
+                          For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
                           For example: 'com.sample.myapp'.
                         env_key: EXPO_BARE_ANDROID_PACKAGE
                         type: user_input_optional
                         value_map:
                           "":
                             title: Project root directory
-                            summary: The directory of the 'app.json' or 'package.json'
+                            summary: The directory of the app config ('app.json',
+                              'app.config.js' or 'app.config.ts') or 'package.json'
                               file of your React Native project.
                             env_key: WORKDIR
                             type: user_input
@@ -1105,7 +1516,7 @@ configs:
               inputs:
               - project_location: $PROJECT_LOCATION
               - module: $MODULE
-              - variant: $VARIANT
+              - variant: release
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
@@ -1321,8 +1732,7 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
-              - configuration: Release
+              - distribution_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -1374,8 +1784,7 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
-              - configuration: Release
+              - distribution_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -1394,7 +1803,7 @@ configs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
-    flutter-config-test: |
+    flutter-config-app-linux: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -1404,7 +1813,7 @@ configs:
       - pull_request_source_branch: '*'
         workflow: primary
       workflows:
-        primary:
+        deploy:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
@@ -1418,12 +1827,37 @@ configs:
           - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-test@%s:
+          - script@%s:
+              title: flutter build linux
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  flutter build linux --release
+
+                  cd build/linux/x64/release/bundle
+                  zip -r "$BITRISE_DEPLOY_DIR/linux.zip" .
+              - working_dir: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
-    flutter-config-test-app-android: |
+    flutter-config-app-macos: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -1447,15 +1881,100 @@ configs:
           - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-test@%s:
+          - script@%s:
+              title: flutter build macos
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  flutter build macos --release
+
+                  cd build/macos/Build/Products/Release
+                  zip -r "$BITRISE_DEPLOY_DIR/macos.zip" .
+              - working_dir: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-build@%s:
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+    flutter-config-app-web: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: flutter build web
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  flutter build web --release
+
+                  cd build/web
+                  zip -r "$BITRISE_DEPLOY_DIR/web.zip" .
+              - working_dir: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: android
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
+    flutter-config-test: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
         primary:
           steps:
           - activate-ssh-key@%s:
@@ -1475,7 +1994,7 @@ configs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
-    flutter-config-test-app-both: |
+    flutter-config-test-app-android: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -1492,7 +2011,6 @@ configs:
           - git-clone@%s: {}
           - script@%s:
               title: Do anything with Script step
-          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s:
               inputs:
               - is_update: "false"
@@ -1506,13 +2024,7 @@ configs:
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: both
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
-              - configuration: Release
+              - platform: android
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -1534,7 +2046,7 @@ configs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
-    flutter-config-test-app-ios: |
+    flutter-config-test-app-both: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -1565,13 +2077,12 @@ configs:
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: ios
+              - platform: both
           - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
-              - configuration: Release
+              - distribution_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -1593,18 +2104,256 @@ configs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
-  ionic:
-    default-ionic-config: |
+    flutter-config-test-app-ios: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: ionic
+      project_type: flutter
       trigger_map:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
         workflow: primary
       workflows:
-        primary:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: ios
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+    flutter-config-test-app-linux: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: flutter build linux
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  flutter build linux --release
+
+                  cd build/linux/x64/release/bundle
+                  zip -r "$BITRISE_DEPLOY_DIR/linux.zip" .
+              - working_dir: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+    flutter-config-test-app-macos: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: flutter build macos
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  flutter build macos --release
+
+                  cd build/macos/Build/Products/Release
+                  zip -r "$BITRISE_DEPLOY_DIR/macos.zip" .
+              - working_dir: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+    flutter-config-test-app-web: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: flutter build web
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  flutter build web --release
+
+                  cd build/web
+                  zip -r "$BITRISE_DEPLOY_DIR/web.zip" .
+              - working_dir: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s:
+              inputs:
+              - is_update: "false"
+          - cache-pull@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+  ionic:
+    default-ionic-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: ionic
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        primary:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
@@ -1655,7 +2404,7 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
+              - distribution_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -1677,6 +2426,192 @@ configs:
               - scheme: $BITRISE_SCHEME
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
+  kmp:
+    kmp-android-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: kmp
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+          - gradle-runner@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+              - gradle_task: :$KMP_SHARED_MODULE:allTests
+          - android-build@%s:
+              inputs:
+              - project_location: $KMP_PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+          - gradle-runner@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+              - gradle_task: :$KMP_SHARED_MODULE:allTests
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+    kmp-android-ios-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: kmp
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+          - gradle-runner@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+              - gradle_task: :$KMP_SHARED_MODULE:allTests
+          - android-build@%s:
+              inputs:
+              - project_location: $KMP_PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+          - gradle-runner@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+              - gradle_task: :$KMP_SHARED_MODULE:allTests
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+    kmp-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: kmp
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+          - gradle-runner@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+              - gradle_task: :$KMP_SHARED_MODULE:allTests
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+    kmp-ios-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: kmp
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+          - gradle-runner@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+              - gradle_task: :$KMP_SHARED_MODULE:allTests
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+          - gradle-runner@%s:
+              inputs:
+              - gradlew_path: $KMP_PROJECT_LOCATION/gradlew
+              - gradle_task: :$KMP_SHARED_MODULE:allTests
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
   macos:
     default-macos-config: |
       format_version: "%s"
@@ -1731,6 +2666,76 @@ configs:
               - scheme: $BITRISE_SCHEME
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
+  maui:
+    default-maui-android-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: maui
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - script@%s:
+              title: Restore .NET workloads
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  dotnet workload restore "$BITRISE_PROJECT_PATH"
+          - script@%s:
+              title: Publish the app
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  dotnet publish "$BITRISE_PROJECT_PATH" --framework "$BITRISE_MAUI_TARGET_FRAMEWORK" --configuration "$BITRISE_MAUI_CONFIGURATION" --output "$BITRISE_DEPLOY_DIR"
+          - deploy-to-bitrise-io@%s: {}
+    default-maui-ios-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: maui
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - script@%s:
+              title: Restore .NET workloads
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  dotnet workload restore "$BITRISE_PROJECT_PATH"
+          - script@%s:
+              title: Publish the app
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  dotnet publish "$BITRISE_PROJECT_PATH" --framework "$BITRISE_MAUI_TARGET_FRAMEWORK" --configuration "$BITRISE_MAUI_CONFIGURATION" --output "$BITRISE_DEPLOY_DIR" -p:RuntimeIdentifier=ios-arm64
+          - deploy-to-bitrise-io@%s: {}
   other:
     other-config: |
       format_version: "%s"
@@ -1781,8 +2786,8 @@ configs:
             Profile** inputs regarding to the uploaded codesigning files\n1. Specify manual
             codesign style\nIf the codesigning files, are generated manually on the Apple
             Developer Portal,  \nyou need to explicitly specify to use manual coedsign settings
-            \ \n(as ejected rn projects have xcode managed codesigning turned on).  \nTo
-            do so, add 'CODE_SIGN_STYLE=\"Manual\"' to 'Additional options for xcodebuild
+            \ \n(as the generated rn projects have xcode managed codesigning turned on).
+            \ \nTo do so, add 'CODE_SIGN_STYLE=\"Manual\"' to 'Additional options for xcodebuild
             call' input\n\n## To run this workflow\n\nIf you want to run this workflow manually:\n\n1.
             Open the app's build list page\n2. Click on **[Start/Schedule a Build]** button\n3.
             Select **deploy** in **Workflow** dropdown input\n4. Click **[Start Build]**
@@ -1811,7 +2816,7 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
+              - distribution_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
           - deploy-to-bitrise-io@%s: {}
         primary:
@@ -1858,8 +2863,8 @@ configs:
             Profile** inputs regarding to the uploaded codesigning files\n1. Specify manual
             codesign style\nIf the codesigning files, are generated manually on the Apple
             Developer Portal,  \nyou need to explicitly specify to use manual coedsign settings
-            \ \n(as ejected rn projects have xcode managed codesigning turned on).  \nTo
-            do so, add 'CODE_SIGN_STYLE=\"Manual\"' to 'Additional options for xcodebuild
+            \ \n(as the generated rn projects have xcode managed codesigning turned on).
+            \ \nTo do so, add 'CODE_SIGN_STYLE=\"Manual\"' to 'Additional options for xcodebuild
             call' input\n\n## To run this workflow\n\nIf you want to run this workflow manually:\n\n1.
             Open the app's build list page\n2. Click on **[Start/Schedule a Build]** button\n3.
             Select **deploy** in **Workflow** dropdown input\n4. Click **[Start Build]**
@@ -1879,7 +2884,7 @@ configs:
               - workdir: $WORKDIR
               - command: install
           - script@%s:
-              title: Set bundleIdentifier, packageName for Expo Eject
+              title: Set bundleIdentifier, packageName for Expo prebuild
               inputs:
               - content: |-
                   #!/usr/bin/env bash
@@ -1890,9 +2895,15 @@ configs:
                   jq '.expo.android |= if has("package") or env.EXPO_BARE_ANDROID_PACKAGE == "" or env.EXPO_BARE_ANDROID_PACKAGE == null then . else .package = env.EXPO_BARE_ANDROID_PACKAGE end |
                   .expo.ios |= if has("bundleIdentifier") or env.EXPO_BARE_IOS_BUNLDE_ID == "" or env.EXPO_BARE_IOS_BUNLDE_ID == null then . else .bundleIdentifier = env.EXPO_BARE_IOS_BUNLDE_ID end' <${appJson} >${tmp}
                   [[ $?==0 ]] && mv -f ${tmp} ${appJson}
-          - expo-detach@%s:
+          - script@%s:
+              title: Generate the native projects with Expo prebuild
               inputs:
-              - project_path: $WORKDIR
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  npx expo prebuild
+              - working_dir: $WORKDIR
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
@@ -1906,7 +2917,7 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
+              - distribution_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
           - deploy-to-bitrise-io@%s: {}
         primary:
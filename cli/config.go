@@ -45,6 +45,11 @@ var configCommand = cli.Command{
 			Usage: "Output format, options [json, yaml].",
 			Value: "yaml",
 		},
+		cli.StringFlag{
+			Name:  "trigger-preset",
+			Usage: "Trigger map preset of the generated configs, options [default, gitflow, trunk, tags].",
+			Value: "default",
+		},
 	},
 }
 
@@ -54,6 +59,7 @@ func initConfig(c *cli.Context) error {
 	searchDir := c.String("dir")
 	outputDir := c.String("output-dir")
 	formatStr := c.String("format")
+	triggerPresetStr := c.String("trigger-preset")
 
 	if formatStr == "" {
 		formatStr = output.YAMLFormat.String()
@@ -66,6 +72,11 @@ func initConfig(c *cli.Context) error {
 		return fmt.Errorf("Not allowed output format (%s), options: [%s, %s]", format.String(), output.YAMLFormat.String(), output.JSONFormat.String())
 	}
 
+	triggerPreset, err := models.ParseTriggerPreset(triggerPresetStr)
+	if err != nil {
+		return fmt.Errorf("Failed to parse trigger preset, error: %s", err)
+	}
+
 	//
 	currentDir, err := pathutil.AbsPath("./")
 	if err != nil {
@@ -101,9 +112,10 @@ func initConfig(c *cli.Context) error {
 	log.TInfof(colorstring.Yellowf("scan dir: %s", searchDir))
 	log.TInfof(colorstring.Yellowf("output dir: %s", outputDir))
 	log.TInfof(colorstring.Yellowf("output format: %s", format))
+	log.TInfof(colorstring.Yellowf("trigger preset: %s", triggerPreset))
 	fmt.Println()
 
-	result, err := scanner.GenerateAndWriteResults(searchDir, outputDir, format)
+	result, err := scanner.GenerateAndWriteResults(searchDir, outputDir, format, triggerPreset)
 	if err != nil {
 		return err
 	}
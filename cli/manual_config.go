@@ -6,6 +6,7 @@ import (
 	"path"
 	"path/filepath"
 
+	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/output"
 	"github.com/bitrise-io/bitrise-init/scanner"
 	"github.com/bitrise-io/go-utils/colorstring"
@@ -39,6 +40,11 @@ var manualConfigCommand = cli.Command{
 			Usage: "Output format, options [json, yaml].",
 			Value: "yaml",
 		},
+		cli.StringFlag{
+			Name:  "trigger-preset",
+			Usage: "Trigger map preset of the generated configs, options [default, gitflow, trunk, tags].",
+			Value: "default",
+		},
 	},
 }
 
@@ -47,12 +53,14 @@ func initManualConfig(c *cli.Context) error {
 	isCI := c.GlobalBool("ci")
 	outputDir := c.String("output-dir")
 	formatStr := c.String("format")
+	triggerPresetStr := c.String("trigger-preset")
 
 	if isCI {
 		log.TInfof(colorstring.Yellow("CI mode"))
 	}
 	log.TInfof(colorstring.Yellowf("output dir: %s", outputDir))
 	log.TInfof(colorstring.Yellowf("output format: %s", formatStr))
+	log.TInfof(colorstring.Yellowf("trigger preset: %s", triggerPresetStr))
 	fmt.Println()
 
 	currentDir, err := pathutil.AbsPath("./")
@@ -85,9 +93,14 @@ func initManualConfig(c *cli.Context) error {
 	if format != output.JSONFormat && format != output.YAMLFormat {
 		return fmt.Errorf("Not allowed output format (%v), options: [%s, %s]", format, output.YAMLFormat.String(), output.JSONFormat.String())
 	}
+
+	triggerPreset, err := models.ParseTriggerPreset(triggerPresetStr)
+	if err != nil {
+		return fmt.Errorf("Failed to parse trigger preset, error: %s", err)
+	}
 	// ---
 
-	scanResult, err := scanner.ManualConfig()
+	scanResult, err := scanner.ManualConfig(triggerPreset)
 	if err != nil {
 		return err
 	}
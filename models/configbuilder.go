@@ -22,17 +22,30 @@ const (
 	defaultSteplibSource = "https://github.com/bitrise-io/bitrise-steplib.git"
 )
 
+// BitriseDataModel is the bitrise.yml generated by the config builder,
+// it has the fields of the bitrise package's model the builder fills in, with the extended trigger map.
+type BitriseDataModel struct {
+	FormatVersion        string                                 `json:"format_version" yaml:"format_version"`
+	DefaultStepLibSource string                                 `json:"default_step_lib_source,omitempty" yaml:"default_step_lib_source,omitempty"`
+	ProjectType          string                                 `json:"project_type" yaml:"project_type"`
+	App                  bitriseModels.AppModel                 `json:"app,omitempty" yaml:"app,omitempty"`
+	TriggerMap           TriggerMapModel                        `json:"trigger_map,omitempty" yaml:"trigger_map,omitempty"`
+	Workflows            map[string]bitriseModels.WorkflowModel `json:"workflows,omitempty" yaml:"workflows,omitempty"`
+}
+
 // ConfigBuilderModel ...
 type ConfigBuilderModel struct {
 	workflowBuilderMap map[WorkflowID]*workflowBuilderModel
+	triggerPolicy      TriggerPolicy
 }
 
-// NewDefaultConfigBuilder ...
-func NewDefaultConfigBuilder() *ConfigBuilderModel {
+// NewDefaultConfigBuilder returns a config builder, generating the trigger map of the trigger preset.
+func NewDefaultConfigBuilder(triggerPreset TriggerPreset) *ConfigBuilderModel {
 	return &ConfigBuilderModel{
 		workflowBuilderMap: map[WorkflowID]*workflowBuilderModel{
 			PrimaryWorkflowID: newDefaultWorkflowBuilder(),
 		},
+		triggerPolicy: triggerPreset.Policy(),
 	}
 }
 
@@ -56,11 +69,21 @@ func (builder *ConfigBuilderModel) SetWorkflowDescriptionTo(workflow WorkflowID,
 	workflowBuilder.Description = description
 }
 
+// AppendEnvsTo ...
+func (builder *ConfigBuilderModel) AppendEnvsTo(workflow WorkflowID, envs ...envmanModels.EnvironmentItemModel) {
+	workflowBuilder := builder.workflowBuilderMap[workflow]
+	if workflowBuilder == nil {
+		workflowBuilder = newDefaultWorkflowBuilder()
+		builder.workflowBuilderMap[workflow] = workflowBuilder
+	}
+	workflowBuilder.appendEnvs(envs...)
+}
+
 // Generate ...
-func (builder *ConfigBuilderModel) Generate(projectType string, appEnvs ...envmanModels.EnvironmentItemModel) (bitriseModels.BitriseDataModel, error) {
+func (builder *ConfigBuilderModel) Generate(projectType string, appEnvs ...envmanModels.EnvironmentItemModel) (BitriseDataModel, error) {
 	primaryWorkflowBuilder, ok := builder.workflowBuilderMap[PrimaryWorkflowID]
 	if !ok || primaryWorkflowBuilder == nil || len(primaryWorkflowBuilder.Steps) == 0 {
-		return bitriseModels.BitriseDataModel{}, errors.New("primary workflow not defined")
+		return BitriseDataModel{}, errors.New("primary workflow not defined")
 	}
 
 	workflows := map[string]bitriseModels.WorkflowModel{}
@@ -68,22 +91,13 @@ func (builder *ConfigBuilderModel) Generate(projectType string, appEnvs ...envma
 		workflows[string(workflowID)] = workflowBuilder.generate()
 	}
 
-	triggerMap := []bitriseModels.TriggerMapItemModel{
-		bitriseModels.TriggerMapItemModel{
-			PushBranch: "*",
-			WorkflowID: string(PrimaryWorkflowID),
-		},
-		bitriseModels.TriggerMapItemModel{
-			PullRequestSourceBranch: "*",
-			WorkflowID:              string(PrimaryWorkflowID),
-		},
-	}
+	triggerMap := builder.triggerPolicy.triggerMap(workflows)
 
 	app := bitriseModels.AppModel{
 		Environments: appEnvs,
 	}
 
-	return bitriseModels.BitriseDataModel{
+	return BitriseDataModel{
 		FormatVersion:        FormatVersion,
 		DefaultStepLibSource: defaultSteplibSource,
 		ProjectType:          projectType,
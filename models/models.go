@@ -13,6 +13,9 @@ type Warnings []string
 // Errors ...
 type Errors []string
 
+// Metadata holds the detected properties of the scanned projects (like tool versions), by project path.
+type Metadata map[string]map[string]string
+
 // Icon is potential app icon.
 // The name is unique (sha256 hash of relative path converted to string plus the original extension appended).
 type Icon struct {
@@ -40,6 +43,7 @@ type ScanResultModel struct {
 	ScannerToErrors                      map[string]Errors                    `json:"errors,omitempty" yaml:"errors,omitempty"`
 	ScannerToErrorsWithRecommendations   map[string]ErrorsWithRecommendations `json:"errors_with_recommendations,omitempty" yaml:"errors_with_recommendations,omitempty"`
 	ScannerToWarningsWithRecommendations map[string]ErrorsWithRecommendations `json:"warnings_with_recommendations,omitempty" yaml:"warnings_with_recommendations,omitempty"`
+	ScannerToMetadata                    map[string]Metadata                  `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	Icons                                []Icon                               `json:"-" yaml:"-"`
 }
 
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+)
+
+// TriggerPreset is the name of a predefined trigger policy.
+type TriggerPreset string
+
+const (
+	// TriggerPresetDefault runs primary on every push and pull request.
+	TriggerPresetDefault TriggerPreset = "default"
+	// TriggerPresetGitflow runs primary on develop and ready for review pull requests, deploy on main and release branches.
+	TriggerPresetGitflow TriggerPreset = "gitflow"
+	// TriggerPresetTrunk runs primary on ready for review pull requests only, deploy on pushes to main.
+	TriggerPresetTrunk TriggerPreset = "trunk"
+	// TriggerPresetTags runs primary on every push and pull request, deploy on version tags.
+	TriggerPresetTags TriggerPreset = "tags"
+)
+
+// TriggerPresets lists the available trigger presets.
+var TriggerPresets = []TriggerPreset{TriggerPresetDefault, TriggerPresetGitflow, TriggerPresetTrunk, TriggerPresetTags}
+
+// TriggerEvent ...
+type TriggerEvent string
+
+const (
+	// PushTriggerEvent ...
+	PushTriggerEvent TriggerEvent = "push"
+	// PullRequestTriggerEvent ...
+	PullRequestTriggerEvent TriggerEvent = "pull_request"
+	// TagTriggerEvent ...
+	TagTriggerEvent TriggerEvent = "tag"
+)
+
+// TriggerRule starts the given workflow if a git event matches the pattern.
+// For pull requests the pattern is matched against the source branch.
+type TriggerRule struct {
+	Event      TriggerEvent
+	Pattern    string
+	WorkflowID WorkflowID
+	// SkipDraftPullRequests disables the pull request rule for draft pull requests.
+	SkipDraftPullRequests bool
+}
+
+// TriggerPolicy describes the trigger map of a generated config.
+// The rules are evaluated in order, the first matching rule selects the workflow.
+type TriggerPolicy struct {
+	Rules []TriggerRule
+}
+
+// ParseTriggerPreset ...
+func ParseTriggerPreset(preset string) (TriggerPreset, error) {
+	if preset == "" {
+		return TriggerPresetDefault, nil
+	}
+
+	for _, p := range TriggerPresets {
+		if strings.ToLower(preset) == string(p) {
+			return p, nil
+		}
+	}
+
+	var presets []string
+	for _, p := range TriggerPresets {
+		presets = append(presets, string(p))
+	}
+	return "", fmt.Errorf("not a valid trigger preset: %s, options: [%s]", preset, strings.Join(presets, ", "))
+}
+
+// Policy returns the trigger policy of the preset.
+func (preset TriggerPreset) Policy() TriggerPolicy {
+	switch preset {
+	case TriggerPresetGitflow:
+		return TriggerPolicy{
+			Rules: []TriggerRule{
+				{Event: PushTriggerEvent, Pattern: "main", WorkflowID: DeployWorkflowID},
+				{Event: PushTriggerEvent, Pattern: "release/*", WorkflowID: DeployWorkflowID},
+				{Event: PushTriggerEvent, Pattern: "develop", WorkflowID: PrimaryWorkflowID},
+				{Event: PullRequestTriggerEvent, Pattern: "*", WorkflowID: PrimaryWorkflowID, SkipDraftPullRequests: true},
+			},
+		}
+	case TriggerPresetTrunk:
+		return TriggerPolicy{
+			Rules: []TriggerRule{
+				{Event: PushTriggerEvent, Pattern: "main", WorkflowID: DeployWorkflowID},
+				{Event: PullRequestTriggerEvent, Pattern: "*", WorkflowID: PrimaryWorkflowID, SkipDraftPullRequests: true},
+			},
+		}
+	case TriggerPresetTags:
+		return TriggerPolicy{
+			Rules: []TriggerRule{
+				{Event: TagTriggerEvent, Pattern: "v*", WorkflowID: DeployWorkflowID},
+				{Event: PushTriggerEvent, Pattern: "*", WorkflowID: PrimaryWorkflowID},
+				{Event: PullRequestTriggerEvent, Pattern: "*", WorkflowID: PrimaryWorkflowID},
+			},
+		}
+	default:
+		return TriggerPolicy{
+			Rules: []TriggerRule{
+				{Event: PushTriggerEvent, Pattern: "*", WorkflowID: PrimaryWorkflowID},
+				{Event: PullRequestTriggerEvent, Pattern: "*", WorkflowID: PrimaryWorkflowID},
+			},
+		}
+	}
+}
+
+// TriggerMapModel ...
+type TriggerMapModel []TriggerMapItemModel
+
+// TriggerMapItemModel is a trigger map item of the bitrise package,
+// extended with the draft pull request flag, the bitrise package does not model yet.
+type TriggerMapItemModel struct {
+	bitriseModels.TriggerMapItemModel `yaml:",inline"`
+	DraftPullRequestEnabled           *bool `json:"draft_pull_request_enabled,omitempty" yaml:"draft_pull_request_enabled,omitempty"`
+}
+
+// triggerMap converts the policy into trigger map items.
+// Rules pointing to a workflow the config does not define fall back to the primary workflow,
+// so that a preset can be used with every scanner, even if the scanner generates no deploy workflow.
+func (policy TriggerPolicy) triggerMap(workflows map[string]bitriseModels.WorkflowModel) TriggerMapModel {
+	var triggerMap TriggerMapModel
+	seen := map[bitriseModels.TriggerMapItemModel]bool{}
+
+	for _, rule := range policy.Rules {
+		workflowID := string(rule.WorkflowID)
+		if _, ok := workflows[workflowID]; !ok {
+			workflowID = string(PrimaryWorkflowID)
+		}
+
+		item := bitriseModels.TriggerMapItemModel{WorkflowID: workflowID}
+		switch rule.Event {
+		case PushTriggerEvent:
+			item.PushBranch = rule.Pattern
+		case PullRequestTriggerEvent:
+			item.PullRequestSourceBranch = rule.Pattern
+		case TagTriggerEvent:
+			item.Tag = rule.Pattern
+		default:
+			continue
+		}
+
+		// the same event and pattern can not start two workflows
+		key := item
+		key.WorkflowID = ""
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		triggerMapItem := TriggerMapItemModel{TriggerMapItemModel: item}
+		if rule.SkipDraftPullRequests && rule.Event == PullRequestTriggerEvent {
+			draftPullRequestEnabled := false
+			triggerMapItem.DraftPullRequestEnabled = &draftPullRequestEnabled
+		}
+		triggerMap = append(triggerMap, triggerMapItem)
+	}
+
+	return triggerMap
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseTriggerPreset(t *testing.T) {
+	preset, err := ParseTriggerPreset("")
+	require.NoError(t, err)
+	require.Equal(t, TriggerPresetDefault, preset)
+
+	preset, err = ParseTriggerPreset("GitFlow")
+	require.NoError(t, err)
+	require.Equal(t, TriggerPresetGitflow, preset)
+
+	_, err = ParseTriggerPreset("nightly")
+	require.Error(t, err)
+}
+
+func TestGenerateTriggerMap(t *testing.T) {
+	draftPullRequestEnabled := false
+
+	tests := []struct {
+		name           string
+		preset         TriggerPreset
+		withDeploy     bool
+		wantTriggerMap TriggerMapModel
+	}{
+		{
+			name:       "default",
+			preset:     TriggerPresetDefault,
+			withDeploy: true,
+			wantTriggerMap: TriggerMapModel{
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PushBranch: "*", WorkflowID: "primary"}},
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PullRequestSourceBranch: "*", WorkflowID: "primary"}},
+			},
+		},
+		{
+			name:       "trunk",
+			preset:     TriggerPresetTrunk,
+			withDeploy: true,
+			wantTriggerMap: TriggerMapModel{
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PushBranch: "main", WorkflowID: "deploy"}},
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PullRequestSourceBranch: "*", WorkflowID: "primary"}, DraftPullRequestEnabled: &draftPullRequestEnabled},
+			},
+		},
+		{
+			name:       "tags",
+			preset:     TriggerPresetTags,
+			withDeploy: true,
+			wantTriggerMap: TriggerMapModel{
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{Tag: "v*", WorkflowID: "deploy"}},
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PushBranch: "*", WorkflowID: "primary"}},
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PullRequestSourceBranch: "*", WorkflowID: "primary"}},
+			},
+		},
+		{
+			name:       "gitflow without deploy workflow",
+			preset:     TriggerPresetGitflow,
+			withDeploy: false,
+			wantTriggerMap: TriggerMapModel{
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PushBranch: "main", WorkflowID: "primary"}},
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PushBranch: "release/*", WorkflowID: "primary"}},
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PushBranch: "develop", WorkflowID: "primary"}},
+				{TriggerMapItemModel: bitriseModels.TriggerMapItemModel{PullRequestSourceBranch: "*", WorkflowID: "primary"}, DraftPullRequestEnabled: &draftPullRequestEnabled},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewDefaultConfigBuilder(tt.preset)
+			builder.AppendStepListItemsTo(PrimaryWorkflowID, bitriseModels.StepListItemModel{"script": {}})
+			if tt.withDeploy {
+				builder.AppendStepListItemsTo(DeployWorkflowID, bitriseModels.StepListItemModel{"script": {}})
+			}
+
+			config, err := builder.Generate("test")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTriggerMap, config.TriggerMap)
+		})
+	}
+}
+
+func TestGenerateTriggerMapYML(t *testing.T) {
+	builder := NewDefaultConfigBuilder(TriggerPresetTrunk)
+	builder.AppendStepListItemsTo(PrimaryWorkflowID, bitriseModels.StepListItemModel{"script": {}})
+	config, err := builder.Generate("test")
+	require.NoError(t, err)
+
+	data, err := yaml.Marshal(config.TriggerMap)
+	require.NoError(t, err)
+	require.Equal(t, `- push_branch: main
+  workflow: primary
+- pull_request_source_branch: '*'
+  workflow: primary
+  draft_pull_request_enabled: false
+`, string(data))
+}
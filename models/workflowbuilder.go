@@ -1,10 +1,14 @@
 package models
 
-import bitriseModels "github.com/bitrise-io/bitrise/models"
+import (
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+)
 
 type workflowBuilderModel struct {
 	Steps       []bitriseModels.StepListItemModel
 	Description string
+	Envs        []envmanModels.EnvironmentItemModel
 }
 
 func newDefaultWorkflowBuilder() *workflowBuilderModel {
@@ -17,9 +21,14 @@ func (builder *workflowBuilderModel) appendStepListItems(items ...bitriseModels.
 	builder.Steps = append(builder.Steps, items...)
 }
 
+func (builder *workflowBuilderModel) appendEnvs(envs ...envmanModels.EnvironmentItemModel) {
+	builder.Envs = append(builder.Envs, envs...)
+}
+
 func (builder *workflowBuilderModel) generate() bitriseModels.WorkflowModel {
 	return bitriseModels.WorkflowModel{
-		Steps:       builder.Steps,
-		Description: builder.Description,
+		Steps:        builder.Steps,
+		Description:  builder.Description,
+		Environments: builder.Envs,
 	}
 }
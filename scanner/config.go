@@ -53,7 +53,10 @@ type scannerOutput struct {
 	options          models.OptionNode
 	configs          models.BitriseConfigMap
 	icons            models.Icons
+	metadata         models.Metadata
 	excludedScanners []string
+	// excludedDirs are the directories the excluded scanners skip, the excluded scanners are skipped entirely if empty.
+	excludedDirs []string
 }
 
 func (o *scannerOutput) AddErrors(tag string, errs ...string) {
@@ -86,8 +89,8 @@ func (o *scannerOutput) AddWarnings(tag string, errs ...string) {
 	}
 }
 
-// Config ...
-func Config(searchDir string) models.ScanResultModel {
+// Config runs the scanners in the search dir, the trigger map of the generated configs follows the trigger preset.
+func Config(searchDir string, triggerPreset models.TriggerPreset) models.ScanResultModel {
 	result := models.ScanResultModel{}
 
 	//
@@ -148,7 +151,7 @@ func Config(searchDir string) models.ScanResultModel {
 	// Collect scanner outputs, by scanner name
 	scannerToOutput := map[string]scannerOutput{}
 	{
-		projectScannerToOutputs := runScanners(scanners.ProjectScanners, searchDir)
+		projectScannerToOutputs := runScanners(scanners.ProjectScanners, searchDir, triggerPreset)
 		detectedProjectTypes := getDetectedScannerNames(projectScannerToOutputs)
 		log.Printf("Detected project types: %s", detectedProjectTypes)
 		fmt.Println()
@@ -162,7 +165,7 @@ func Config(searchDir string) models.ScanResultModel {
 			toolScanner.(scanners.AutomationToolScanner).SetDetectedProjectTypes(detectedProjectTypes)
 		}
 
-		toolScannerToOutputs := runScanners(scanners.AutomationToolScanners, searchDir)
+		toolScannerToOutputs := runScanners(scanners.AutomationToolScanners, searchDir, triggerPreset)
 		detectedAutomationToolScanners := getDetectedScannerNames(toolScannerToOutputs)
 		log.Printf("Detected automation tools: %s", detectedAutomationToolScanners)
 		fmt.Println()
@@ -182,6 +185,7 @@ func Config(searchDir string) models.ScanResultModel {
 
 	scannerToOptions := map[string]models.OptionNode{}
 	scannerToConfigMap := map[string]models.BitriseConfigMap{}
+	scannerToMetadata := map[string]models.Metadata{}
 	icons := models.Icons{}
 	for scanner, scannerOutput := range scannerToOutput {
 		// Currently the tests except an empty warning list if no warnings
@@ -199,6 +203,9 @@ func Config(searchDir string) models.ScanResultModel {
 		if len(scannerOutput.configs) > 0 && scannerOutput.status == detected {
 			scannerToOptions[scanner] = scannerOutput.options
 			scannerToConfigMap[scanner] = scannerOutput.configs
+			if len(scannerOutput.metadata) > 0 {
+				scannerToMetadata[scanner] = scannerOutput.metadata
+			}
 		}
 		icons = append(icons, scannerOutput.icons...)
 	}
@@ -209,13 +216,15 @@ func Config(searchDir string) models.ScanResultModel {
 		ScannerToErrors:                      scannerToErrors,
 		ScannerToErrorsWithRecommendations:   scannerToErrorsWithRecommendations,
 		ScannerToWarningsWithRecommendations: scannerToWarningsWithRecommendation,
+		ScannerToMetadata:                    scannerToMetadata,
 		Icons:                                icons,
 	}
 }
 
-func runScanners(scannerList []scanners.ScannerInterface, searchDir string) map[string]scannerOutput {
+func runScanners(scannerList []scanners.ScannerInterface, searchDir string, triggerPreset models.TriggerPreset) map[string]scannerOutput {
 	scannerOutputs := map[string]scannerOutput{}
 	var excludedScannerNames []string
+	excludedDirsByScanner := map[string][]string{}
 	for _, scanner := range scannerList {
 		log.TInfof("Scanner: %s", colorstring.Blue(scanner.Name()))
 		if sliceutil.IsStringInSlice(scanner.Name(), excludedScannerNames) {
@@ -223,22 +232,46 @@ func runScanners(scannerList []scanners.ScannerInterface, searchDir string) map[
 			fmt.Println()
 			continue
 		}
+		if excluder, ok := scanner.(scanners.DirExcluder); ok {
+			excludedDirs := excludedDirsByScanner[scanner.Name()]
+			if len(excludedDirs) > 0 {
+				log.TWarnf("scanner skips the directories: %v", excludedDirs)
+			}
+			excluder.ExcludeDirs(excludedDirs)
+		}
 
 		log.TPrintf("+------------------------------------------------------------------------------+")
 		log.TPrintf("|                                                                              |")
-		scannerOutput := runScanner(scanner, searchDir)
+		scannerOutput := runScanner(scanner, searchDir, triggerPreset)
 		log.TPrintf("|                                                                              |")
 		log.TPrintf("+------------------------------------------------------------------------------+")
 		fmt.Println()
 
 		scannerOutputs[scanner.Name()] = scannerOutput
-		excludedScannerNames = append(excludedScannerNames, scannerOutput.excludedScanners...)
+		for _, name := range scannerOutput.excludedScanners {
+			if len(scannerOutput.excludedDirs) > 0 && isDirExcluder(scannerList, name) {
+				excludedDirsByScanner[name] = append(excludedDirsByScanner[name], scannerOutput.excludedDirs...)
+			} else {
+				excludedScannerNames = append(excludedScannerNames, name)
+			}
+		}
 	}
 	return scannerOutputs
 }
 
+// isDirExcluder returns true if the named scanner can skip the projects in some directories.
+func isDirExcluder(scannerList []scanners.ScannerInterface, name string) bool {
+	for _, scanner := range scannerList {
+		if scanner.Name() == name {
+			_, ok := scanner.(scanners.DirExcluder)
+			return ok
+		}
+	}
+	return false
+}
+
 // Collect output of a specific scanner
-func runScanner(detector scanners.ScannerInterface, searchDir string) scannerOutput {
+func runScanner(detector scanners.ScannerInterface, searchDir string, triggerPreset models.TriggerPreset) scannerOutput {
 	output := scannerOutput{}
 
 	if isDetect, err := detector.DetectPlatform(searchDir); err != nil {
@@ -275,7 +308,7 @@ func runScanner(detector scanners.ScannerInterface, searchDir string) scannerOut
 	}
 
 	// Generate configs
-	configs, err := detector.Configs()
+	configs, err := detector.Configs(triggerPreset)
 	if err != nil {
 		data := detectorErrorData(detector.Name(), err)
 		analytics.LogError(configsFailedTag, data, "%s detector Configs failed", detector.Name())
@@ -287,16 +320,41 @@ func runScanner(detector scanners.ScannerInterface, searchDir string) scannerOut
 		return output
 	}
 
+	if violations := validateConfigs(options, configs); len(violations) > 0 {
+		for _, violation := range violations {
+			data := detectorErrorData(detector.Name(), errors.New(violation))
+			analytics.LogError(configsFailedTag, data, "%s detector generated an invalid config", detector.Name())
+
+			log.TErrorf("Invalid config: %s", violation)
+		}
+
+		output.status = detectedWithErrors
+		output.AddErrors(configsFailedTag, violations...)
+		return output
+	}
+
 	scannerExcludedScanners := detector.ExcludedScannerNames()
+	var scannerExcludedDirs []string
+	if provider, ok := detector.(scanners.ExcludedDirsProvider); ok {
+		scannerExcludedDirs = provider.ExcludedDirs()
+	}
 	if len(scannerExcludedScanners) > 0 {
-		log.TWarnf("Scanner will exclude scanners: %v", scannerExcludedScanners)
+		if len(scannerExcludedDirs) > 0 {
+			log.TWarnf("Scanner will exclude scanners: %v from the directories: %v", scannerExcludedScanners, scannerExcludedDirs)
+		} else {
+			log.TWarnf("Scanner will exclude scanners: %v", scannerExcludedScanners)
+		}
 	}
 
 	output.status = detected
 	output.options = options
 	output.configs = configs
 	output.icons = icons
+	if provider, ok := detector.(scanners.MetadataProvider); ok {
+		output.metadata = provider.Metadata()
+	}
 	output.excludedScanners = scannerExcludedScanners
+	output.excludedDirs = scannerExcludedDirs
 	return output
 }
 
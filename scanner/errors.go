@@ -88,7 +88,7 @@ func newOptionsFailedMatcher() *errormapper.PatternErrorMatcher {
 			`No Gradle Wrapper \(gradlew\) found\.`:                                                                                 newGradlewNotFoundDetail,
 			`app\.json file \((.+)\) missing or empty (.+) entry\nThe app\.json file needs to contain:`:                             newAppJSONIssueDetail,
 			`app\.json file \((.+)\) missing or empty (.+) entry\nIf the project uses Expo Kit the app.json file needs to contain:`: newExpoAppJSONIssueDetail,
-			`Cordova config.xml not found.`:                                                                                         newIonicCapacitorNotSupportedIssueDetail,
+			`Cordova config.xml not found.`:                                                                                         newIonicCordovaConfigNotFoundIssueDetail,
 		},
 	)
 }
@@ -125,9 +125,9 @@ func newExpoAppJSONIssueDetail(errorMsg string, params ...string) errormapper.De
 	}
 }
 
-func newIonicCapacitorNotSupportedIssueDetail(errorMsg string, params ...string) errormapper.DetailedError {
+func newIonicCordovaConfigNotFoundIssueDetail(errorMsg string, params ...string) errormapper.DetailedError {
 	return errormapper.DetailedError{
 		Title:       "We couldn’t find your cordova.xml file.",
-		Description: `Our auto-configurator only supports Ionic projects with Cordova at the moment. If you’re trying to add a project with Ionic Capacitor, or something else, some Steps in your automatically generated Workflow might fail. To fix this, replace the failing Steps with script Steps in the Workflow editor later.`,
+		Description: `Our auto-configurator supports Ionic projects with Cordova or Capacitor. Capacitor projects are detected by the capacitor.config.json (or capacitor.config.ts) file and the @capacitor/* dependencies of the package.json file, make sure they are committed to your repository. If you’re trying to add a project with something else, some Steps in your automatically generated Workflow might fail. To fix this, replace the failing Steps with script Steps in the Workflow editor later.`,
 	}
 }
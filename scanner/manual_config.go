@@ -2,13 +2,14 @@ package scanner
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners"
 )
 
-// ManualConfig ...
-func ManualConfig() (models.ScanResultModel, error) {
+// ManualConfig returns the default configs of every scanner, the trigger map of the configs follows the trigger preset.
+func ManualConfig(triggerPreset models.TriggerPreset) (models.ScanResultModel, error) {
 	scannerList := append(scanners.ProjectScanners, scanners.AutomationToolScanners...)
 	scannerToOptionRoot := map[string]models.OptionNode{}
 	scannerToBitriseConfigMap := map[string]models.BitriseConfigMap{}
@@ -18,14 +19,17 @@ func ManualConfig() (models.ScanResultModel, error) {
 
 		scannerToOptionRoot[scanner.Name()] = options
 
-		configs, err := scanner.DefaultConfigs()
+		configs, err := scanner.DefaultConfigs(triggerPreset)
 		if err != nil {
 			return models.ScanResultModel{}, fmt.Errorf("Failed create default configs, error: %s", err)
 		}
+		if violations := validateConfigs(options, configs); len(violations) > 0 {
+			return models.ScanResultModel{}, fmt.Errorf("Invalid %s default configs:\n%s", scanner.Name(), strings.Join(violations, "\n"))
+		}
 		scannerToBitriseConfigMap[scanner.Name()] = configs
 	}
 
-	customConfig, err := scanners.CustomConfig()
+	customConfig, err := scanners.CustomConfig(triggerPreset)
 	if err != nil {
 		return models.ScanResultModel{}, fmt.Errorf("Failed create default custom configs, error: %s", err)
 	}
@@ -17,8 +17,8 @@ import (
 )
 
 // GenerateScanResult runs the scanner, returns the results and if any platform was detected.
-func GenerateScanResult(searchDir string) (models.ScanResultModel, bool) {
-	scanResult := Config(searchDir)
+func GenerateScanResult(searchDir string, triggerPreset models.TriggerPreset) (models.ScanResultModel, bool) {
+	scanResult := Config(searchDir, triggerPreset)
 
 	var platforms []string
 	for platform := range scanResult.ScannerToOptionRoot {
@@ -42,8 +42,8 @@ func GenerateScanResult(searchDir string) (models.ScanResultModel, bool) {
 }
 
 // GenerateAndWriteResults runs the scanner and saves results to the given output dir.
-func GenerateAndWriteResults(searchDir string, outputDir string, format output.Format) (models.ScanResultModel, error) {
-	result, detected := GenerateScanResult(searchDir)
+func GenerateAndWriteResults(searchDir string, outputDir string, format output.Format, triggerPreset models.TriggerPreset) (models.ScanResultModel, error) {
+	result, detected := GenerateScanResult(searchDir, triggerPreset)
 
 	// Write output to files
 	log.TInfof("Saving outputs:")
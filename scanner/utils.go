@@ -211,6 +211,13 @@ func AskForConfig(scanResult models.ScanResultModel) (bitriseModels.BitriseDataM
 	}
 
 	config.App.Environments = append(config.App.Environments, appEnvs...)
+
+	if err := config.Normalize(); err != nil {
+		return bitriseModels.BitriseDataModel{}, fmt.Errorf("failed to normalize config, error: %s", err)
+	}
+	if violations := validateConfig(config, nil); len(violations) > 0 {
+		return bitriseModels.BitriseDataModel{}, fmt.Errorf("invalid config:\n%s", strings.Join(violations, "\n"))
+	}
 	// ---
 
 	return config, nil
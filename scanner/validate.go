@@ -0,0 +1,242 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"gopkg.in/yaml.v2"
+)
+
+// builtinEnvKeys are provided by the Bitrise CLI or the build VM,
+// step inputs may reference them without a matching option or app env.
+var builtinEnvKeys = []string{
+	"BITRISE_SOURCE_DIR",
+	"BITRISE_DEPLOY_DIR",
+	"BITRISE_BUILD_NUMBER",
+	"BITRISE_TRIGGERED_WORKFLOW_ID",
+	"BITRISE_GIT_BRANCH",
+	"BITRISE_GIT_TAG",
+	"BITRISE_GIT_COMMIT",
+	"BITRISE_PULL_REQUEST",
+	"HOME",
+	"PATH",
+	"PWD",
+}
+
+// stepOutputEnvKeys are exported by the steps of the generated workflows,
+// later steps of the workflow may reference them.
+// The scanner works offline, without the step.yml of the steps, so the outputs can not be derived
+// and the list is kept by hand: it holds the outputs of the steps in the steps package.
+// An output missing from the list is reported as a violation, when a single line input references it.
+var stepOutputEnvKeys = []string{
+	// android-build, android-build-for-ui-testing
+	"BITRISE_APK_PATH",
+	"BITRISE_APK_PATH_LIST",
+	"BITRISE_AAB_PATH",
+	"BITRISE_AAB_PATH_LIST",
+	"BITRISE_MAPPING_PATH",
+	"BITRISE_TEST_APK_PATH",
+	// xcode-archive, xcode-archive-mac, export-xcarchive
+	"BITRISE_IPA_PATH",
+	"BITRISE_DSYM_PATH",
+	"BITRISE_XCARCHIVE_PATH",
+	"BITRISE_EXPORTED_FILE_PATH",
+	// xcode-build-for-simulator
+	"BITRISE_APP_DIR_PATH",
+	// xcode-test, xcode-test-mac
+	"BITRISE_XCRESULT_PATH",
+}
+
+var envReferencePattern = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)\}?`)
+
+// configEnvKeys returns the env keys set by the options leading to each config.
+// If more than one path leads to the same config, only the env keys set by every path are returned.
+func configEnvKeys(options models.OptionNode) map[string][]string {
+	configToEnvKeys := map[string][]string{}
+
+	var walk func(opt *models.OptionNode, envKeys []string)
+	walk = func(opt *models.OptionNode, envKeys []string) {
+		if opt == nil {
+			return
+		}
+
+		if opt.IsConfigOption() {
+			current, ok := configToEnvKeys[opt.Config]
+			if !ok {
+				configToEnvKeys[opt.Config] = envKeys
+				return
+			}
+
+			var common []string
+			for _, key := range current {
+				if sliceutil.IsStringInSlice(key, envKeys) {
+					common = append(common, key)
+				}
+			}
+			configToEnvKeys[opt.Config] = common
+			return
+		}
+
+		if opt.EnvKey != "" {
+			envKeys = append(append([]string{}, envKeys...), opt.EnvKey)
+		}
+		for _, child := range opt.ChildOptionMap {
+			walk(child, envKeys)
+		}
+	}
+	walk(&options, nil)
+
+	return configToEnvKeys
+}
+
+// validateConfigs validates every config of a scanner against the env keys provided by its options.
+func validateConfigs(options models.OptionNode, configs models.BitriseConfigMap) []string {
+	configToEnvKeys := configEnvKeys(options)
+
+	var names []string
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []string
+	for _, name := range names {
+		envKeys, ok := configToEnvKeys[name]
+		if !ok {
+			// the config can not be selected, the env keys it requires are unknown
+			continue
+		}
+
+		for _, violation := range validateConfigContent(configs[name], envKeys) {
+			violations = append(violations, fmt.Sprintf("config (%s): %s", name, violation))
+		}
+	}
+	return violations
+}
+
+// validateConfigContent checks a bitrise.yml template, envKeys are the env keys filled by the selected options.
+func validateConfigContent(content string, envKeys []string) []string {
+	var config bitriseModels.BitriseDataModel
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return []string{fmt.Sprintf("invalid yml: %s", err)}
+	}
+
+	violations := validateDuplicatedWorkflows(content)
+
+	if err := config.Normalize(); err != nil {
+		return append(violations, fmt.Sprintf("failed to normalize: %s", err))
+	}
+
+	return append(violations, validateConfig(config, envKeys)...)
+}
+
+// validateConfig checks a bitrise config model, envKeys are the env keys filled by the selected options.
+func validateConfig(config bitriseModels.BitriseDataModel, envKeys []string) []string {
+	var violations []string
+
+	if config.FormatVersion != models.FormatVersion {
+		violations = append(violations, fmt.Sprintf("format_version (%s) does not match the supported version (%s)", config.FormatVersion, models.FormatVersion))
+	}
+
+	missingWorkflow := false
+	for _, item := range config.TriggerMap {
+		if _, ok := config.Workflows[item.WorkflowID]; !ok {
+			missingWorkflow = true
+			violations = append(violations, fmt.Sprintf("workflow (%s) defined in trigger item (%s), but does not exist", item.WorkflowID, item.String(true)))
+		}
+	}
+
+	// Validate stops at the first issue, which would be the already reported missing workflow
+	if !missingWorkflow {
+		if _, err := config.Validate(); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	providedEnvKeys := append(append(append([]string{}, builtinEnvKeys...), stepOutputEnvKeys...), envKeys...)
+	for _, env := range config.App.Environments {
+		if key, _, err := env.GetKeyValuePair(); err == nil {
+			providedEnvKeys = append(providedEnvKeys, key)
+		}
+	}
+
+	var workflowIDs []string
+	for workflowID := range config.Workflows {
+		workflowIDs = append(workflowIDs, workflowID)
+	}
+	sort.Strings(workflowIDs)
+
+	for _, workflowID := range workflowIDs {
+		workflow := config.Workflows[workflowID]
+
+		workflowEnvKeys := providedEnvKeys
+		for _, env := range workflow.Environments {
+			if key, _, err := env.GetKeyValuePair(); err == nil {
+				workflowEnvKeys = append(workflowEnvKeys, key)
+			}
+		}
+
+		for _, stepListItem := range workflow.Steps {
+			stepID, step, err := bitriseModels.GetStepIDStepDataPair(stepListItem)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("workflow (%s): %s", workflowID, err))
+				continue
+			}
+
+			for _, input := range step.Inputs {
+				key, value, err := input.GetKeyValuePair()
+				if err != nil {
+					violations = append(violations, fmt.Sprintf("workflow (%s), step (%s): %s", workflowID, stepID, err))
+					continue
+				}
+
+				for _, envKey := range undefinedEnvReferences(value, workflowEnvKeys) {
+					violations = append(violations, fmt.Sprintf("workflow (%s), step (%s), input (%s): env ($%s) is not provided by any option or app env", workflowID, stepID, key, envKey))
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+func undefinedEnvReferences(value string, envKeys []string) []string {
+	// multiline values are scripts, which may reference their own variables
+	if strings.Contains(value, "\n") {
+		return nil
+	}
+
+	var undefined []string
+	for _, match := range envReferencePattern.FindAllStringSubmatch(value, -1) {
+		if !sliceutil.IsStringInSlice(match[1], envKeys) && !sliceutil.IsStringInSlice(match[1], undefined) {
+			undefined = append(undefined, match[1])
+		}
+	}
+	return undefined
+}
+
+// validateDuplicatedWorkflows looks for workflow IDs defined more than once,
+// unmarshalling the config into a map would silently keep only one of them.
+func validateDuplicatedWorkflows(content string) []string {
+	var config struct {
+		Workflows yaml.MapSlice `yaml:"workflows"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return nil
+	}
+
+	var violations []string
+	seen := map[interface{}]bool{}
+	for _, item := range config.Workflows {
+		if seen[item.Key] {
+			violations = append(violations, fmt.Sprintf("workflow (%v) defined more than once", item.Key))
+		}
+		seen[item.Key] = true
+	}
+	return violations
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+)
+
+const validConfig = `format_version: "` + models.FormatVersion + `"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+project_type: ios
+trigger_map:
+- push_branch: '*'
+  workflow: primary
+workflows:
+  primary:
+    steps:
+    - xcode-test@2:
+        inputs:
+        - project_path: $BITRISE_PROJECT_PATH
+        - scheme: $BITRISE_SCHEME
+    - deploy-to-bitrise-io@1:
+        inputs:
+        - deploy_path: $BITRISE_DEPLOY_DIR
+`
+
+const invalidConfig = `format_version: "1"
+project_type: ios
+trigger_map:
+- push_branch: '*'
+  workflow: deploy
+workflows:
+  primary:
+    steps:
+    - script@1: {}
+  primary:
+    steps:
+    - xcode-test@2:
+        inputs:
+        - project_path: $BITRISE_PROJECT_PATH
+        - scheme: ${BITRISE_SCHEME}
+`
+
+func TestValidateConfigs(t *testing.T) {
+	projectPathOption := models.NewOption("Project", "", "BITRISE_PROJECT_PATH", models.TypeSelector)
+	schemeOption := models.NewOption("Scheme", "", "BITRISE_SCHEME", models.TypeSelector)
+	projectPathOption.AddOption("ios.xcodeproj", schemeOption)
+	schemeOption.AddConfig("ios", models.NewConfigOption("valid-config", nil))
+
+	otherSchemeOption := models.NewOption("Scheme", "", "", models.TypeUserInput)
+	projectPathOption.AddOption("other.xcodeproj", otherSchemeOption)
+	otherSchemeOption.AddConfig("", models.NewConfigOption("invalid-config", nil))
+
+	violations := validateConfigs(*projectPathOption, models.BitriseConfigMap{
+		"valid-config":   validConfig,
+		"invalid-config": invalidConfig,
+	})
+	require.Equal(t, []string{
+		"config (invalid-config): workflow (primary) defined more than once",
+		"config (invalid-config): format_version (1) does not match the supported version (" + models.FormatVersion + ")",
+		"config (invalid-config): workflow (deploy) defined in trigger item (push_branch: * -> workflow: deploy), but does not exist",
+		"config (invalid-config): workflow (primary), step (xcode-test@2), input (scheme): env ($BITRISE_SCHEME) is not provided by any option or app env",
+	}, violations)
+}
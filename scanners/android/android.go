@@ -3,11 +3,14 @@ package android
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/bitrise-io/bitrise-init/analytics"
 	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/log"
 )
 
 // Scanner ...
@@ -16,6 +19,12 @@ type Scanner struct {
 	ProjectRoots   []string
 	ExcludeTest    bool
 	ExcludeAppIcon bool
+
+	// configDescriptors holds the descriptors of the configs offered in the options, by config name.
+	configDescriptors map[string]configDescriptor
+	metadata          models.Metadata
+	// excludedDirs are the directories of the projects scanned by an other scanner, relative to the SearchDir.
+	excludedDirs []string
 }
 
 // NewScanner ...
@@ -42,12 +51,26 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 		{"settings.gradle", "settings.gradle.kts"},
 	}
 	skipDirs := []string{".git", "CordovaLib", "node_modules"}
-	scanner.ProjectRoots, err = walkMultipleFileGroups(searchDir, projectFiles, skipDirs)
+	projectRoots, err := walkMultipleFileGroups(searchDir, projectFiles, skipDirs)
 	if err != nil {
 		return false, fmt.Errorf("failed to search for build.gradle files, error: %s", err)
 	}
 
-	return len(scanner.ProjectRoots) > 0, err
+	scanner.ProjectRoots = nil
+	for _, projectRoot := range projectRoots {
+		if relProjectRoot, err := filepath.Rel(searchDir, projectRoot); err == nil && utility.IsInDirs(relProjectRoot, scanner.excludedDirs) {
+			log.TPrintf("Skipping the project scanned by an other scanner: %s", relProjectRoot)
+			continue
+		}
+		scanner.ProjectRoots = append(scanner.ProjectRoots, projectRoot)
+	}
+
+	return len(scanner.ProjectRoots) > 0, nil
+}
+
+// ExcludeDirs ...
+func (scanner *Scanner) ExcludeDirs(dirs []string) {
+	scanner.excludedDirs = dirs
 }
 
 // Options ...
@@ -56,6 +79,9 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	warnings := models.Warnings{}
 	appIconsAllProjects := models.Icons{}
 
+	scanner.configDescriptors = map[string]configDescriptor{}
+	scanner.metadata = models.Metadata{}
+
 	foundOptions := false
 	var lastErr error = nil
 	for _, projectRoot := range scanner.ProjectRoots {
@@ -90,13 +116,80 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 			iconIDs[i] = icon.Filename
 		}
 
-		configOption := models.NewConfigOption(ConfigName, iconIDs)
-		moduleOption := models.NewOption(ModuleInputTitle, ModuleInputSummary, ModuleInputEnvKey, models.TypeUserInput)
-		variantOption := models.NewOption(VariantInputTitle, VariantInputSummary, VariantInputEnvKey, models.TypeOptionalUserInput)
+		jdkVersion := ""
+		if requirement, err := detectJDKRequirement(projectRoot); err != nil {
+			log.TWarnf("Failed to detect the required JDK version in %s, error: %s", projectRoot, err)
+		} else {
+			jdkVersion = requirement.JDKVersion()
+			log.TPrintf("Gradle: %s, Android Gradle Plugin: %s, required JDK: %s", requirement.GradleVersion, requirement.AGPVersion, jdkVersion)
+			if warning := requirement.Warning(); warning != "" {
+				warnings = append(warnings, warning)
+			}
+			if metadata := requirement.Metadata(); len(metadata) > 0 {
+				scanner.metadata[relProjectRoot] = metadata
+			}
+		}
 
+		if keystores, err := findCommittedKeystores(projectRoot); err != nil {
+			log.TWarnf("Failed to search for keystore files in %s, error: %s", projectRoot, err)
+		} else if len(keystores) > 0 {
+			for i, keystore := range keystores {
+				if rel, err := filepath.Rel(scanner.SearchDir, keystore); err == nil {
+					keystores[i] = rel
+				}
+			}
+			warnings = append(warnings, committedKeystoreWarning(keystores))
+		}
+
+		modules, err := detectApplicationModules(projectRoot)
+		if err != nil {
+			log.TWarnf("Failed to detect application modules in %s, error: %s", projectRoot, err)
+		}
+		moduleOptionType := models.TypeSelector
+		if len(modules) == 0 {
+			log.TWarnf("No application module found in %s, falling back to the default module: %s", projectRoot, defaultModule)
+			modules = []gradleModule{{Name: defaultModule}}
+			if buildScriptPth, buildScript, err := readFirstExisting(filepath.Join(projectRoot, defaultModule), "build.gradle", "build.gradle.kts"); err == nil {
+				modules[0].BuildScriptPath, modules[0].BuildScript = buildScriptPth, buildScript
+			}
+			// the default module is only a guess, the actual module can be typed in
+			moduleOptionType = models.TypeUserInput
+		}
+
+		moduleOption := models.NewOption(ModuleInputTitle, ModuleInputSummary, ModuleInputEnvKey, moduleOptionType)
 		projectLocationOption.AddOption(relProjectRoot, moduleOption)
-		moduleOption.AddOption("app", variantOption)
-		variantOption.AddConfig("", configOption)
+
+		for _, module := range modules {
+			descriptor := configDescriptor{jdkVersion: jdkVersion, signing: detectSigningSetup(module.BuildScript)}
+			if descriptor.signing.SelfSigned {
+				log.TPrintf("The release build of module %s is signed with credentials from the environment variables: %s", module.Name, strings.Join(descriptor.signing.EnvVars, ", "))
+			}
+			tests, err := detectInstrumentedTests(module)
+			if err != nil {
+				log.TWarnf("Failed to detect instrumented tests of module %s, error: %s", module.Name, err)
+			} else if tests != nil {
+				log.TPrintf("Module %s has instrumented tests (%s)", module.Name, tests)
+				descriptor.instrumentedTests = tests
+			}
+			configName := descriptor.configName()
+			scanner.configDescriptors[configName] = descriptor
+
+			if module.BuildScriptPath == "" {
+				variantOption := models.NewOption(VariantInputTitle, VariantInputSummary, VariantInputEnvKey, models.TypeOptionalUserInput)
+				moduleOption.AddOption(module.Name, variantOption)
+				variantOption.AddConfig("", models.NewConfigOption(configName, iconIDs))
+				continue
+			}
+
+			variants := parseModuleVariants(module.BuildScript).Variants()
+			log.TPrintf("Variants of module %s: %s", module.Name, strings.Join(variants, ", "))
+
+			variantOption := models.NewOption(VariantInputTitle, VariantInputSummary, VariantInputEnvKey, models.TypeOptionalSelector)
+			moduleOption.AddOption(module.Name, variantOption)
+			for _, variant := range variants {
+				variantOption.AddConfig(variant, models.NewConfigOption(configName, iconIDs))
+			}
+		}
 		foundOptions = true
 	}
 	if !foundOptions && lastErr != nil {
@@ -121,27 +214,45 @@ func (scanner *Scanner) DefaultOptions() models.OptionNode {
 }
 
 // Configs ...
-func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	configBuilder := scanner.generateConfigBuilder()
-
-	config, err := configBuilder.Generate(ScannerName)
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	caches, err := utility.DetectDependencyCaches(scanner.SearchDir, scanner.ProjectRoots, utility.GradleDependencyManager)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
+	descriptors := scanner.configDescriptors
+	if len(descriptors) == 0 {
+		descriptors = map[string]configDescriptor{ConfigName: {}}
 	}
 
-	return models.BitriseConfigMap{
-		ConfigName: string(data),
-	}, nil
+	configs := models.BitriseConfigMap{}
+	for configName, descriptor := range descriptors {
+		configBuilder := scanner.generateConfigBuilder(descriptor, triggerPreset, caches...)
+
+		config, err := configBuilder.Generate(ScannerName)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+
+		configs[configName] = string(data)
+	}
+
+	return configs, nil
+}
+
+// Metadata ...
+func (scanner *Scanner) Metadata() models.Metadata {
+	return scanner.metadata
 }
 
 // DefaultConfigs ...
-func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := scanner.generateConfigBuilder()
+func (scanner *Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configBuilder := scanner.generateConfigBuilder(configDescriptor{}, triggerPreset)
 
 	config, err := configBuilder.Generate(ScannerName)
 	if err != nil {
@@ -0,0 +1,154 @@
+package android
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/steps"
+	envmanModels "github.com/bitrise-io/envman/models"
+)
+
+const defaultTestInstrumentationRunner = "androidx.test.runner.AndroidJUnitRunner"
+
+const instrumentedTestWorkflowDescription = `Builds the app and its instrumented tests (src/androidTest), and runs the tests on a virtual device.`
+
+var (
+	// testBuildType "staging" / testBuildType = "staging"
+	testBuildTypePattern = regexp.MustCompile(`\btestBuildType\s*(?:=\s*)?\(?\s*["']([^"']+)["']`)
+	// testInstrumentationRunner "androidx.test.runner.AndroidJUnitRunner" / testInstrumentationRunner = "com.example.HiltTestRunner"
+	testInstrumentationRunnerPattern = regexp.MustCompile(`\btestInstrumentationRunner\s*(?:=\s*)?\(?\s*["']([^"']+)["']`)
+	// execution 'ANDROIDX_TEST_ORCHESTRATOR' / execution = "ANDROIDX_TEST_ORCHESTRATOR"
+	orchestratorPattern = regexp.MustCompile(`\bANDROIDX?_TEST_ORCHESTRATOR\b`)
+	// testOptions { managedDevices { devices { ... } } }
+	managedDevicesPattern = regexp.MustCompile(`\bmanagedDevices\s*\{`)
+)
+
+// instrumentedTests describes the instrumented tests (androidTest source sets) of a module.
+type instrumentedTests struct {
+	// Variant is the variant the test APK is built for: the test build type of the first product flavor (combination).
+	Variant        string
+	Runner         string
+	Orchestrator   bool
+	ManagedDevices bool
+}
+
+// detectInstrumentedTests returns the instrumented test configuration of the module,
+// or nil if the module has no instrumented test sources.
+func detectInstrumentedTests(module gradleModule) (*instrumentedTests, error) {
+	if module.BuildScriptPath == "" {
+		return nil, nil
+	}
+
+	sourceSets, err := filepath.Glob(filepath.Join(filepath.Dir(module.BuildScriptPath), "src", "androidTest*"))
+	if err != nil {
+		return nil, err
+	}
+
+	hasSources := false
+	for _, sourceSet := range sourceSets {
+		if info, err := os.Stat(sourceSet); err != nil {
+			return nil, err
+		} else if info.IsDir() {
+			hasSources = true
+			break
+		}
+	}
+	if !hasSources {
+		return nil, nil
+	}
+
+	buildScript := stripComments(module.BuildScript)
+	testBuildType := debugBuildType
+	if match := testBuildTypePattern.FindStringSubmatch(buildScript); match != nil {
+		testBuildType = match[1]
+	}
+	variants := parseModuleVariants(buildScript)
+	variants.BuildTypes = []string{testBuildType}
+
+	tests := instrumentedTests{
+		Variant:        variants.Variants()[0],
+		Runner:         defaultTestInstrumentationRunner,
+		Orchestrator:   orchestratorPattern.MatchString(buildScript),
+		ManagedDevices: managedDevicesPattern.MatchString(buildScript),
+	}
+	if match := testInstrumentationRunnerPattern.FindStringSubmatch(buildScript); match != nil {
+		tests.Runner = match[1]
+	}
+
+	return &tests, nil
+}
+
+// configName returns the name of the config, which runs the instrumented tests:
+// Gradle Managed Devices are driven by Gradle,
+// tests requiring the Orchestrator or a custom runner are run by the connected test task (which knows the runner setup),
+// other tests are run by the android-instrumented-test step on the test APK built for UI testing.
+func (tests instrumentedTests) configName() string {
+	if tests.ManagedDevices {
+		return ManagedDeviceTestConfigName
+	}
+	if tests.Orchestrator || tests.Runner != defaultTestInstrumentationRunner {
+		return ConnectedTestConfigName
+	}
+	return InstrumentedTestConfigName
+}
+
+func (tests instrumentedTests) appendWorkflow(configBuilder *models.ConfigBuilderModel, jdkVersion string, caches ...steps.DependencyCache) {
+	projectLocationEnv, gradlewPath, moduleEnv := "$"+ProjectLocationInputEnvKey, "$"+ProjectLocationInputEnvKey+"/gradlew", "$"+ModuleInputEnvKey
+	gradleTask := func(task string) string {
+		return ":" + moduleEnv + ":" + task
+	}
+
+	configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, prepareStepList(jdkVersion, caches...)...)
+	configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
+		envmanModels.EnvironmentItemModel{GradlewPathInputKey: gradlewPath},
+	))
+
+	switch tests.configName() {
+	case ManagedDeviceTestConfigName:
+		configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.GradleRunnerStepListItem(
+			envmanModels.EnvironmentItemModel{GradlewPathInputKey: gradlewPath},
+			envmanModels.EnvironmentItemModel{GradleTaskInputKey: gradleTask("allDevicesCheck")},
+		))
+	case ConnectedTestConfigName:
+		configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.AVDManagerStepListItem())
+		configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.WaitForAndroidEmulatorStepListItem())
+		configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.GradleRunnerStepListItem(
+			envmanModels.EnvironmentItemModel{GradlewPathInputKey: gradlewPath},
+			envmanModels.EnvironmentItemModel{GradleTaskInputKey: gradleTask("connectedAndroidTest")},
+		))
+	default:
+		// the emulator boots while the APKs are built,
+		// the selected variant may be a release one, which has no test APK
+		configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.AVDManagerStepListItem())
+		configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.AndroidBuildForUITestingStepListItem(
+			envmanModels.EnvironmentItemModel{ProjectLocationInputKey: projectLocationEnv},
+			envmanModels.EnvironmentItemModel{ModuleInputKey: moduleEnv},
+			envmanModels.EnvironmentItemModel{VariantInputKey: tests.Variant},
+		))
+		configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.WaitForAndroidEmulatorStepListItem())
+		configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.AndroidInstrumentedTestStepListItem())
+	}
+
+	configBuilder.AppendStepListItemsTo(InstrumentedTestWorkflowID, steps.DefaultDeployStepList(true, caches...)...)
+	configBuilder.SetWorkflowDescriptionTo(InstrumentedTestWorkflowID, instrumentedTestWorkflowDescription)
+}
+
+func (tests instrumentedTests) String() string {
+	var details []string
+	if tests.Runner != defaultTestInstrumentationRunner {
+		details = append(details, "runner: "+tests.Runner)
+	}
+	if tests.Orchestrator {
+		details = append(details, "orchestrator")
+	}
+	if tests.ManagedDevices {
+		details = append(details, "managed devices")
+	}
+	if len(details) == 0 {
+		return "default runner"
+	}
+	return strings.Join(details, ", ")
+}
@@ -0,0 +1,71 @@
+package android
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectInstrumentedTests(t *testing.T) {
+	moduleDir, err := pathutil.NormalizedOSTempDirPath("__instrumented_tests__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(moduleDir))
+	}()
+
+	module := gradleModule{
+		Name:            "app",
+		BuildScriptPath: filepath.Join(moduleDir, "build.gradle.kts"),
+		BuildScript: `android {
+    defaultConfig {
+        testInstrumentationRunner = "com.example.HiltTestRunner"
+    }
+    testOptions {
+        execution = "ANDROIDX_TEST_ORCHESTRATOR"
+    }
+}`,
+	}
+
+	tests, err := detectInstrumentedTests(module)
+	require.NoError(t, err)
+	require.Nil(t, tests)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(moduleDir, "src", "androidTestFree", "java"), 0755))
+
+	tests, err = detectInstrumentedTests(module)
+	require.NoError(t, err)
+	require.Equal(t, &instrumentedTests{Variant: "debug", Runner: "com.example.HiltTestRunner", Orchestrator: true}, tests)
+	require.Equal(t, ConnectedTestConfigName, tests.configName())
+
+	module.BuildScript = `android {
+    defaultConfig {
+        testInstrumentationRunner "androidx.test.runner.AndroidJUnitRunner"
+    }
+    testBuildType "staging"
+    flavorDimensions "tier"
+    productFlavors {
+        free { dimension "tier" }
+        paid { dimension "tier" }
+    }
+}`
+	tests, err = detectInstrumentedTests(module)
+	require.NoError(t, err)
+	require.Equal(t, "freeStaging", tests.Variant)
+	require.Equal(t, InstrumentedTestConfigName, tests.configName())
+
+	module.BuildScript = `android {
+    testOptions {
+        managedDevices {
+            devices {
+                pixel2api30(com.android.build.api.dsl.ManagedVirtualDevice) { }
+            }
+        }
+    }
+}`
+	tests, err = detectInstrumentedTests(module)
+	require.NoError(t, err)
+	require.Equal(t, ManagedDeviceTestConfigName, tests.configName())
+}
@@ -0,0 +1,287 @@
+package android
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// JDK versions selectable by the set-java-version step.
+var supportedJDKVersions = []int{8, 11, 17, 21}
+
+var (
+	// distributionUrl=https\://services.gradle.org/distributions/gradle-8.0-bin.zip
+	gradleDistributionPattern = regexp.MustCompile(`distributionUrl\s*=.*gradle-([0-9][\w.-]*?)-(?:bin|all)\.zip`)
+	// id 'com.android.application' version '8.1.0' / id("com.android.library") version "8.1.0" apply false
+	agpPluginVersionPattern = regexp.MustCompile(`\bid\s*\(?\s*["']com\.android\.(?:application|library)["']\s*\)?\s*version\s*\(?\s*["']([^"']+)["']`)
+	// classpath 'com.android.tools.build:gradle:7.4.2' / classpath("com.android.tools.build:gradle:8.1.0")
+	agpClasspathPattern = regexp.MustCompile(`["']com\.android\.tools\.build:gradle:([^"']+)["']`)
+	// agp = "8.1.0"
+	catalogVersionPattern = regexp.MustCompile(`(?m)^\s*([\w.-]+)\s*=\s*"([^"]+)"`)
+	// android-application = { id = "com.android.application", version.ref = "agp" } / { id = "com.android.application", version = "8.1.0" }
+	catalogAGPPluginPattern = regexp.MustCompile(`\{[^}]*\bid\s*=\s*"com\.android\.(?:application|library)"[^}]*\}`)
+	// android-gradle-plugin = { module = "com.android.tools.build:gradle", version.ref = "agp" } / { group = "com.android.tools.build", name = "gradle", version.ref = "agp" }
+	catalogAGPLibraryPattern = regexp.MustCompile(`\{[^}]*(?:"com\.android\.tools\.build:gradle"|group\s*=\s*"com\.android\.tools\.build"\s*,\s*name\s*=\s*"gradle")[^}]*\}`)
+	catalogVersionRefPattern = regexp.MustCompile(`\bversion\.ref\s*=\s*"([^"]+)"`)
+	catalogInlineVersion     = regexp.MustCompile(`\bversion\s*=\s*"([^"]+)"`)
+	// android-application = "com.android.application:8.1.0"
+	catalogAGPNotationPattern = regexp.MustCompile(`"com\.android\.(?:application|library):([^"]+)"`)
+	// jvmToolchain(17) / languageVersion.set(JavaLanguageVersion.of(17)) / languageVersion = JavaLanguageVersion.of("17")
+	jvmToolchainPattern = regexp.MustCompile(`\bjvmToolchain\s*\(\s*(\d+)\s*\)|JavaLanguageVersion\.of\s*\(\s*["']?(\d+)["']?\s*\)`)
+	// sourceCompatibility JavaVersion.VERSION_17 / targetCompatibility = JavaVersion.VERSION_1_8
+	javaCompatibilityPattern = regexp.MustCompile(`\b(?:source|target)Compatibility\s*(?:=\s*)?JavaVersion\.VERSION_(?:1_)?(\d+)`)
+)
+
+// jdkRequirement holds the Java related versions of an Android project.
+type jdkRequirement struct {
+	GradleVersion string
+	AGPVersion    string
+	// Toolchain is the highest Java version required by a jvmToolchain or compileOptions setting.
+	Toolchain int
+}
+
+// parseVersion returns the numeric major and minor components of a version, like 8.1.0-alpha01 -> 8, 1.
+func parseVersion(version string) (int, int, bool) {
+	components := strings.SplitN(version, ".", 3)
+	major, err := strconv.Atoi(components[0])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	minor := 0
+	if len(components) > 1 {
+		digits := components[1]
+		if idx := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); idx >= 0 {
+			digits = digits[:idx]
+		}
+		minor, _ = strconv.Atoi(digits)
+	}
+	return major, minor, true
+}
+
+func versionAtLeast(version string, major, minor int) bool {
+	vMajor, vMinor, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	return vMajor > major || (vMajor == major && vMinor >= minor)
+}
+
+// minimumJDK returns the Java version required by the Android Gradle Plugin version and the toolchain settings,
+// 0 if none of them is known.
+func (requirement jdkRequirement) minimumJDK() int {
+	jdk := 0
+	switch {
+	case requirement.AGPVersion == "":
+	case versionAtLeast(requirement.AGPVersion, 8, 0):
+		jdk = 17
+	case versionAtLeast(requirement.AGPVersion, 7, 0):
+		jdk = 11
+	default:
+		if _, _, ok := parseVersion(requirement.AGPVersion); ok {
+			jdk = 8
+		}
+	}
+
+	if requirement.Toolchain > jdk {
+		jdk = requirement.Toolchain
+	}
+	return jdk
+}
+
+// maximumJDK returns the latest Java version the Gradle version can run on, 0 if the Gradle version is unknown.
+func (requirement jdkRequirement) maximumJDK() int {
+	if _, _, ok := parseVersion(requirement.GradleVersion); !ok {
+		return 0
+	}
+
+	for _, compatibility := range []struct {
+		major, minor, jdk int
+	}{
+		{8, 5, 21},
+		{7, 3, 17},
+		{7, 0, 16},
+		{6, 7, 15},
+		{6, 3, 14},
+		{6, 0, 13},
+		{5, 4, 12},
+		{5, 0, 11},
+	} {
+		if versionAtLeast(requirement.GradleVersion, compatibility.major, compatibility.minor) {
+			return compatibility.jdk
+		}
+	}
+	return 8
+}
+
+// JDKVersion returns the set-java-version step compatible Java version, the project should be built with,
+// empty string if no requirement is detected.
+func (requirement jdkRequirement) JDKVersion() string {
+	minimum := requirement.minimumJDK()
+	if minimum == 0 {
+		return ""
+	}
+
+	for _, version := range supportedJDKVersions {
+		if version >= minimum {
+			return strconv.Itoa(version)
+		}
+	}
+	return strconv.Itoa(minimum)
+}
+
+// Warning returns a warning if the Gradle version can not run on the required Java version.
+func (requirement jdkRequirement) Warning() string {
+	minimum, maximum := requirement.minimumJDK(), requirement.maximumJDK()
+	if minimum == 0 || maximum == 0 || minimum <= maximum {
+		return ""
+	}
+	return fmt.Sprintf("Gradle %s does not support Java %d, which is required by the project (Android Gradle Plugin: %s), update the Gradle wrapper to a compatible version", requirement.GradleVersion, minimum, requirement.AGPVersion)
+}
+
+// Metadata returns the detected versions for the scan result.
+func (requirement jdkRequirement) Metadata() map[string]string {
+	metadata := map[string]string{}
+	if requirement.GradleVersion != "" {
+		metadata["gradle_version"] = requirement.GradleVersion
+	}
+	if requirement.AGPVersion != "" {
+		metadata["agp_version"] = requirement.AGPVersion
+	}
+	if jdk := requirement.JDKVersion(); jdk != "" {
+		metadata["jdk_version"] = jdk
+	}
+	return metadata
+}
+
+func parseGradleWrapperVersion(properties string) string {
+	if match := gradleDistributionPattern.FindStringSubmatch(properties); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func parseAGPVersion(buildScript string) string {
+	buildScript = stripComments(buildScript)
+	if match := agpPluginVersionPattern.FindStringSubmatch(buildScript); match != nil {
+		return match[1]
+	}
+	if match := agpClasspathPattern.FindStringSubmatch(buildScript); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func parseVersionCatalogAGPVersion(catalog string) string {
+	catalog = tomlCommentPattern.ReplaceAllString(catalog, "")
+
+	versions := map[string]string{}
+	sections := catalogSectionPattern.FindAllStringSubmatchIndex(catalog, -1)
+	for i, section := range sections {
+		if catalog[section[2]:section[3]] != "versions" {
+			continue
+		}
+		end := len(catalog)
+		if i+1 < len(sections) {
+			end = sections[i+1][0]
+		}
+		for _, match := range catalogVersionPattern.FindAllStringSubmatch(catalog[section[1]:end], -1) {
+			versions[match[1]] = match[2]
+		}
+	}
+
+	for _, pattern := range []*regexp.Regexp{catalogAGPPluginPattern, catalogAGPLibraryPattern} {
+		for _, declaration := range pattern.FindAllString(catalog, -1) {
+			if match := catalogVersionRefPattern.FindStringSubmatch(declaration); match != nil {
+				if version, ok := versions[match[1]]; ok {
+					return version
+				}
+			}
+			if match := catalogInlineVersion.FindStringSubmatch(declaration); match != nil {
+				return match[1]
+			}
+		}
+	}
+
+	if match := catalogAGPNotationPattern.FindStringSubmatch(catalog); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func parseToolchainVersion(buildScript string) int {
+	buildScript = stripComments(buildScript)
+
+	version := 0
+	for _, match := range jvmToolchainPattern.FindAllStringSubmatch(buildScript, -1) {
+		for _, group := range match[1:] {
+			if v, err := strconv.Atoi(group); err == nil && v > version {
+				version = v
+			}
+		}
+	}
+	for _, match := range javaCompatibilityPattern.FindAllStringSubmatch(buildScript, -1) {
+		if v, err := strconv.Atoi(match[1]); err == nil && v > version {
+			version = v
+		}
+	}
+	return version
+}
+
+// detectJDKRequirement collects the Gradle, Android Gradle Plugin and toolchain versions of the project.
+func detectJDKRequirement(projectRoot string) (jdkRequirement, error) {
+	var requirement jdkRequirement
+
+	_, properties, err := readFirstExisting(filepath.Join(projectRoot, "gradle", "wrapper"), "gradle-wrapper.properties")
+	if err != nil {
+		return jdkRequirement{}, err
+	}
+	requirement.GradleVersion = parseGradleWrapperVersion(properties)
+
+	var buildScripts []string
+	if err := filepath.Walk(projectRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != projectRoot && nameMatchSkipDirs(info.Name(), []string{"build", ".gradle", ".git", "node_modules", "buildSrc"}) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if name := info.Name(); name == "build.gradle" || name == "build.gradle.kts" || name == "settings.gradle" || name == "settings.gradle.kts" {
+			buildScripts = append(buildScripts, path)
+		}
+		return nil
+	}); err != nil {
+		return jdkRequirement{}, err
+	}
+
+	for _, buildScript := range buildScripts {
+		content, err := ioutil.ReadFile(buildScript)
+		if err != nil {
+			return jdkRequirement{}, err
+		}
+
+		if requirement.AGPVersion == "" {
+			requirement.AGPVersion = parseAGPVersion(string(content))
+		}
+		if toolchain := parseToolchainVersion(string(content)); toolchain > requirement.Toolchain {
+			requirement.Toolchain = toolchain
+		}
+	}
+
+	if requirement.AGPVersion == "" {
+		_, catalog, err := readFirstExisting(filepath.Join(projectRoot, "gradle"), "libs.versions.toml")
+		if err != nil {
+			return jdkRequirement{}, err
+		}
+		requirement.AGPVersion = parseVersionCatalogAGPVersion(catalog)
+	}
+
+	return requirement, nil
+}
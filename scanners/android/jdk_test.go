@@ -0,0 +1,85 @@
+package android
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJDKRequirement(t *testing.T) {
+	tests := []struct {
+		name        string
+		requirement jdkRequirement
+		wantJDK     string
+		wantWarning bool
+	}{
+		{name: "unknown", requirement: jdkRequirement{GradleVersion: "8.0"}, wantJDK: ""},
+		{name: "AGP 8", requirement: jdkRequirement{GradleVersion: "8.0", AGPVersion: "8.1.0-alpha01"}, wantJDK: "17"},
+		{name: "AGP 7", requirement: jdkRequirement{GradleVersion: "7.5", AGPVersion: "7.4.2"}, wantJDK: "11"},
+		{name: "AGP 4", requirement: jdkRequirement{GradleVersion: "6.5", AGPVersion: "4.1.3"}, wantJDK: "8"},
+		{name: "toolchain", requirement: jdkRequirement{GradleVersion: "8.5", AGPVersion: "8.2.0", Toolchain: 21}, wantJDK: "21"},
+		{name: "toolchain rounded up", requirement: jdkRequirement{GradleVersion: "7.5", AGPVersion: "7.4.2", Toolchain: 15}, wantJDK: "17"},
+		{name: "outdated gradle", requirement: jdkRequirement{GradleVersion: "7.2", AGPVersion: "8.0.0"}, wantJDK: "17", wantWarning: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantJDK, tt.requirement.JDKVersion())
+			require.Equal(t, tt.wantWarning, tt.requirement.Warning() != "")
+		})
+	}
+}
+
+func TestParseVersionCatalogAGPVersion(t *testing.T) {
+	require.Equal(t, "8.1.0", parseVersionCatalogAGPVersion(`[versions]
+agp = "8.1.0" # android gradle plugin
+kotlin = "1.9.0"
+
+[plugins]
+android-application = { id = "com.android.application", version.ref = "agp" }
+`))
+	require.Equal(t, "7.4.2", parseVersionCatalogAGPVersion(`[libraries]
+android-gradlePlugin = { group = "com.android.tools.build", name = "gradle", version = "7.4.2" }
+`))
+	require.Equal(t, "", parseVersionCatalogAGPVersion(`[versions]
+kotlin = "1.9.0"
+`))
+}
+
+func TestDetectJDKRequirement(t *testing.T) {
+	projectRoot, err := pathutil.NormalizedOSTempDirPath("__android_jdk__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(projectRoot))
+	}()
+
+	for pth, content := range map[string]string{
+		"gradle/wrapper/gradle-wrapper.properties": `distributionBase=GRADLE_USER_HOME
+distributionUrl=https\://services.gradle.org/distributions/gradle-8.0-all.zip`,
+		"build.gradle": `buildscript {
+    dependencies {
+        classpath 'com.android.tools.build:gradle:8.0.2'
+    }
+}`,
+		"app/build.gradle.kts": `android {
+    compileOptions {
+        sourceCompatibility = JavaVersion.VERSION_1_8
+    }
+}
+kotlin {
+    jvmToolchain(17)
+}`,
+	} {
+		pth = filepath.Join(projectRoot, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+	}
+
+	requirement, err := detectJDKRequirement(projectRoot)
+	require.NoError(t, err)
+	require.Equal(t, jdkRequirement{GradleVersion: "8.0", AGPVersion: "8.0.2", Toolchain: 17}, requirement)
+	require.Equal(t, map[string]string{"gradle_version": "8.0", "agp_version": "8.0.2", "jdk_version": "17"}, requirement.Metadata())
+}
@@ -0,0 +1,360 @@
+package android
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+const (
+	applicationPluginID  = "com.android.application"
+	kotlinPluginIDPrefix = "org.jetbrains.kotlin."
+)
+
+var (
+	lineCommentPattern  = regexp.MustCompile(`(?m)(^|\s)//.*$`)
+	blockCommentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	quotedStringPattern = regexp.MustCompile(`["']([^"']+)["']`)
+
+	// include ':app', ':lib' / include(":app", ":lib")
+	includePattern = regexp.MustCompile(`\binclude\b\s*\(?\s*((?:["'][^"']+["']\s*,?\s*)+)`)
+	// includeBuild 'build-logic' / includeBuild("build-logic")
+	includeBuildPattern = regexp.MustCompile(`\bincludeBuild\b\s*\(?\s*["']([^"']+)["']`)
+	// project(':app').projectDir = file('android/app') / new File(settingsDir, 'android/app')
+	projectDirPattern = regexp.MustCompile(`project\s*\(\s*["']([^"']+)["']\s*\)\s*\.projectDir\s*=\s*(?:new\s+)?(?:file|File)\s*\(\s*(?:(?:rootDir|settingsDir|rootProject\.projectDir)\s*,\s*)?["']([^"']+)["']`)
+
+	// apply plugin: 'com.android.application' / apply(plugin = "com.android.application") / id 'com.android.application' / id("com.android.application")
+	pluginIDPattern = regexp.MustCompile(`(?:\bplugin\s*[:=]\s*|\bid\s*\(?\s*)["']([^"']+)["']`)
+	// kotlin("multiplatform") / kotlin("android")
+	kotlinPluginPattern = regexp.MustCompile(`\bkotlin\s*\(\s*["']([\w.]+)["']\s*\)`)
+	// alias(libs.plugins.android.application)
+	pluginAliasPattern = regexp.MustCompile(`\balias\s*\(\s*(\w+)\.plugins\.([\w.]+)\s*\)`)
+	// android-application = { id = "com.android.application", version.ref = "agp" } / android-application = "com.android.application:8.0.0"
+	catalogPluginPattern  = regexp.MustCompile(`(?m)^\s*([\w.-]+)\s*=\s*(?:\{[^}]*\bid\s*=\s*"([^"]+)"[^}]*\}|"([^":]+)(?::[^"]*)?")`)
+	catalogSectionPattern = regexp.MustCompile(`(?m)^\s*\[([\w.-]+)\]\s*$`)
+	tomlCommentPattern    = regexp.MustCompile(`(?m)#.*$`)
+	// register("androidApplication") { id = "nowinandroid.android.application"; implementationClass = "AndroidApplicationConventionPlugin" }
+	conventionPluginIDPattern    = regexp.MustCompile(`\bid\s*=\s*"([^"]+)"`)
+	conventionPluginClassPattern = regexp.MustCompile(`\bimplementationClass\s*=\s*"([^"]+)"`)
+)
+
+func stripComments(content string) string {
+	content = blockCommentPattern.ReplaceAllString(content, "")
+	return lineCommentPattern.ReplaceAllString(content, "$1")
+}
+
+// gradleSettings holds the project structure defined by a settings.gradle(.kts) file.
+type gradleSettings struct {
+	// Modules are the included project paths, like app or feature:home.
+	Modules []string
+	// ModuleDirs are the custom module directories, relative to the root project.
+	ModuleDirs map[string]string
+	// IncludedBuilds are the composite build directories, relative to the root project.
+	IncludedBuilds []string
+}
+
+func parseSettingsGradle(content string) gradleSettings {
+	content = stripComments(content)
+	settings := gradleSettings{ModuleDirs: map[string]string{}}
+
+	for _, match := range includePattern.FindAllStringSubmatch(content, -1) {
+		for _, module := range quotedStringPattern.FindAllStringSubmatch(match[1], -1) {
+			name := strings.TrimPrefix(module[1], ":")
+			if name != "" {
+				settings.Modules = appendUnique(settings.Modules, name)
+			}
+		}
+	}
+
+	for _, match := range includeBuildPattern.FindAllStringSubmatch(content, -1) {
+		settings.IncludedBuilds = appendUnique(settings.IncludedBuilds, filepath.Clean(match[1]))
+	}
+
+	for _, match := range projectDirPattern.FindAllStringSubmatch(content, -1) {
+		settings.ModuleDirs[strings.TrimPrefix(match[1], ":")] = filepath.Clean(match[2])
+	}
+
+	return settings
+}
+
+func (settings gradleSettings) moduleDir(module string) string {
+	if dir, ok := settings.ModuleDirs[module]; ok {
+		return dir
+	}
+	return filepath.Join(strings.Split(module, ":")...)
+}
+
+// parseVersionCatalogPlugins returns the plugin IDs defined in the [plugins] section of a version catalog,
+// by their accessor (e.g. android.application for android-application).
+func parseVersionCatalogPlugins(content string) map[string]string {
+	content = tomlCommentPattern.ReplaceAllString(content, "")
+
+	sections := catalogSectionPattern.FindAllStringSubmatchIndex(content, -1)
+	for i, section := range sections {
+		if content[section[2]:section[3]] != "plugins" {
+			continue
+		}
+
+		end := len(content)
+		if i+1 < len(sections) {
+			end = sections[i+1][0]
+		}
+
+		plugins := map[string]string{}
+		for _, match := range catalogPluginPattern.FindAllStringSubmatch(content[section[1]:end], -1) {
+			id := match[2]
+			if id == "" {
+				id = match[3]
+			}
+			plugins[catalogAccessor(match[1])] = id
+		}
+		return plugins
+	}
+
+	return nil
+}
+
+func catalogAccessor(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
+
+// appliedPluginIDs returns the IDs of the plugins applied in a build script,
+// version catalog aliases are resolved by the given catalogs.
+func appliedPluginIDs(content string, catalogs map[string]map[string]string) []string {
+	content = stripComments(content)
+
+	var ids []string
+	for _, match := range pluginIDPattern.FindAllStringSubmatch(content, -1) {
+		ids = appendUnique(ids, match[1])
+	}
+	for _, match := range kotlinPluginPattern.FindAllStringSubmatch(content, -1) {
+		ids = appendUnique(ids, kotlinPluginIDPrefix+match[1])
+	}
+	for _, match := range pluginAliasPattern.FindAllStringSubmatch(content, -1) {
+		if id, ok := catalogs[match[1]][catalogAccessor(match[2])]; ok {
+			ids = appendUnique(ids, id)
+		}
+	}
+	return ids
+}
+
+// conventionApplicationPluginIDs returns the IDs of the convention plugins of an included build,
+// which apply the Android application plugin.
+// Both binary plugins (registered in the gradlePlugin block) and precompiled script plugins are supported.
+func conventionApplicationPluginIDs(buildDir string) ([]string, error) {
+	sources := map[string]string{}
+	if err := filepath.Walk(buildDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if nameMatchSkipDirs(info.Name(), []string{"build", ".gradle"}) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".kt" && ext != ".kts" && ext != ".groovy" && ext != ".gradle" && ext != ".java" {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sources[path] = stripComments(string(content))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	appliesApplicationPlugin := func(content string) bool {
+		return strings.Contains(content, `"`+applicationPluginID+`"`) || strings.Contains(content, `'`+applicationPluginID+`'`)
+	}
+
+	var ids []string
+	for path, content := range sources {
+		base := filepath.Base(path)
+
+		// precompiled script plugin: src/main/kotlin/my.android.application.gradle.kts
+		if strings.Contains(filepath.ToSlash(path), "/src/main/") && (strings.HasSuffix(base, ".gradle.kts") || strings.HasSuffix(base, ".gradle")) {
+			if appliesApplicationPlugin(content) {
+				ids = appendUnique(ids, strings.TrimSuffix(strings.TrimSuffix(base, ".kts"), ".gradle"))
+			}
+			continue
+		}
+
+		// binary plugin registration
+		for _, block := range strings.Split(content, "register") {
+			idMatch := conventionPluginIDPattern.FindStringSubmatch(block)
+			classMatch := conventionPluginClassPattern.FindStringSubmatch(block)
+			if idMatch == nil || classMatch == nil {
+				continue
+			}
+
+			className := classMatch[1][strings.LastIndex(classMatch[1], ".")+1:]
+			for classPath, classContent := range sources {
+				if strings.TrimSuffix(filepath.Base(classPath), filepath.Ext(classPath)) == className && appliesApplicationPlugin(classContent) {
+					ids = appendUnique(ids, idMatch[1])
+				}
+			}
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func readFirstExisting(dir string, names ...string) (string, string, error) {
+	for _, name := range names {
+		pth := filepath.Join(dir, name)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return "", "", err
+		} else if !exist {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(pth)
+		if err != nil {
+			return "", "", err
+		}
+		return pth, string(content), nil
+	}
+	return "", "", nil
+}
+
+// gradleModule is a module of a Gradle project.
+type gradleModule struct {
+	// Name is the Gradle project path of the module without the leading colon, like app or feature:home.
+	Name string
+	// BuildScriptPath is the path of the module's build.gradle(.kts) file.
+	BuildScriptPath string
+	// BuildScript is the content of the module's build script.
+	BuildScript string
+	// PluginIDs are the IDs of the plugins applied by the build script.
+	PluginIDs []string
+	// Application is true if the module applies the Android application plugin
+	// (directly, by a version catalog alias or by a convention plugin of an included build).
+	Application bool
+}
+
+// detectModules returns the modules with a build script, included by the settings script of the project.
+func detectModules(projectRoot string) ([]gradleModule, error) {
+	settingsPth, settingsContent, err := readFirstExisting(projectRoot, "settings.gradle", "settings.gradle.kts")
+	if err != nil || settingsPth == "" {
+		return nil, err
+	}
+	settings := parseSettingsGradle(settingsContent)
+
+	catalogs := map[string]map[string]string{}
+	catalogPth, catalogContent, err := readFirstExisting(filepath.Join(projectRoot, "gradle"), "libs.versions.toml")
+	if err != nil {
+		return nil, err
+	}
+	if catalogPth != "" {
+		catalogs["libs"] = parseVersionCatalogPlugins(catalogContent)
+	}
+
+	applicationPluginIDs := []string{applicationPluginID}
+	for _, includedBuild := range settings.IncludedBuilds {
+		buildDir := filepath.Join(projectRoot, includedBuild)
+		if exist, err := pathutil.IsDirExists(buildDir); err != nil {
+			return nil, err
+		} else if !exist {
+			continue
+		}
+
+		ids, err := conventionApplicationPluginIDs(buildDir)
+		if err != nil {
+			return nil, err
+		}
+		applicationPluginIDs = append(applicationPluginIDs, ids...)
+	}
+
+	var modules []gradleModule
+	for _, module := range settings.Modules {
+		buildScriptPth, buildScript, err := readFirstExisting(filepath.Join(projectRoot, settings.moduleDir(module)), "build.gradle", "build.gradle.kts")
+		if err != nil {
+			return nil, err
+		}
+		if buildScriptPth == "" {
+			continue
+		}
+
+		detected := gradleModule{Name: module, BuildScriptPath: buildScriptPth, BuildScript: buildScript, PluginIDs: appliedPluginIDs(buildScript, catalogs)}
+		for _, id := range detected.PluginIDs {
+			if sliceutil.IsStringInSlice(id, applicationPluginIDs) {
+				detected.Application = true
+				break
+			}
+		}
+		modules = append(modules, detected)
+	}
+
+	return modules, nil
+}
+
+// detectApplicationModules returns the modules of the Android project, which apply the Android application plugin.
+func detectApplicationModules(projectRoot string) ([]gradleModule, error) {
+	modules, err := detectModules(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var applicationModules []gradleModule
+	for _, module := range modules {
+		if module.Application {
+			applicationModules = append(applicationModules, module)
+		}
+	}
+	return applicationModules, nil
+}
+
+// GradleModule is a module of a Gradle project, used by the scanners of cross-platform Gradle projects.
+type GradleModule struct {
+	// Name is the Gradle project path of the module without the leading colon, like app or feature:home.
+	Name string
+	// Dir is the directory of the module.
+	Dir string
+	// PluginIDs are the IDs of the plugins applied by the module's build script.
+	PluginIDs []string
+	// Application is true if the module builds an Android application.
+	Application bool
+	// Variants are the build variants of an application module, like freeDebug or paidRelease.
+	Variants []string
+}
+
+// GradleModules returns the modules included by the settings script of the Gradle project in projectRoot.
+func GradleModules(projectRoot string) ([]GradleModule, error) {
+	modules, err := detectModules(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var gradleModules []GradleModule
+	for _, module := range modules {
+		gradleModule := GradleModule{
+			Name:        module.Name,
+			Dir:         filepath.Dir(module.BuildScriptPath),
+			PluginIDs:   module.PluginIDs,
+			Application: module.Application,
+		}
+		if module.Application {
+			gradleModule.Variants = parseModuleVariants(module.BuildScript).Variants()
+		}
+		gradleModules = append(gradleModules, gradleModule)
+	}
+	return gradleModules, nil
+}
+
+func appendUnique(list []string, item string) []string {
+	if sliceutil.IsStringInSlice(item, list) {
+		return list
+	}
+	return append(list, item)
+}
@@ -0,0 +1,135 @@
+package android
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSettingsGradle(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    gradleSettings
+	}{
+		{
+			name: "groovy",
+			content: `include ':mobile', ':wear'
+// include ':legacy'
+include ':feature:home',
+        ':lib'
+project(':lib').projectDir = new File(settingsDir, 'libs/lib')
+includeBuild 'build-logic'`,
+			want: gradleSettings{
+				Modules:        []string{"mobile", "wear", "feature:home", "lib"},
+				ModuleDirs:     map[string]string{"lib": "libs/lib"},
+				IncludedBuilds: []string{"build-logic"},
+			},
+		},
+		{
+			name: "kotlin dsl",
+			content: `pluginManagement {
+    includeBuild("build-logic")
+    repositories { maven("https://jitpack.io") }
+}
+rootProject.name = "sample"
+include(":app")
+include(":core:data", ":core:ui")
+/* include(":disabled") */`,
+			want: gradleSettings{
+				Modules:        []string{"app", "core:data", "core:ui"},
+				ModuleDirs:     map[string]string{},
+				IncludedBuilds: []string{"build-logic"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseSettingsGradle(tt.content))
+		})
+	}
+}
+
+func TestAppliedPluginIDs(t *testing.T) {
+	catalogs := map[string]map[string]string{
+		"libs": parseVersionCatalogPlugins(`[versions]
+agp = "8.1.0"
+
+[plugins]
+android-application = { id = "com.android.application", version.ref = "agp" }
+kotlin_android = "org.jetbrains.kotlin.android:1.9.0"
+
+[libraries]
+core = { module = "androidx.core:core-ktx", version = "1.10.0" }
+`),
+	}
+	require.Equal(t, map[string]string{
+		"android.application": "com.android.application",
+		"kotlin.android":      "org.jetbrains.kotlin.android",
+	}, catalogs["libs"])
+
+	require.Equal(t, []string{"com.android.application"}, appliedPluginIDs(`apply plugin: 'com.android.application'
+android { defaultConfig { applicationId "io.bitrise.sample" } }`, catalogs))
+	require.Equal(t, []string{"com.android.library", "kotlin-android"}, appliedPluginIDs(`plugins {
+    id 'com.android.library'
+    id("kotlin-android")
+}`, catalogs))
+	require.Equal(t, []string{"com.android.application", "org.jetbrains.kotlin.android"}, appliedPluginIDs(`plugins {
+    alias(libs.plugins.android.application)
+    alias(libs.plugins.kotlin.android)
+}`, catalogs))
+	require.Equal(t, []string{"com.android.library", "org.jetbrains.kotlin.multiplatform"}, appliedPluginIDs(`plugins {
+    id("com.android.library")
+    kotlin("multiplatform")
+}`, catalogs))
+}
+
+func TestDetectApplicationModules(t *testing.T) {
+	projectRoot, err := pathutil.NormalizedOSTempDirPath("__android_modules__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(projectRoot))
+	}()
+
+	for pth, content := range map[string]string{
+		"settings.gradle.kts": `includeBuild("build-logic")` + "\n" + `include(":mobile", ":tv", ":wear", ":lib", ":missing")`,
+		"gradle/libs.versions.toml": `[plugins]
+android-application = { id = "com.android.application", version = "8.1.0" }
+sample-android-application = { id = "sample.android.application" }`,
+		"mobile/build.gradle.kts": `plugins { alias(libs.plugins.android.application) }`,
+		"tv/build.gradle":         `apply plugin: 'com.android.application'`,
+		"wear/build.gradle.kts":   `plugins { alias(libs.plugins.sample.android.application) }`,
+		"lib/build.gradle.kts":    `plugins { id("com.android.library") }`,
+		"build-logic/convention/build.gradle.kts": `gradlePlugin {
+    plugins {
+        register("androidApplication") {
+            id = "sample.android.application"
+            implementationClass = "AndroidApplicationConventionPlugin"
+        }
+    }
+}`,
+		"build-logic/convention/src/main/kotlin/AndroidApplicationConventionPlugin.kt": `class AndroidApplicationConventionPlugin : Plugin<Project> {
+    override fun apply(target: Project) {
+        with(target) { pluginManager.apply("com.android.application") }
+    }
+}`,
+	} {
+		pth = filepath.Join(projectRoot, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+	}
+
+	modules, err := detectApplicationModules(projectRoot)
+	require.NoError(t, err)
+
+	var names []string
+	for _, module := range modules {
+		names = append(names, module.Name)
+	}
+	require.Equal(t, []string{"mobile", "tv", "wear"}, names)
+	require.Equal(t, filepath.Join(projectRoot, "tv", "build.gradle"), modules[1].BuildScriptPath)
+}
@@ -0,0 +1,145 @@
+package android
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const selfSignedDeployWorkflowDescription = `## How to get a signed APK
+
+The release build of this project is signed by Gradle, with the signing credentials read from environment variables.
+
+Add the environment variables used by the **signingConfigs** block of your module's build file as **Secrets**,
+and the **android-build** step will output the signed release build.
+
+## To run this workflow
+
+If you want to run this workflow manually:
+
+1. Open the app's build list page
+2. Click on **[Start/Schedule a Build]** button
+3. Select **deploy** in **Workflow** dropdown input
+4. Click **[Start Build]** button
+`
+
+var (
+	// System.getenv("KEYSTORE_PASSWORD") / System.env.KEYSTORE_PASSWORD / System.env["KEYSTORE_PASSWORD"] / providers.environmentVariable("KEYSTORE_PASSWORD")
+	signingEnvPattern = regexp.MustCompile(`(?:System\.getenv\s*\(\s*["'](\w+)["']\s*\)|System\.env\.(\w+)|System\.env\s*\[\s*["'](\w+)["']\s*\]|environmentVariable\s*\(\s*["'](\w+)["']\s*\))`)
+	// storePassword / keyPassword / storeFile assignments
+	signingPropertyPattern = regexp.MustCompile(`(?m)\b(storeFile|storePassword|keyAlias|keyPassword)\b\s*(?:=\s*)?([^;\n]*)`)
+	// signingConfig signingConfigs.release / signingConfig = signingConfigs.getByName("release")
+	signingConfigReferencePattern = regexp.MustCompile(`\bsigningConfig\s*(?:=\s*)?signingConfigs\s*(?:\.\s*getByName\s*\(\s*["']([^"']+)["']\s*\)|\.\s*(\w+)|\[\s*["']([^"']+)["']\s*\])`)
+	// android { bundle { ... } }
+	bundleBlockPattern = regexp.MustCompile(`\bbundle\s*\{`)
+)
+
+// signingConfig is a signing config defined in the signingConfigs block of a module build script.
+type signingConfig struct {
+	Name string
+	// EnvVars are the environment variables the signing properties are read from.
+	EnvVars []string
+	// EnvBasedCredentials is true if both the store and the key passwords are read from environment variables.
+	EnvBasedCredentials bool
+}
+
+// signingSetup describes how the release build of a module is signed.
+type signingSetup struct {
+	// SelfSigned is true if the release build type signs itself, with credentials read from environment variables.
+	SelfSigned bool
+	EnvVars    []string
+	// AAB is true if the module is configured to be distributed as an Android App Bundle.
+	AAB bool
+}
+
+func parseSigningConfigs(buildScript string) map[string]signingConfig {
+	configs := map[string]signingConfig{}
+
+	body, ok := blockBody(buildScript, "signingConfigs")
+	if !ok {
+		return configs
+	}
+
+	for _, element := range containerElements(body) {
+		config := signingConfig{Name: element.Name}
+		envBasedPasswords := map[string]bool{}
+		for _, property := range signingPropertyPattern.FindAllStringSubmatch(element.Body, -1) {
+			for _, match := range signingEnvPattern.FindAllStringSubmatch(property[2], -1) {
+				for _, envVar := range match[1:] {
+					if envVar != "" {
+						config.EnvVars = appendUnique(config.EnvVars, envVar)
+						envBasedPasswords[property[1]] = true
+					}
+				}
+			}
+		}
+		config.EnvBasedCredentials = envBasedPasswords["storePassword"] && envBasedPasswords["keyPassword"]
+		configs[config.Name] = config
+	}
+
+	return configs
+}
+
+// releaseSigningConfigName returns the name of the signing config used by the release build type.
+func releaseSigningConfigName(buildScript string) string {
+	body, ok := blockBody(buildScript, "buildTypes")
+	if !ok {
+		return ""
+	}
+
+	for _, element := range containerElements(body) {
+		if element.Name != releaseBuildType {
+			continue
+		}
+		if match := signingConfigReferencePattern.FindStringSubmatch(element.Body); match != nil {
+			for _, name := range match[1:] {
+				if name != "" {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// detectSigningSetup parses the signing configuration of a module build script.
+func detectSigningSetup(buildScript string) signingSetup {
+	buildScript = stripComments(buildScript)
+
+	setup := signingSetup{AAB: bundleBlockPattern.MatchString(buildScript)}
+	if config, ok := parseSigningConfigs(buildScript)[releaseSigningConfigName(buildScript)]; ok && config.EnvBasedCredentials {
+		setup.SelfSigned = true
+		setup.EnvVars = config.EnvVars
+	}
+	return setup
+}
+
+// findCommittedKeystores returns the keystore files (except the debug keystore) of the project.
+func findCommittedKeystores(projectRoot string) ([]string, error) {
+	var keystores []string
+	if err := filepath.Walk(projectRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != projectRoot && nameMatchSkipDirs(info.Name(), []string{"build", ".gradle", ".git", "node_modules"}) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if ext := filepath.Ext(info.Name()); (ext == ".jks" || ext == ".keystore") && info.Name() != "debug.keystore" {
+			keystores = append(keystores, path)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return keystores, nil
+}
+
+func committedKeystoreWarning(keystores []string) string {
+	return fmt.Sprintf("keystore files should NOT be checked into Version Control Systems, upload them in the Code Signing tab instead, the location of the files: %s", strings.Join(keystores, ", "))
+}
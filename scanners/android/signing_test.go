@@ -0,0 +1,124 @@
+package android
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSigningSetup(t *testing.T) {
+	tests := []struct {
+		name        string
+		buildScript string
+		want        signingSetup
+	}{
+		{
+			name:        "no signing config",
+			buildScript: `android { buildTypes { release { minifyEnabled true } } }`,
+			want:        signingSetup{},
+		},
+		{
+			name: "groovy env based credentials",
+			buildScript: `android {
+    signingConfigs {
+        release {
+            storeFile file(System.getenv("KEYSTORE_PATH"))
+            storePassword System.getenv("KEYSTORE_PASSWORD")
+            keyAlias System.env.KEY_ALIAS
+            keyPassword System.env["KEY_PASSWORD"]
+        }
+    }
+    buildTypes {
+        release {
+            signingConfig signingConfigs.release
+        }
+    }
+}`,
+			want: signingSetup{SelfSigned: true, EnvVars: []string{"KEYSTORE_PATH", "KEYSTORE_PASSWORD", "KEY_ALIAS", "KEY_PASSWORD"}},
+		},
+		{
+			name: "kotlin providers and bundle",
+			buildScript: `android {
+    signingConfigs {
+        create("upload") {
+            storePassword = providers.environmentVariable("STORE_PASSWORD").get()
+            keyPassword = providers.environmentVariable("KEY_PASSWORD").get()
+        }
+    }
+    buildTypes {
+        getByName("release") {
+            signingConfig = signingConfigs.getByName("upload")
+        }
+    }
+    bundle {
+        language { enableSplit = false }
+    }
+}`,
+			want: signingSetup{SelfSigned: true, EnvVars: []string{"STORE_PASSWORD", "KEY_PASSWORD"}, AAB: true},
+		},
+		{
+			name: "hardcoded credentials",
+			buildScript: `android {
+    signingConfigs {
+        release {
+            storePassword "secret"
+            keyPassword System.getenv("KEY_PASSWORD")
+        }
+    }
+    buildTypes {
+        release {
+            signingConfig signingConfigs.release
+        }
+    }
+}`,
+			want: signingSetup{},
+		},
+		{
+			name: "release build type not signed",
+			buildScript: `android {
+    signingConfigs {
+        staging {
+            storePassword System.getenv("STORE_PASSWORD")
+            keyPassword System.getenv("KEY_PASSWORD")
+        }
+    }
+}`,
+			want: signingSetup{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, detectSigningSetup(tt.buildScript))
+		})
+	}
+}
+
+func TestFindCommittedKeystores(t *testing.T) {
+	projectRoot, err := pathutil.NormalizedOSTempDirPath("__android_signing__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(projectRoot))
+	}()
+
+	for _, pth := range []string{
+		"app/release.jks",
+		"app/debug.keystore",
+		"keystores/upload.keystore",
+		"app/build/intermediates/signing.jks",
+	} {
+		pth = filepath.Join(projectRoot, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, nil, 0644))
+	}
+
+	keystores, err := findCommittedKeystores(projectRoot)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(projectRoot, "app/release.jks"),
+		filepath.Join(projectRoot, "keystores/upload.keystore"),
+	}, keystores)
+}
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
@@ -22,6 +24,10 @@ const (
 	ConfigName        = "android-config"
 	DefaultConfigName = "default-android-config"
 
+	InstrumentedTestConfigName  = "android-instrumented-test-config"
+	ConnectedTestConfigName     = "android-connected-test-config"
+	ManagedDeviceTestConfigName = "android-managed-device-test-config"
+
 	ProjectLocationInputKey     = "project_location"
 	ProjectLocationInputEnvKey  = "PROJECT_LOCATION"
 	ProjectLocationInputTitle   = "The root directory of an Android project"
@@ -29,6 +35,8 @@ const (
 
 	ModuleBuildGradlePathInputKey = "build_gradle_path"
 
+	defaultModule = "app"
+
 	VariantInputKey     = "variant"
 	VariantInputEnvKey  = "VARIANT"
 	VariantInputTitle   = "Variant"
@@ -42,8 +50,21 @@ const (
 	GradlewPathInputKey    = "gradlew_path"
 	GradlewPathInputEnvKey = "GRADLEW_PATH"
 	GradlewPathInputTitle  = "Gradlew file path"
+
+	GradleTaskInputKey = "gradle_task"
+
+	SetJavaVersionInputKey = "set_java_version"
+
+	BuildTypeInputKey  = "build_type"
+	AndroidAppInputKey = "android_app"
+	AABPathEnvKey      = "BITRISE_AAB_PATH"
+
+	aabBuildType = "aab"
 )
 
+// InstrumentedTestWorkflowID is the workflow running the instrumented tests of the app.
+const InstrumentedTestWorkflowID models.WorkflowID = "instrumented_test"
+
 func walk(src string, fn func(path string, info os.FileInfo) error) error {
 	return filePathWalk(src, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -133,13 +154,56 @@ that the right Gradle version is installed and used for the build. More info/gui
 	return nil
 }
 
-func (scanner *Scanner) generateConfigBuilder() models.ConfigBuilderModel {
-	configBuilder := models.NewDefaultConfigBuilder()
+// configDescriptor describes the config generated for a module of an Android project.
+type configDescriptor struct {
+	instrumentedTests *instrumentedTests
+	jdkVersion        string
+	signing           signingSetup
+}
+
+func (descriptor configDescriptor) configName() string {
+	name := ConfigName
+	if descriptor.instrumentedTests != nil {
+		name = descriptor.instrumentedTests.configName()
+	}
+
+	var modifiers []string
+	if tests := descriptor.instrumentedTests; tests != nil && tests.configName() == InstrumentedTestConfigName && tests.Variant != debugBuildType {
+		modifiers = append(modifiers, tests.Variant)
+	}
+	if descriptor.jdkVersion != "" {
+		modifiers = append(modifiers, "jdk"+descriptor.jdkVersion)
+	}
+	if descriptor.signing.SelfSigned {
+		modifiers = append(modifiers, "self-signed")
+	}
+	if descriptor.signing.AAB {
+		modifiers = append(modifiers, "aab")
+	}
+	if len(modifiers) == 0 {
+		return name
+	}
+	return strings.TrimSuffix(name, "-config") + "-" + strings.Join(modifiers, "-") + "-config"
+}
+
+// prepareStepList returns the default prepare steps, followed by the Java version selection if the project requires a specific JDK.
+func prepareStepList(jdkVersion string, caches ...steps.DependencyCache) []bitriseModels.StepListItemModel {
+	stepList := steps.DefaultPrepareStepList(true, caches...)
+	if jdkVersion != "" {
+		stepList = append(stepList, steps.SetJavaVersionStepListItem(
+			envmanModels.EnvironmentItemModel{SetJavaVersionInputKey: jdkVersion},
+		))
+	}
+	return stepList
+}
+
+func (scanner *Scanner) generateConfigBuilder(descriptor configDescriptor, triggerPreset models.TriggerPreset, caches ...steps.DependencyCache) models.ConfigBuilderModel {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 
 	projectLocationEnv, gradlewPath, moduleEnv, variantEnv := "$"+ProjectLocationInputEnvKey, "$"+ProjectLocationInputEnvKey+"/gradlew", "$"+ModuleInputEnvKey, "$"+VariantInputEnvKey
 
 	//-- primary
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(true)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, prepareStepList(descriptor.jdkVersion, caches...)...)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
 		envmanModels.EnvironmentItemModel{GradlewPathInputKey: gradlewPath},
 	))
@@ -165,10 +229,10 @@ func (scanner *Scanner) generateConfigBuilder() models.ConfigBuilderModel {
 			VariantInputKey: variantEnv,
 		},
 	))
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(true)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(true, caches...)...)
 
 	//-- deploy
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(true)...)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, prepareStepList(descriptor.jdkVersion, caches...)...)
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
 		envmanModels.EnvironmentItemModel{GradlewPathInputKey: gradlewPath},
 	))
@@ -200,21 +264,35 @@ func (scanner *Scanner) generateConfigBuilder() models.ConfigBuilderModel {
 		},
 	))
 
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.AndroidBuildStepListItem(
-		envmanModels.EnvironmentItemModel{
-			ProjectLocationInputKey: projectLocationEnv,
-		},
-		envmanModels.EnvironmentItemModel{
-			ModuleInputKey: moduleEnv,
-		},
-		envmanModels.EnvironmentItemModel{
-			VariantInputKey: variantEnv,
-		},
-	))
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.SignAPKStepListItem())
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(true)...)
+	// the deploy build uses the release variants, to be signed by the sign-apk step, unless the release build signs itself,
+	// lint and unit tests keep running on the selected variant
+	buildInputs := []envmanModels.EnvironmentItemModel{
+		{ProjectLocationInputKey: projectLocationEnv},
+		{ModuleInputKey: moduleEnv},
+		{VariantInputKey: releaseBuildType},
+	}
+	if descriptor.signing.AAB {
+		buildInputs = append(buildInputs, envmanModels.EnvironmentItemModel{BuildTypeInputKey: aabBuildType})
+	}
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.AndroidBuildStepListItem(buildInputs...))
+
+	switch {
+	case descriptor.signing.SelfSigned:
+		configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, selfSignedDeployWorkflowDescription)
+	case descriptor.signing.AAB:
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.SignAPKStepListItem(
+			envmanModels.EnvironmentItemModel{AndroidAppInputKey: "$" + AABPathEnvKey},
+		))
+		configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
+	default:
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.SignAPKStepListItem())
+		configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
+	}
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(true, caches...)...)
 
-	configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
+	if descriptor.instrumentedTests != nil {
+		descriptor.instrumentedTests.appendWorkflow(configBuilder, descriptor.jdkVersion, caches...)
+	}
 
 	return *configBuilder
 }
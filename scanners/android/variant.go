@@ -0,0 +1,204 @@
+package android
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+const (
+	debugBuildType   = "debug"
+	releaseBuildType = "release"
+)
+
+var (
+	defaultBuildTypes = []string{debugBuildType, releaseBuildType}
+
+	// getByName("release") / create("staging") / maybeCreate("staging") / register("staging") / named("release")
+	namedContainerElementPattern = regexp.MustCompile(`\b(?:getByName|create|maybeCreate|register|named)\s*\(\s*["']([^"']+)["']`)
+	// staging / staging.initWith(debug) / "staging"
+	containerElementPattern = regexp.MustCompile(`^["']?([A-Za-z_]\w*)["']?\s*(?:\(\s*\))?$`)
+	// flavorDimensions "tier", "env" / flavorDimensions += listOf("tier", "env") / flavorDimensions.add("tier")
+	flavorDimensionsPattern = regexp.MustCompile(`\bflavorDimensions\b[^\n]*`)
+	// dimension "tier" / dimension = "tier" / setDimension("tier")
+	flavorDimensionPattern = regexp.MustCompile(`\b(?:dimension|setDimension)\s*(?:=\s*)?\(?\s*["']([^"']+)["']`)
+
+	// configuration blocks of a container, which do not define a new element
+	containerConfigurationBlocks = []string{"all", "configureEach", "whenObjectAdded", "matching", "withType"}
+)
+
+// blockBody returns the content of the first `name { ... }` block in the build script.
+func blockBody(content, name string) (string, bool) {
+	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*\{`)
+	loc := pattern.FindStringIndex(content)
+	if loc == nil {
+		return "", false
+	}
+
+	depth := 0
+	for i := loc[1] - 1; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return content[loc[1]:i], true
+			}
+		}
+	}
+	return content[loc[1]:], true
+}
+
+// containerElement is a named element of a Gradle domain object container, like a build type or a product flavor.
+type containerElement struct {
+	Name string
+	Body string
+}
+
+// containerElements returns the elements configured in the body of a container block (like buildTypes or productFlavors).
+func containerElements(body string) []containerElement {
+	var elements []containerElement
+
+	depth := 0
+	headerStart := 0
+	elementStart := -1
+	header := ""
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '{':
+			if depth == 0 {
+				header = strings.TrimSpace(body[headerStart:i])
+				elementStart = i + 1
+			}
+			depth++
+		case '}':
+			depth--
+			if depth == 0 && elementStart >= 0 {
+				if name := containerElementName(header); name != "" {
+					elements = append(elements, containerElement{Name: name, Body: body[elementStart:i]})
+				}
+				headerStart = i + 1
+				elementStart = -1
+			}
+		case '\n', ';':
+			if depth == 0 {
+				headerStart = i + 1
+			}
+		}
+	}
+
+	return elements
+}
+
+func containerElementName(header string) string {
+	if match := namedContainerElementPattern.FindStringSubmatch(header); match != nil {
+		return match[1]
+	}
+
+	// staging.initWith(debug) {
+	if idx := strings.Index(header, "."); idx > 0 {
+		header = header[:idx]
+	}
+	if match := containerElementPattern.FindStringSubmatch(header); match != nil && !sliceutil.IsStringInSlice(match[1], containerConfigurationBlocks) {
+		return match[1]
+	}
+	return ""
+}
+
+// productFlavor is a product flavor of a module.
+type productFlavor struct {
+	Name      string
+	Dimension string
+}
+
+// moduleVariants holds the build types and product flavors of a module.
+type moduleVariants struct {
+	BuildTypes       []string
+	FlavorDimensions []string
+	ProductFlavors   []productFlavor
+}
+
+// parseModuleVariants parses the build types and product flavors from a Groovy or Kotlin DSL module build script.
+func parseModuleVariants(buildScript string) moduleVariants {
+	buildScript = stripComments(buildScript)
+	variants := moduleVariants{BuildTypes: append([]string{}, defaultBuildTypes...)}
+
+	if body, ok := blockBody(buildScript, "buildTypes"); ok {
+		for _, element := range containerElements(body) {
+			variants.BuildTypes = appendUnique(variants.BuildTypes, element.Name)
+		}
+	}
+
+	for _, line := range flavorDimensionsPattern.FindAllString(buildScript, -1) {
+		for _, match := range quotedStringPattern.FindAllStringSubmatch(line, -1) {
+			variants.FlavorDimensions = appendUnique(variants.FlavorDimensions, match[1])
+		}
+	}
+
+	if body, ok := blockBody(buildScript, "productFlavors"); ok {
+		for _, element := range containerElements(body) {
+			flavor := productFlavor{Name: element.Name}
+			if match := flavorDimensionPattern.FindStringSubmatch(element.Body); match != nil {
+				flavor.Dimension = match[1]
+			}
+			variants.ProductFlavors = append(variants.ProductFlavors, flavor)
+		}
+	}
+
+	return variants
+}
+
+// ProductFlavors returns the product flavors declared by a Groovy or Kotlin DSL module build script,
+// used by the scanners of cross-platform projects with an Android app module.
+func ProductFlavors(buildScript string) []string {
+	var flavors []string
+	for _, flavor := range parseModuleVariants(buildScript).ProductFlavors {
+		flavors = appendUnique(flavors, flavor.Name)
+	}
+	return flavors
+}
+
+// Variants returns the variant names of the module, like freeDebug or paidRelease.
+// A variant is a combination of a product flavor of each flavor dimension and a build type.
+func (variants moduleVariants) Variants() []string {
+	var flavorGroups [][]string
+	if len(variants.FlavorDimensions) > 1 {
+		for _, dimension := range variants.FlavorDimensions {
+			var group []string
+			for _, flavor := range variants.ProductFlavors {
+				if flavor.Dimension == dimension {
+					group = append(group, flavor.Name)
+				}
+			}
+			if len(group) > 0 {
+				flavorGroups = append(flavorGroups, group)
+			}
+		}
+	} else if len(variants.ProductFlavors) > 0 {
+		// with a single flavor dimension, the dimension of the flavors can be omitted
+		var group []string
+		for _, flavor := range variants.ProductFlavors {
+			group = append(group, flavor.Name)
+		}
+		flavorGroups = append(flavorGroups, group)
+	}
+
+	combinations := []string{""}
+	for _, group := range append(flavorGroups, variants.BuildTypes) {
+		var next []string
+		for _, prefix := range combinations {
+			for _, name := range group {
+				if prefix == "" {
+					next = append(next, name)
+				} else {
+					next = append(next, prefix+strings.ToUpper(name[:1])+name[1:])
+				}
+			}
+		}
+		combinations = next
+	}
+
+	return combinations
+}
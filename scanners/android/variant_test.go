@@ -0,0 +1,114 @@
+package android
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModuleVariants(t *testing.T) {
+	tests := []struct {
+		name        string
+		buildScript string
+		want        []string
+	}{
+		{
+			name:        "no build types and flavors",
+			buildScript: `android { compileSdkVersion 33 }`,
+			want:        []string{"debug", "release"},
+		},
+		{
+			name: "groovy",
+			buildScript: `android {
+    buildTypes {
+        release {
+            minifyEnabled true
+        }
+        // qa { }
+        staging {
+            initWith debug
+        }
+    }
+    flavorDimensions "tier"
+    productFlavors {
+        free { dimension "tier" }
+        paid {
+            dimension "tier"
+            applicationIdSuffix ".paid"
+        }
+    }
+}`,
+			want: []string{"freeDebug", "freeRelease", "freeStaging", "paidDebug", "paidRelease", "paidStaging"},
+		},
+		{
+			name: "kotlin dsl with multiple flavor dimensions",
+			buildScript: `android {
+    buildTypes {
+        getByName("release") {
+            isMinifyEnabled = true
+        }
+        create("benchmark") {
+            initWith(getByName("release"))
+        }
+        all { }
+    }
+    flavorDimensions += listOf("tier", "env")
+    productFlavors {
+        create("prod") { dimension = "env" }
+        create("free") { dimension = "tier" }
+        create("paid") { dimension = "tier" }
+    }
+}`,
+			want: []string{"freeProdDebug", "freeProdRelease", "freeProdBenchmark", "paidProdDebug", "paidProdRelease", "paidProdBenchmark"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseModuleVariants(tt.buildScript).Variants())
+		})
+	}
+}
+
+func TestProductFlavors(t *testing.T) {
+	tests := []struct {
+		name        string
+		buildScript string
+		want        []string
+	}{
+		{
+			name:        "no product flavors",
+			buildScript: `android { buildTypes { release { } } }`,
+			want:        nil,
+		},
+		{
+			name: "groovy with comments",
+			buildScript: `android {
+    flavorDimensions "env"
+    productFlavors {
+        // staging { dimension "env" }
+        dev { dimension "env" }
+        /* qa {
+            dimension "env"
+        } */
+        prod { dimension "env" }
+    }
+}`,
+			want: []string{"dev", "prod"},
+		},
+		{
+			name: "kotlin dsl",
+			buildScript: `android {
+    productFlavors {
+        create("dev") { dimension = "env" }
+        register("prod") { dimension = "env" }
+    }
+}`,
+			want: []string{"dev", "prod"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ProductFlavors(tt.buildScript))
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
 	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/bitrise-io/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
@@ -45,6 +46,15 @@ const (
 	targetEmulator = "emulator"
 )
 
+const (
+	addPlatformInputKey = "add_platform"
+
+	addPlatformsScriptTitle = "Add the Cordova platforms"
+)
+
+// defaultPlatforms are offered if the project does not declare its platforms.
+var defaultPlatforms = []string{"ios", "android", "ios,android"}
+
 //------------------
 // ScannerInterface
 //------------------
@@ -56,6 +66,8 @@ type Scanner struct {
 	searchDir           string
 	hasKarmaJasmineTest bool
 	hasJasmineTest      bool
+	// platformSpecs are the targeted platforms and their version specs.
+	platformSpecs map[string]string
 }
 
 // NewScanner ...
@@ -220,6 +232,31 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	}
 	// ---
 
+	// Search for the targeted platforms
+	widget, err := ParseConfigXML(scanner.cordovaConfigPth)
+	if err != nil {
+		return models.OptionNode{}, warnings, nil, fmt.Errorf("failed to parse config.xml, error: %s", err)
+	}
+	packageJSON, err := parsePackageJSON(packagesJSONPth)
+	if err != nil {
+		return models.OptionNode{}, warnings, nil, err
+	}
+	scanner.platformSpecs = targetPlatforms(widget, packageJSON)
+	platforms := platformOptions(scanner.platformSpecs)
+	log.TPrintf("Targeted platforms: %v", platforms)
+	if len(platforms) == 0 {
+		platforms = defaultPlatforms
+	}
+
+	for _, dir := range []string{"platforms", "plugins"} {
+		if exist, err := pathutil.IsDirExists(filepath.Join(projectRootDir, dir)); err != nil {
+			return models.OptionNode{}, warnings, nil, err
+		} else if exist {
+			warnings = append(warnings, fmt.Sprintf("The %s directory is committed to the repository. Cordova restores it from config.xml and package.json, remove it from the repository (and add it to .gitignore) to avoid building with outdated native projects.", dir))
+		}
+	}
+	// ---
+
 	// Get relative config.xml dir
 	cordovaConfigDir := filepath.Dir(scanner.cordovaConfigPth)
 	relCordovaConfigDir, err := utility.RelPath(scanner.searchDir, cordovaConfigDir)
@@ -236,8 +273,6 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	// Options
 	var rootOption *models.OptionNode
 
-	platforms := []string{"ios", "android", "ios,android"}
-
 	if relCordovaConfigDir != "" {
 		rootOption = models.NewOption(workDirInputTitle, workDirInputSummary, workDirInputEnvKey, models.TypeSelector)
 
@@ -268,12 +303,7 @@ func (*Scanner) DefaultOptions() models.OptionNode {
 	platformTypeOption := models.NewOption(platformInputTitle, platformInputSummary, platformInputEnvKey, models.TypeSelector)
 	workDirOption.AddOption("", platformTypeOption)
 
-	platforms := []string{
-		"ios",
-		"android",
-		"ios,android",
-	}
-	for _, platform := range platforms {
+	for _, platform := range defaultPlatforms {
 		configOption := models.NewConfigOption(defaultConfigName, nil)
 		platformTypeOption.AddConfig(platform, configOption)
 	}
@@ -282,17 +312,35 @@ func (*Scanner) DefaultOptions() models.OptionNode {
 }
 
 // Configs ...
-func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	packageJSONDir := filepath.Dir(scanner.cordovaConfigPth)
+	jsPackageManager, err := utility.DetectJSPackageManager(scanner.searchDir, packageJSONDir)
+	if err != nil {
+		return models.BitriseConfigMap{}, fmt.Errorf("failed to detect the JavaScript package manager, error: %s", err)
+	}
+	log.TPrintf("Js dependency manager: %s", jsPackageManager)
+
+	jsProjectDirs := []string{packageJSONDir}
+	if jsPackageManager.InstallDir != "" {
+		jsProjectDirs = append(jsProjectDirs, jsPackageManager.InstallDir)
+	}
+	caches, err := utility.DetectDependencyCaches(scanner.searchDir, jsProjectDirs, utility.JSDependencyManagers...)
+	if err != nil {
+		return models.BitriseConfigMap{}, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+	}
 
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+
+	workdir := ""
 	workdirEnvList := []envmanModels.EnvironmentItemModel{}
 	if scanner.relCordovaConfigDir != "" {
-		workdirEnvList = append(workdirEnvList, envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey})
+		workdir = "$" + workDirInputEnvKey
+		workdirEnvList = append(workdirEnvList, envmanModels.EnvironmentItemModel{workDirInputKey: workdir})
 	}
 
 	if scanner.hasJasmineTest || scanner.hasKarmaJasmineTest {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, jsPackageManager.InstallStepListItem(workdir))
 
 		// CI
 		if scanner.hasKarmaJasmineTest {
@@ -300,13 +348,13 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 		} else if scanner.hasJasmineTest {
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.JasmineTestRunnerStepListItem(workdirEnvList...))
 		}
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 		// CD
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, jsPackageManager.InstallStepListItem(workdir))
 
 		if scanner.hasKarmaJasmineTest {
 			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.KarmaJasmineTestRunnerStepListItem(workdirEnvList...))
@@ -316,15 +364,8 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.GenerateCordovaBuildConfigStepListItem())
 
-		cordovaArchiveEnvs := []envmanModels.EnvironmentItemModel{
-			envmanModels.EnvironmentItemModel{platformInputKey: "$" + platformInputEnvKey},
-			envmanModels.EnvironmentItemModel{targetInputKey: targetEmulator},
-		}
-		if scanner.relCordovaConfigDir != "" {
-			cordovaArchiveEnvs = append(cordovaArchiveEnvs, envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey})
-		}
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CordovaArchiveStepListItem(cordovaArchiveEnvs...))
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, scanner.archiveStepListItems(jsPackageManager, workdir)...)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 		config, err := configBuilder.Generate(ScannerName)
 		if err != nil {
@@ -342,19 +383,12 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 	}
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, jsPackageManager.InstallStepListItem(workdir))
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.GenerateCordovaBuildConfigStepListItem())
 
-	cordovaArchiveEnvs := []envmanModels.EnvironmentItemModel{
-		envmanModels.EnvironmentItemModel{platformInputKey: "$" + platformInputEnvKey},
-		envmanModels.EnvironmentItemModel{targetInputKey: targetEmulator},
-	}
-	if scanner.relCordovaConfigDir != "" {
-		cordovaArchiveEnvs = append(cordovaArchiveEnvs, envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey})
-	}
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CordovaArchiveStepListItem(cordovaArchiveEnvs...))
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, scanner.archiveStepListItems(jsPackageManager, workdir)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 	config, err := configBuilder.Generate(ScannerName)
 	if err != nil {
@@ -371,9 +405,34 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 	}, nil
 }
 
+// archiveStepListItems returns the steps building the selected platforms in the workdir.
+// The platforms with a pinned version are added by a Script step, instead of the archive step.
+func (scanner *Scanner) archiveStepListItems(jsPackageManager utility.JSPackageManager, workdir string) []bitriseModels.StepListItemModel {
+	var stepListItems []bitriseModels.StepListItemModel
+
+	cordovaArchiveEnvs := []envmanModels.EnvironmentItemModel{
+		envmanModels.EnvironmentItemModel{platformInputKey: "$" + platformInputEnvKey},
+		envmanModels.EnvironmentItemModel{targetInputKey: targetEmulator},
+	}
+	if scanner.relCordovaConfigDir != "" {
+		cordovaArchiveEnvs = append(cordovaArchiveEnvs, envmanModels.EnvironmentItemModel{workDirInputKey: workdir})
+	}
+
+	if script := addPlatformsScript(jsPackageManager, scanner.platformSpecs); script != "" {
+		scriptInputs := []envmanModels.EnvironmentItemModel{{"content": script}}
+		if workdir != "" {
+			scriptInputs = append(scriptInputs, envmanModels.EnvironmentItemModel{"working_dir": workdir})
+		}
+		stepListItems = append(stepListItems, steps.ScriptSteplistItem(addPlatformsScriptTitle, scriptInputs...))
+		cordovaArchiveEnvs = append(cordovaArchiveEnvs, envmanModels.EnvironmentItemModel{addPlatformInputKey: "false"})
+	}
+
+	return append(stepListItems, steps.CordovaArchiveStepListItem(cordovaArchiveEnvs...))
+}
+
 // DefaultConfigs ...
-func (*Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func (*Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
@@ -3,6 +3,7 @@ package cordova
 import (
 	"testing"
 
+	"github.com/bitrise-io/bitrise-init/utility"
 	"github.com/stretchr/testify/require"
 )
 
@@ -10,6 +11,72 @@ func TestParseConfigXMLContent(t *testing.T) {
 	widget, err := parseConfigXMLContent(testConfigXMLContent)
 	require.NoError(t, err)
 	require.Equal(t, "http://cordova.apache.org/ns/1.0", widget.XMLNSCDV)
+	require.Equal(t, []EngineModel{{Name: "ios", Spec: "~4.3.1"}, {Name: "android", Spec: "~6.1.2"}}, widget.Engines)
+	require.Equal(t, []PlatformModel{{Name: "android"}, {Name: "ios"}}, widget.Platforms)
+}
+
+func TestTargetPlatforms(t *testing.T) {
+	widget, err := parseConfigXMLContent(testConfigXMLContent)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		widget      WidgetModel
+		packageJSON packageJSONModel
+		want        map[string]string
+	}{
+		{
+			name:   "engines of config.xml",
+			widget: widget,
+			want:   map[string]string{"ios": "~4.3.1", "android": "~6.1.2"},
+		},
+		{
+			name:   "platform elements of config.xml",
+			widget: WidgetModel{Platforms: []PlatformModel{{Name: "android"}, {Name: "browser"}}},
+			want:   map[string]string{"android": ""},
+		},
+		{
+			name:   "platforms of package.json",
+			widget: widget,
+			packageJSON: func() packageJSONModel {
+				var packageJSON packageJSONModel
+				packageJSON.Cordova.Platforms = []string{"android"}
+				packageJSON.DevDependencies = map[string]string{"cordova-android": "^10.1.2"}
+				return packageJSON
+			}(),
+			want: map[string]string{"android": "^10.1.2"},
+		},
+		{
+			name: "no platforms",
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, targetPlatforms(tt.widget, tt.packageJSON))
+		})
+	}
+}
+
+func TestPlatformOptions(t *testing.T) {
+	require.Equal(t, []string{"ios", "android", "ios,android"}, platformOptions(map[string]string{"android": "", "ios": ""}))
+	require.Equal(t, []string{"android"}, platformOptions(map[string]string{"android": ""}))
+	require.Equal(t, []string(nil), platformOptions(map[string]string{}))
+}
+
+func TestAddPlatformsScript(t *testing.T) {
+	jsPackageManager := utility.JSPackageManager{Name: utility.NpmPackageManager}
+
+	require.Equal(t, "", addPlatformsScript(jsPackageManager, map[string]string{"android": "", "ios": ""}))
+	require.Equal(t, `#!/usr/bin/env bash
+set -ex
+
+for platform in ${CORDOVA_PLATFORM//,/ }; do
+  case "$platform" in
+    android) npx cordova platform add "android@~6.1.2" ;;
+    ios) npx cordova platform add "ios" ;;
+  esac
+done`, addPlatformsScript(jsPackageManager, map[string]string{"android": "~6.1.2", "ios": ""}))
 }
 
 const testConfigXMLContent = `<?xml version='1.0' encoding='utf-8'?>
@@ -1,8 +1,13 @@
 package cordova
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/bitrise-io/bitrise-init/utility"
 	"github.com/bitrise-io/go-utils/fileutil"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
@@ -11,7 +16,20 @@ const configXMLBasePath = "config.xml"
 
 // WidgetModel ...
 type WidgetModel struct {
-	XMLNSCDV string `xml:"xmlns cdv,attr"`
+	XMLNSCDV  string          `xml:"xmlns cdv,attr"`
+	Engines   []EngineModel   `xml:"engine"`
+	Platforms []PlatformModel `xml:"platform"`
+}
+
+// EngineModel is a platform added to the project, with its version spec (cordova-cli 6 and earlier).
+type EngineModel struct {
+	Name string `xml:"name,attr"`
+	Spec string `xml:"spec,attr"`
+}
+
+// PlatformModel holds the platform specific settings of the project.
+type PlatformModel struct {
+	Name string `xml:"name,attr"`
 }
 
 func parseConfigXMLContent(content string) (WidgetModel, error) {
@@ -45,3 +63,114 @@ func FilterRootConfigXMLFile(fileList []string) (string, error) {
 
 	return configXMLs[0], nil
 }
+
+// packageJSONModel holds the Cordova settings of package.json (cordova-cli 7 and later).
+type packageJSONModel struct {
+	Cordova struct {
+		Platforms []string `json:"platforms"`
+	} `json:"cordova"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+func parsePackageJSON(pth string) (packageJSONModel, error) {
+	content, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return packageJSONModel{}, err
+	}
+	var packageJSON packageJSONModel
+	if err := json.Unmarshal(content, &packageJSON); err != nil {
+		return packageJSONModel{}, err
+	}
+	return packageJSON, nil
+}
+
+// targetPlatforms returns the iOS and Android platforms targeted by the project and their version specs (empty if not pinned).
+// The platforms of package.json take precedence over the engines of config.xml, the platform elements are used if neither is set.
+func targetPlatforms(widget WidgetModel, packageJSON packageJSONModel) map[string]string {
+	specs := map[string]string{}
+	addPlatform := func(name, spec string) {
+		// cordova-ios@6.2.0 / ios
+		name = strings.TrimPrefix(strings.SplitN(name, "@", 2)[0], "cordova-")
+		if name == "ios" || name == "android" {
+			if _, ok := specs[name]; !ok || spec != "" {
+				specs[name] = spec
+			}
+		}
+	}
+
+	for _, platform := range packageJSON.Cordova.Platforms {
+		addPlatform(platform, "")
+	}
+	if len(specs) == 0 {
+		for _, engine := range widget.Engines {
+			addPlatform(engine.Name, engine.Spec)
+		}
+	}
+	if len(specs) == 0 {
+		for _, platform := range widget.Platforms {
+			addPlatform(platform.Name, "")
+		}
+	}
+
+	// the cordova-ios and cordova-android dependencies hold the platform versions since cordova-cli 7
+	for platform := range specs {
+		for _, dependencies := range []map[string]string{packageJSON.Dependencies, packageJSON.DevDependencies} {
+			if spec, ok := dependencies["cordova-"+platform]; ok {
+				specs[platform] = spec
+			}
+		}
+		if specs[platform] == "" {
+			for _, engine := range widget.Engines {
+				if engine.Name == platform {
+					specs[platform] = engine.Spec
+				}
+			}
+		}
+	}
+
+	return specs
+}
+
+// platformOptions returns the values of the platform option: every targeted platform and their combination.
+func platformOptions(specs map[string]string) []string {
+	var platforms []string
+	for platform := range specs {
+		platforms = append(platforms, platform)
+	}
+	// ios, android, ios,android
+	sort.Sort(sort.Reverse(sort.StringSlice(platforms)))
+
+	if len(platforms) < 2 {
+		return platforms
+	}
+	return append(platforms, strings.Join(platforms, ","))
+}
+
+// addPlatformsScript returns the script adding the platforms selected by the platform env var, with their pinned versions,
+// empty if no version is pinned.
+func addPlatformsScript(jsPackageManager utility.JSPackageManager, specs map[string]string) string {
+	pinned := false
+	for _, spec := range specs {
+		pinned = pinned || spec != ""
+	}
+	if !pinned {
+		return ""
+	}
+
+	var platforms []string
+	for platform := range specs {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	script := "#!/usr/bin/env bash\nset -ex\n\nfor platform in ${" + platformInputEnvKey + "//,/ }; do\n  case \"$platform\" in\n"
+	for _, platform := range platforms {
+		target := platform
+		if spec := specs[platform]; spec != "" {
+			target += "@" + spec
+		}
+		script += fmt.Sprintf("    %s) %s ;;\n", platform, jsPackageManager.ExecCommand(fmt.Sprintf("cordova platform add \"%s\"", target)))
+	}
+	return script + "  esac\ndone"
+}
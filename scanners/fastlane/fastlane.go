@@ -9,7 +9,6 @@ import (
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/bitrise-io/bitrise-init/toolscanner"
-	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/log"
 )
@@ -186,9 +185,9 @@ func (*Scanner) DefaultOptions() models.OptionNode {
 }
 
 // Configs ...
-func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	generateConfig := func(isIOS bool) (bitriseModels.BitriseDataModel, error) {
-		configBuilder := models.NewDefaultConfigBuilder()
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	generateConfig := func(isIOS bool) (models.BitriseDataModel, error) {
+		configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 
 		if isIOS {
@@ -210,7 +209,7 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 	}
 
 	// Create list of possible configs with project types
-	nameToConfigModel := map[string]bitriseModels.BitriseDataModel{}
+	nameToConfigModel := map[string]models.BitriseDataModel{}
 
 	for _, platform := range scanner.projectTypes {
 		config, err := generateConfig(platform == iosPlatform)
@@ -235,11 +234,11 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 }
 
 // DefaultConfigs ...
-func (*Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
+func (*Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
 	configMap := models.BitriseConfigMap{}
 
 	for _, p := range platforms {
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 
 		if p == iosPlatform {
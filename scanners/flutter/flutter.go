@@ -1,20 +1,25 @@
 package flutter
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
 	"github.com/bitrise-io/go-xcode/pathfilters"
 
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/android"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
 	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-xcode/xcodeproject/xcodeproj"
 	"github.com/bitrise-io/go-xcode/xcodeproject/xcworkspace"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -24,7 +29,6 @@ const (
 	configName                  = "flutter-config"
 	projectLocationInputKey     = "project_location"
 	platformInputKey            = "platform"
-	defaultIOSConfiguration     = "Release"
 	projectLocationInputEnvKey  = "BITRISE_FLUTTER_PROJECT_LOCATION"
 	projectLocationInputTitle   = "Project location"
 	projectTypeInputEnvKey      = "BITRISE_FLUTTER_PROJECT_TYPE"
@@ -33,16 +37,29 @@ const (
 	platformInputTitle          = "Platform"
 	projectLocationInputSummary = "The path to your Flutter project, stored as an Environment Variable. In your Workflows, you can specify paths relative to this path. You can change this at any time."
 	testsInputSummary           = "Our Flutter Test Step can run the tests found in your project's repository."
-	platformInputSummary        = "The target platform for your first build. Your options are iOS, Android, both, or neither. You can change this in your Env Vars at any time."
+	platformInputSummary        = "The target platform for your first build. Your options are iOS, Android, both, web, macOS, Linux, or neither. You can change this in your Env Vars at any time."
 	installerUpdateFlutterKey   = "is_update"
+	installerVersionKey         = "version"
+)
+
+const (
+	flavorInputEnvKey  = "BITRISE_FLUTTER_FLAVOR"
+	flavorInputTitle   = "Flavor"
+	flavorInputSummary = "The flavor of the app built by the deploy Workflow: an Android productFlavor or an iOS flavor scheme. You can change this in your Env Vars at any time."
+
+	androidAdditionalParamsInputKey = "android_additional_params"
+	iosAdditionalParamsInputKey     = "ios_additional_params"
 )
 
 var (
 	platforms = []string{
 		"none",
-		"android",
-		"ios",
-		"both",
+		androidPlatform,
+		iosPlatform,
+		bothPlatform,
+		webPlatform,
+		macosPlatform,
+		linuxPlatform,
 	}
 )
 
@@ -53,6 +70,10 @@ var (
 // Scanner ...
 type Scanner struct {
 	projects []project
+	metadata models.Metadata
+
+	// configDescriptors holds the descriptors of the configs offered in the options, by config name.
+	configDescriptors map[string]configDescriptor
 }
 
 type project struct {
@@ -61,6 +82,52 @@ type project struct {
 	hasTest           bool
 	hasIosProject     bool
 	hasAndroidProject bool
+	sdkVersion        sdkVersion
+	// androidFlavors are the productFlavors of the Android app module.
+	androidFlavors []string
+	// iosFlavors are the flavors of the iOS flavor schemes.
+	iosFlavors          []string
+	additionalPlatforms []string
+}
+
+// configDescriptor describes a generated config.
+type configDescriptor struct {
+	flutterVersion string
+	test           bool
+	// platform is the platform built by the deploy workflow, the deploy workflow is not generated if empty.
+	platform string
+	// flavor is true if the deploy workflow builds the flavor selected by the flavor option.
+	flavor bool
+	// iosConfigurations is true if the iOS app is archived with the build configuration selected by the configuration option.
+	iosConfigurations bool
+	// caches are the dependency caches of the projects using the config, they do not affect the config name.
+	caches []steps.DependencyCache
+}
+
+func (descriptor configDescriptor) configName() string {
+	name := versionedConfigName(descriptor.flutterVersion)
+	if descriptor.test {
+		name += "-test"
+	}
+	if descriptor.platform != "" {
+		name += "-app-" + descriptor.platform
+	}
+	if descriptor.flavor {
+		name += "-flavor"
+	}
+	if descriptor.iosConfigurations {
+		name += "-configuration"
+	}
+	return name
+}
+
+// versionedConfigName returns the name of the configs installing the given Flutter version (or channel),
+// the default config name if the version is not set.
+func versionedConfigName(version string) string {
+	if version == "" {
+		return configName
+	}
+	return strings.TrimSuffix(configName, "-config") + "-" + version + "-config"
 }
 
 type pubspec struct {
@@ -139,6 +206,8 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	}
 	log.TPrintf("")
 
+	scanner.metadata = models.Metadata{}
+
 	log.TInfof("Fetching pubspec.yaml files")
 projects:
 	for _, projectLocation := range projectLocations {
@@ -169,9 +238,11 @@ projects:
 			}
 		}
 
-		iosProjPath := filepath.Join(projectLocation, "ios", "Runner.xcworkspace")
-		if exists, err := pathutil.IsPathExists(iosProjPath); err == nil && exists {
-			proj.hasIosProject = true
+		// projects without CocoaPods dependencies have no workspace
+		for _, iosProjPath := range []string{filepath.Join(projectLocation, "ios", "Runner.xcworkspace"), filepath.Join(projectLocation, "ios", "Runner.xcodeproj")} {
+			if exists, err := pathutil.IsPathExists(iosProjPath); err == nil && exists {
+				proj.hasIosProject = true
+			}
 		}
 
 		androidProjPath := filepath.Join(projectLocation, "android", "build.gradle")
@@ -186,11 +257,35 @@ projects:
 			}
 		}
 
+		if platforms, err := detectAdditionalPlatforms(projectLocation); err != nil {
+			log.TWarnf("Failed to detect the platforms of %s, error: %s", projectLocation, err)
+		} else {
+			proj.additionalPlatforms = platforms
+		}
+
+		if proj.hasAndroidProject {
+			if proj.androidFlavors, err = detectAndroidFlavors(projectLocation); err != nil {
+				log.TWarnf("Failed to detect the Android flavors of %s, error: %s", projectLocation, err)
+			}
+		}
+
+		if version, err := detectSDKVersion(projectLocation); err != nil {
+			log.TWarnf("Failed to detect the required Flutter version in %s, error: %s", projectLocation, err)
+		} else {
+			proj.sdkVersion = version
+		}
+
 		log.TPrintf("- Project name: %s", ps.Name)
 		log.TPrintf("  Path: %s", projectLocation)
 		log.TPrintf("  HasTest: %t", proj.hasTest)
 		log.TPrintf("  HasAndroidProject: %t", proj.hasAndroidProject)
 		log.TPrintf("  HasIosProject: %t", proj.hasIosProject)
+		if len(proj.additionalPlatforms) > 0 {
+			log.TPrintf("  Additional platforms: %s", strings.Join(proj.additionalPlatforms, ", "))
+		}
+		if proj.sdkVersion.Version != "" {
+			log.TPrintf("  Flutter version: %s (%s)", proj.sdkVersion.Version, proj.sdkVersion.Source)
+		}
 
 		proj.path = projectLocation
 
@@ -200,6 +295,7 @@ projects:
 			} else {
 				log.TPrintf("  XCWorkspaces(%d):", len(workspaceLocations))
 
+				proj.xcodeProjectPaths = map[string][]string{}
 				for _, workspaceLocation := range workspaceLocations {
 					log.TPrintf("    Path: %s", workspaceLocation)
 					ws, err := xcworkspace.Open(workspaceLocation)
@@ -211,8 +307,6 @@ projects:
 						continue projects
 					}
 
-					proj.xcodeProjectPaths = map[string][]string{}
-
 					for _, schemes := range schemeMap {
 						if len(schemes) > 0 {
 							log.TPrintf("    Schemes(%d):", len(schemes))
@@ -224,8 +318,38 @@ projects:
 					}
 				}
 			}
+
+			if len(proj.xcodeProjectPaths) == 0 {
+				xcodeProjectPath := proj.xcodeProjectPath()
+				if schemes, err := projectSchemes(xcodeProjectPath); err != nil {
+					log.TWarnf("Failed to read the schemes of %s, error: %s", xcodeProjectPath, err)
+				} else {
+					log.TPrintf("  Xcode project: %s", xcodeProjectPath)
+					log.TPrintf("    Schemes(%d):", len(schemes))
+					for _, scheme := range schemes {
+						log.TPrintf("    - %s", scheme)
+					}
+					proj.xcodeProjectPaths = map[string][]string{xcodeProjectPath: schemes}
+				}
+			}
+
+			if len(proj.xcodeProjectPaths) == 0 {
+				log.TWarnf("No Xcode scheme found in %s, the iOS app is not built", filepath.Join(projectLocation, "ios"))
+				proj.hasIosProject = false
+			}
+		}
+
+		if len(proj.androidFlavors) > 0 {
+			log.TPrintf("  Android flavors: %s", strings.Join(proj.androidFlavors, ", "))
+		}
+		proj.iosFlavors = schemeFlavors(proj.xcodeProjectPaths)
+		if len(proj.iosFlavors) > 0 {
+			log.TPrintf("  iOS flavors: %s", strings.Join(proj.iosFlavors, ", "))
 		}
 
+		if metadata := proj.sdkVersion.Metadata(); len(metadata) > 0 {
+			scanner.metadata[proj.path] = metadata
+		}
 		scanner.projects = append(scanner.projects, proj)
 	}
 
@@ -236,6 +360,24 @@ projects:
 	return true, nil
 }
 
+// projectSchemes returns the schemes of an Xcode project, which is not embedded in a workspace.
+func projectSchemes(xcodeProjectPath string) ([]string, error) {
+	xcodeProj, err := xcodeproj.Open(xcodeProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	schemes, err := xcodeProj.Schemes()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, scheme := range schemes {
+		names = append(names, scheme.Name)
+	}
+	return names, nil
+}
+
 // ExcludedScannerNames ...
 func (Scanner) ExcludedScannerNames() []string {
 	return []string{
@@ -247,90 +389,142 @@ func (Scanner) ExcludedScannerNames() []string {
 // Options ...
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
 	flutterProjectLocationOption := models.NewOption(projectLocationInputTitle, projectLocationInputSummary, projectLocationInputEnvKey, models.TypeSelector)
+	warnings := models.Warnings{}
 
-	for _, project := range scanner.projects {
-		if project.hasTest {
-			flutterProjectHasTestOption := models.NewOption(testsInputTitle, testsInputSummary, "", models.TypeSelector)
-			flutterProjectLocationOption.AddOption(project.path, flutterProjectHasTestOption)
-
-			for _, v := range []string{"yes", "no"} {
-				cfg := configName
-				if v == "yes" {
-					cfg += "-test"
-				}
+	scanner.configDescriptors = map[string]configDescriptor{}
 
-				if project.hasIosProject || project.hasAndroidProject {
-					if project.hasIosProject {
-						projectPathOption := models.NewOption(ios.ProjectPathInputTitle, ios.ProjectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeSelector)
-						flutterProjectHasTestOption.AddOption(v, projectPathOption)
+	for _, project := range scanner.projects {
+		if sliceutil.IsStringInSlice(windowsPlatform, project.additionalPlatforms) {
+			warnings = append(warnings, fmt.Sprintf("The Windows app of the project (%s) is not built, as the Bitrise stacks can not build Windows apps.", project.path))
+		}
 
-						for xcodeWorkspacePath, schemes := range project.xcodeProjectPaths {
-							schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeSelector)
-							projectPathOption.AddOption(xcodeWorkspacePath, schemeOption)
+		descriptor := configDescriptor{flutterVersion: project.sdkVersion.Version}
+		if !project.hasTest {
+			if err := scanner.addPlatformOptions(flutterProjectLocationOption, project.path, project, descriptor); err != nil {
+				return models.OptionNode{}, warnings, nil, err
+			}
+			continue
+		}
 
-							for _, scheme := range schemes {
-								exportMethodOption := models.NewOption(ios.IosExportMethodInputTitle, ios.IosExportMethodInputSummary, ios.ExportMethodInputEnvKey, models.TypeSelector)
-								schemeOption.AddOption(scheme, exportMethodOption)
+		flutterProjectHasTestOption := models.NewOption(testsInputTitle, testsInputSummary, "", models.TypeSelector)
+		flutterProjectLocationOption.AddOption(project.path, flutterProjectHasTestOption)
 
-								for _, exportMethod := range ios.IosExportMethods {
-									configOption := models.NewConfigOption(cfg+"-app-"+getBuildablePlatform(project.hasAndroidProject, project.hasIosProject), nil)
-									exportMethodOption.AddConfig(exportMethod, configOption)
-								}
-							}
-						}
-					} else {
-						configOption := models.NewConfigOption(cfg+"-app-"+getBuildablePlatform(project.hasAndroidProject, project.hasIosProject), nil)
-						flutterProjectHasTestOption.AddOption(v, configOption)
-					}
-				} else {
-					configOption := models.NewConfigOption(cfg, nil)
-					flutterProjectHasTestOption.AddOption(v, configOption)
-				}
+		for _, v := range []string{"yes", "no"} {
+			descriptor.test = v == "yes"
+			if err := scanner.addPlatformOptions(flutterProjectHasTestOption, v, project, descriptor); err != nil {
+				return models.OptionNode{}, warnings, nil, err
 			}
-		} else {
-			cfg := configName
+		}
+	}
+
+	return *flutterProjectLocationOption, warnings, nil, nil
+}
+
+// addPlatformOptions adds the deploy platform (and flavor) options of the project to the parent option, under the given value.
+func (scanner *Scanner) addPlatformOptions(parent *models.OptionNode, value string, project project, descriptor configDescriptor) error {
+	platforms := project.deployPlatforms()
+	if len(platforms) == 0 {
+		caches, err := project.dependencyCaches("")
+		if err != nil {
+			return err
+		}
+		descriptor.caches = caches
+		scanner.addConfig(parent, value, descriptor)
+		return nil
+	}
 
-			if project.hasIosProject || project.hasAndroidProject {
-				if project.hasIosProject {
-					projectPathOption := models.NewOption(ios.ProjectPathInputTitle, ios.ProjectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeSelector)
-					flutterProjectLocationOption.AddOption(project.path, projectPathOption)
+	platformOption := models.NewOption(platformInputTitle, platformInputSummary, "", models.TypeSelector)
+	parent.AddOption(value, platformOption)
 
-					for xcodeWorkspacePath, schemes := range project.xcodeProjectPaths {
-						schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeSelector)
-						projectPathOption.AddOption(xcodeWorkspacePath, schemeOption)
+	for _, platform := range platforms {
+		caches, err := project.dependencyCaches(platform)
+		if err != nil {
+			return err
+		}
+		descriptor.platform = platform
+		descriptor.caches = caches
+		flavors := project.platformFlavors(platform)
+		if len(flavors) == 0 {
+			scanner.addIOSOptions(platformOption, platform, project, descriptor, "")
+			continue
+		}
 
-						for _, scheme := range schemes {
-							exportMethodOption := models.NewOption(ios.IosExportMethodInputTitle, ios.IosExportMethodInputSummary, ios.ExportMethodInputEnvKey, models.TypeSelector)
-							schemeOption.AddOption(scheme, exportMethodOption)
+		flavorOption := models.NewOption(flavorInputTitle, flavorInputSummary, flavorInputEnvKey, models.TypeSelector)
+		platformOption.AddOption(platform, flavorOption)
 
-							for _, exportMethod := range ios.IosExportMethods {
-								configOption := models.NewConfigOption(cfg+"-app-"+getBuildablePlatform(project.hasAndroidProject, project.hasIosProject), nil)
-								exportMethodOption.AddConfig(exportMethod, configOption)
-							}
-						}
-					}
-				} else {
-					configOption := models.NewConfigOption(cfg+"-app-"+getBuildablePlatform(project.hasAndroidProject, project.hasIosProject), nil)
-					flutterProjectLocationOption.AddOption(project.path, configOption)
+		flavorDescriptor := descriptor
+		flavorDescriptor.flavor = true
+		for _, flavor := range flavors {
+			scanner.addIOSOptions(flavorOption, flavor, project, flavorDescriptor, flavor)
+		}
+	}
+	return nil
+}
+
+// addIOSOptions adds the iOS app options of the project (if the platform includes iOS) to the parent option, under the given value.
+// The schemes are filtered to the ones of the flavor, if it is set.
+func (scanner *Scanner) addIOSOptions(parent *models.OptionNode, value string, project project, descriptor configDescriptor, flavor string) {
+	if !hasIOSPlatform(descriptor.platform) {
+		scanner.addConfig(parent, value, descriptor)
+		return
+	}
+
+	projectPathOption := models.NewOption(ios.ProjectPathInputTitle, ios.ProjectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeSelector)
+	parent.AddOption(value, projectPathOption)
+
+	for xcodeWorkspacePath, schemes := range project.xcodeProjectPaths {
+		schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeSelector)
+		projectPathOption.AddOption(xcodeWorkspacePath, schemeOption)
+
+		if flavor != "" {
+			schemes = flavorSchemes(schemes, flavor)
+		}
+		for _, scheme := range schemes {
+			configurations, err := ios.DetectSchemeConfigurations(".", scheme, []string{xcodeWorkspacePath, project.xcodeProjectPath()}, []string{project.xcodeProjectPath()})
+			if err != nil {
+				log.TWarnf("Failed to detect the build configurations of scheme %s, error: %s", scheme, err)
+			}
+
+			schemeDescriptor := descriptor
+			schemeDescriptor.iosConfigurations = configurations.HasConfigurations()
+			exportMethodOptions := configurations.AddOptions(schemeOption, scheme, false, func(parent *models.OptionNode, value string) []*models.OptionNode {
+				exportMethodOption := models.NewOption(ios.IosExportMethodInputTitle, ios.IosExportMethodInputSummary, ios.ExportMethodInputEnvKey, models.TypeSelector)
+				parent.AddOption(value, exportMethodOption)
+				return []*models.OptionNode{exportMethodOption}
+			})
+
+			for _, exportMethodOption := range exportMethodOptions {
+				for _, exportMethod := range ios.IosExportMethods {
+					scanner.addConfig(exportMethodOption, exportMethod, schemeDescriptor)
 				}
-			} else {
-				configOption := models.NewConfigOption(cfg, nil)
-				flutterProjectLocationOption.AddOption(project.path, configOption)
 			}
 		}
 	}
+}
+
+// xcodeProjectPath returns the path of the Runner Xcode project of the project.
+func (proj project) xcodeProjectPath() string {
+	return filepath.Join(proj.path, "ios", "Runner.xcodeproj")
+}
 
-	return *flutterProjectLocationOption, models.Warnings{}, nil, nil
+// addConfig adds the config of the descriptor to the parent option, under the given value.
+// The configs shared by several projects cache the dependencies of each.
+func (scanner *Scanner) addConfig(parent *models.OptionNode, value string, descriptor configDescriptor) {
+	if existing, ok := scanner.configDescriptors[descriptor.configName()]; ok {
+		descriptor.caches = utility.MergeDependencyCaches(append(existing.caches, descriptor.caches...)...)
+	}
+	scanner.configDescriptors[descriptor.configName()] = descriptor
+	parent.AddConfig(value, models.NewConfigOption(descriptor.configName(), nil))
 }
 
 func getBuildablePlatform(hasAndroidProject, hasIosProject bool) string {
 	switch {
 	case hasAndroidProject && !hasIosProject:
-		return "android"
+		return androidPlatform
 	case !hasAndroidProject && hasIosProject:
-		return "ios"
+		return iosPlatform
 	default:
-		return "both"
+		return bothPlatform
 	}
 }
 
@@ -342,36 +536,29 @@ func (Scanner) DefaultOptions() models.OptionNode {
 	flutterProjectLocationOption.AddOption("", flutterProjectHasTestOption)
 
 	for _, v := range []string{"yes", "no"} {
-		cfg := configName
-		if v == "yes" {
-			cfg += "-test"
-		}
 		flutterPlatformOption := models.NewOption(platformInputTitle, platformInputSummary, "", models.TypeSelector)
 		flutterProjectHasTestOption.AddOption(v, flutterPlatformOption)
 
 		for _, platform := range platforms {
-			if platform != "none" {
-				if platform != "android" {
-					projectPathOption := models.NewOption(ios.ProjectPathInputTitle, ios.ProjectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeUserInput)
-					flutterPlatformOption.AddOption(platform, projectPathOption)
+			descriptor := defaultConfigDescriptor(v == "yes", platform)
+			if !hasIOSPlatform(platform) {
+				configOption := models.NewConfigOption(descriptor.configName(), nil)
+				flutterPlatformOption.AddConfig(platform, configOption)
+				continue
+			}
 
-					schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
-					projectPathOption.AddOption("", schemeOption)
+			projectPathOption := models.NewOption(ios.ProjectPathInputTitle, ios.ProjectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeUserInput)
+			flutterPlatformOption.AddOption(platform, projectPathOption)
 
-					exportMethodOption := models.NewOption(ios.IosExportMethodInputTitle, ios.IosExportMethodInputSummary, ios.ExportMethodInputEnvKey, models.TypeSelector)
-					schemeOption.AddOption("", exportMethodOption)
+			schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
+			projectPathOption.AddOption("", schemeOption)
 
-					for _, exportMethod := range ios.IosExportMethods {
-						configOption := models.NewConfigOption(cfg+"-app-"+platform, nil)
-						exportMethodOption.AddConfig(exportMethod, configOption)
-					}
-				} else {
-					configOption := models.NewConfigOption(cfg+"-app-"+platform, nil)
-					flutterPlatformOption.AddConfig(platform, configOption)
-				}
-			} else {
-				configOption := models.NewConfigOption(cfg, nil)
-				flutterPlatformOption.AddConfig(platform, configOption)
+			exportMethodOption := models.NewOption(ios.IosExportMethodInputTitle, ios.IosExportMethodInputSummary, ios.ExportMethodInputEnvKey, models.TypeSelector)
+			schemeOption.AddOption("", exportMethodOption)
+
+			for _, exportMethod := range ios.IosExportMethods {
+				configOption := models.NewConfigOption(descriptor.configName(), nil)
+				exportMethodOption.AddConfig(exportMethod, configOption)
 			}
 		}
 	}
@@ -380,108 +567,196 @@ func (Scanner) DefaultOptions() models.OptionNode {
 }
 
 // Configs ...
-func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	return scanner.DefaultConfigs()
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configs := models.BitriseConfigMap{}
+	for name, descriptor := range scanner.configDescriptors {
+		config, err := generateConfig(descriptor, triggerPreset)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+		configs[name] = config
+	}
+	return configs, nil
+}
+
+// dependencyCaches returns the dependency caches of the project used by the config building the platform:
+// the pub cache, and the caches of the native projects built for the platform.
+func (proj project) dependencyCaches(platform string) ([]steps.DependencyCache, error) {
+	caches, err := utility.DetectDependencyCaches(".", []string{proj.path}, utility.PubDependencyManager)
+	if err != nil {
+		return nil, err
+	}
+
+	if proj.hasAndroidProject && (platform == androidPlatform || platform == bothPlatform) {
+		androidCaches, err := utility.DetectDependencyCaches(".", []string{filepath.Join(proj.path, "android")}, utility.GradleDependencyManager)
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, androidCaches...)
+	}
+
+	if proj.hasIosProject && hasIOSPlatform(platform) {
+		iosCaches, err := utility.DetectDependencyCaches(".", []string{filepath.Join(proj.path, "ios")}, utility.CocoaPodsDependencyManager)
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, iosCaches...)
+	}
+
+	return caches, nil
 }
 
 // DefaultConfigs ...
-func (scanner Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
+func (Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
 	configs := models.BitriseConfigMap{}
+	for _, test := range []bool{false, true} {
+		for _, platform := range platforms {
+			descriptor := defaultConfigDescriptor(test, platform)
+			config, err := generateConfig(descriptor, triggerPreset)
+			if err != nil {
+				return models.BitriseConfigMap{}, err
+			}
+			configs[descriptor.configName()] = config
+		}
+	}
+	return configs, nil
+}
 
-	for _, variant := range []struct {
-		configID string
-		test     bool
-		deploy   bool
-		platform string
-	}{
-		{test: false, deploy: false, configID: configName},
-		{test: true, deploy: false, configID: configName + "-test"},
-		{test: false, deploy: true, platform: "both", configID: configName + "-app-both"},
-		{test: true, deploy: true, platform: "both", configID: configName + "-test-app-both"},
-		{test: false, deploy: true, platform: "android", configID: configName + "-app-android"},
-		{test: true, deploy: true, platform: "android", configID: configName + "-test-app-android"},
-		{test: false, deploy: true, platform: "ios", configID: configName + "-app-ios"},
-		{test: true, deploy: true, platform: "ios", configID: configName + "-test-app-ios"},
-	} {
-		configBuilder := models.NewDefaultConfigBuilder()
-
-		// primary
-
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
-
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterInstallStepListItem(
-			envmanModels.EnvironmentItemModel{installerUpdateFlutterKey: "false"},
-		))
+func defaultConfigDescriptor(test bool, platform string) configDescriptor {
+	descriptor := configDescriptor{test: test}
+	if platform != "none" {
+		descriptor.platform = platform
+	}
+	return descriptor
+}
+
+func generateConfig(descriptor configDescriptor, triggerPreset models.TriggerPreset) (string, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+	caches := descriptor.caches
+
+	// primary
 
-		// cache-pull is after flutter-installer, to prevent removal of pub system cache
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CachePullStepListItem())
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterAnalyzeStepListItem(
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, flutterInstallStepListItem(descriptor.flutterVersion))
+
+	// cache-pull is after flutter-installer, to prevent removal of pub system cache
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, restoreCacheStepList(caches...)...)
+
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterAnalyzeStepListItem(
+		envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
+	))
+
+	if descriptor.test {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterTestStepListItem(
 			envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
 		))
+	}
 
-		if variant.test {
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterTestStepListItem(
-				envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-			))
-		}
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(true, caches...)...)
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(true)...)
+	// deploy
 
-		// deploy
+	if descriptor.platform != "" {
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
 
-		if variant.deploy {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
+		if hasIOSPlatform(descriptor.platform) {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+		}
 
-			if variant.platform != "android" {
-				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
-			}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, flutterInstallStepListItem(descriptor.flutterVersion))
 
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterInstallStepListItem(
-				envmanModels.EnvironmentItemModel{installerUpdateFlutterKey: "false"},
-			))
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, restoreCacheStepList(caches...)...)
 
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CachePullStepListItem())
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterAnalyzeStepListItem(
+			envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
+		))
 
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterAnalyzeStepListItem(
+		if descriptor.test {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterTestStepListItem(
 				envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
 			))
+		}
 
-			if variant.test {
-				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterTestStepListItem(
-					envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-				))
-			}
-
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterBuildStepListItem(
-				envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-				envmanModels.EnvironmentItemModel{platformInputKey: variant.platform},
+		if isMobilePlatform(descriptor.platform) {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, flutterBuildStepListItem(descriptor))
+		} else {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ScriptSteplistItem("flutter build "+descriptor.platform,
+				envmanModels.EnvironmentItemModel{"content": buildScript(descriptor.platform)},
+				envmanModels.EnvironmentItemModel{"working_dir": "$" + projectLocationInputEnvKey},
 			))
+		}
 
-			if variant.platform != "android" {
-				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
-					envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
-					envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
-					envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
-					envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: defaultIOSConfiguration},
-				))
+		if hasIOSPlatform(descriptor.platform) {
+			// without a detected configuration, the scheme is archived with the configuration of its archive action
+			xcodeArchiveInputs := []envmanModels.EnvironmentItemModel{
+				{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
+				{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
+				{ios.DistributionMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
 			}
-
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(true)...)
+			if descriptor.iosConfigurations {
+				xcodeArchiveInputs = append(xcodeArchiveInputs, envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "$" + ios.ConfigurationInputEnvKey})
+			}
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(xcodeArchiveInputs...))
 		}
 
-		config, err := configBuilder.Generate(scannerName)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(true, caches...)...)
+	}
 
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
+	config, err := configBuilder.Generate(scannerName)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// flutterBuildStepListItem returns the flutter-build step building the mobile platform, and the selected flavor if the config is flavored.
+func flutterBuildStepListItem(descriptor configDescriptor) bitriseModels.StepListItemModel {
+	inputs := []envmanModels.EnvironmentItemModel{
+		{projectLocationInputKey: "$" + projectLocationInputEnvKey},
+		{platformInputKey: descriptor.platform},
+	}
+	if descriptor.flavor {
+		params := "--release --flavor $" + flavorInputEnvKey
+		if descriptor.platform != iosPlatform {
+			inputs = append(inputs, envmanModels.EnvironmentItemModel{androidAdditionalParamsInputKey: params})
+		}
+		if hasIOSPlatform(descriptor.platform) {
+			inputs = append(inputs, envmanModels.EnvironmentItemModel{iosAdditionalParamsInputKey: params})
 		}
+	}
+	return steps.FlutterBuildStepListItem(inputs...)
+}
+
+// buildScript returns the script building the web or desktop platform, and compressing its output into the deploy dir.
+func buildScript(platform string) string {
+	return fmt.Sprintf(`#!/usr/bin/env bash
+set -ex
+
+flutter build %s --release
 
-		configs[variant.configID] = string(data)
+cd %s
+zip -r "$BITRISE_DEPLOY_DIR/%s.zip" .`, platform, additionalPlatformOutputDirs[platform], platform)
+}
+
+// flutterInstallStepListItem returns the flutter-installer step installing the given version (or channel),
+// the preinstalled Flutter is used if the version is empty.
+func flutterInstallStepListItem(version string) bitriseModels.StepListItemModel {
+	inputs := []envmanModels.EnvironmentItemModel{{installerUpdateFlutterKey: "false"}}
+	if version != "" {
+		inputs = append(inputs, envmanModels.EnvironmentItemModel{installerVersionKey: version})
 	}
+	return steps.FlutterInstallStepListItem(inputs...)
+}
 
-	return configs, nil
+func restoreCacheStepList(caches ...steps.DependencyCache) []bitriseModels.StepListItemModel {
+	if len(caches) == 0 {
+		return []bitriseModels.StepListItemModel{steps.CachePullStepListItem()}
+	}
+	return steps.RestoreCacheStepList(caches...)
 }
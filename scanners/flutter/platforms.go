@@ -0,0 +1,159 @@
+package flutter
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/scanners/android"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// The deploy platforms: the mobile platforms built by the flutter-build step,
+// and the desktop and web platforms built by the flutter CLI.
+const (
+	bothPlatform    = "both"
+	androidPlatform = "android"
+	iosPlatform     = "ios"
+	webPlatform     = "web"
+	macosPlatform   = "macos"
+	linuxPlatform   = "linux"
+	windowsPlatform = "windows"
+)
+
+// additionalPlatforms are detected by their platform folder in the project.
+var additionalPlatforms = []string{webPlatform, macosPlatform, linuxPlatform, windowsPlatform}
+
+// additionalPlatformOutputDirs are the build outputs of the additional platforms, relative to the project.
+var additionalPlatformOutputDirs = map[string]string{
+	webPlatform:   "build/web",
+	macosPlatform: "build/macos/Build/Products/Release",
+	linuxPlatform: "build/linux/x64/release/bundle",
+}
+
+const iosRunnerScheme = "Runner"
+
+// isMobilePlatform returns true for the platforms built by the flutter-build step, which support flavors.
+func isMobilePlatform(platform string) bool {
+	return platform == bothPlatform || platform == androidPlatform || platform == iosPlatform
+}
+
+func hasIOSPlatform(platform string) bool {
+	return platform == bothPlatform || platform == iosPlatform
+}
+
+// deployPlatforms returns the platforms, which can be built by the deploy workflow of the project.
+func (proj project) deployPlatforms() []string {
+	var platforms []string
+	if proj.hasAndroidProject || proj.hasIosProject {
+		platforms = append(platforms, getBuildablePlatform(proj.hasAndroidProject, proj.hasIosProject))
+	}
+	if proj.hasAndroidProject && proj.hasIosProject {
+		platforms = append(platforms, androidPlatform, iosPlatform)
+	}
+	for _, platform := range proj.additionalPlatforms {
+		// Windows apps can not be built on the Bitrise stacks
+		if platform != windowsPlatform {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+// detectAdditionalPlatforms returns the web and desktop platforms, which have a platform folder in the project.
+func detectAdditionalPlatforms(projectLocation string) ([]string, error) {
+	var platforms []string
+	for _, platform := range additionalPlatforms {
+		if exist, err := pathutil.IsDirExists(filepath.Join(projectLocation, platform)); err != nil {
+			return nil, err
+		} else if exist {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms, nil
+}
+
+// detectAndroidFlavors returns the productFlavors of the Android app module.
+func detectAndroidFlavors(projectLocation string) ([]string, error) {
+	for _, buildScript := range []string{"build.gradle", "build.gradle.kts"} {
+		pth := filepath.Join(projectLocation, "android", "app", buildScript)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return nil, err
+		} else if !exist {
+			continue
+		}
+
+		content, err := fileutil.ReadStringFromFile(pth)
+		if err != nil {
+			return nil, err
+		}
+		return android.ProductFlavors(content), nil
+	}
+	return nil, nil
+}
+
+// iosFlavor returns the flavor of an iOS scheme: the schemes other than Runner (like dev or Runner-dev) are flavor schemes.
+func iosFlavor(scheme string) string {
+	if scheme == iosRunnerScheme {
+		return ""
+	}
+	return strings.TrimPrefix(scheme, iosRunnerScheme+"-")
+}
+
+// flavorSchemes returns the schemes building the flavor, all the schemes if none of them matches the flavor.
+func flavorSchemes(schemes []string, flavor string) []string {
+	var matching []string
+	for _, scheme := range schemes {
+		if iosFlavor(scheme) == flavor {
+			matching = append(matching, scheme)
+		}
+	}
+	if len(matching) == 0 {
+		return schemes
+	}
+	return matching
+}
+
+// schemeFlavors returns the flavors of the iOS flavor schemes, in alphabetical order.
+func schemeFlavors(xcodeProjectPaths map[string][]string) []string {
+	var flavors []string
+	for _, schemes := range xcodeProjectPaths {
+		for _, scheme := range schemes {
+			if flavor := iosFlavor(scheme); flavor != "" && !sliceutil.IsStringInSlice(flavor, flavors) {
+				flavors = append(flavors, flavor)
+			}
+		}
+	}
+	sort.Strings(flavors)
+	return flavors
+}
+
+// platformFlavors returns the flavors, which can be built for the mobile platform:
+// the Android flavors, the iOS flavors, or the flavors of both apps.
+func (proj project) platformFlavors(platform string) []string {
+	switch platform {
+	case androidPlatform:
+		return proj.androidFlavors
+	case iosPlatform:
+		return proj.iosFlavors
+	case bothPlatform:
+		if !proj.hasAndroidProject {
+			return proj.iosFlavors
+		}
+		if !proj.hasIosProject {
+			return proj.androidFlavors
+		}
+
+		var flavors []string
+		for _, flavor := range proj.androidFlavors {
+			if sliceutil.IsStringInSlice(flavor, proj.iosFlavors) {
+				flavors = append(flavors, flavor)
+			}
+		}
+		return flavors
+	default:
+		return nil
+	}
+}
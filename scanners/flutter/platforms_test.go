@@ -0,0 +1,217 @@
+package flutter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAndroidFlavors(t *testing.T) {
+	tests := []struct {
+		name        string
+		buildScript string
+		content     string
+		want        []string
+	}{
+		{
+			name:        "no build script",
+			buildScript: "",
+			want:        nil,
+		},
+		{
+			name:        "groovy with comments",
+			buildScript: "build.gradle",
+			content: `android {
+    flavorDimensions "env"
+    productFlavors {
+        // staging { dimension "env" }
+        dev { dimension "env" }
+        /* qa { dimension "env" } */
+        prod { dimension "env" }
+    }
+}`,
+			want: []string{"dev", "prod"},
+		},
+		{
+			name:        "kotlin dsl",
+			buildScript: "build.gradle.kts",
+			content: `android {
+    productFlavors {
+        create("dev") { dimension = "env" }
+        create("prod") { dimension = "env" }
+    }
+}`,
+			want: []string{"dev", "prod"},
+		},
+		{
+			name:        "no product flavors",
+			buildScript: "build.gradle",
+			content:     `android { buildTypes { release { } } }`,
+			want:        nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectLocation := t.TempDir()
+			if tt.buildScript != "" {
+				appDir := filepath.Join(projectLocation, "android", "app")
+				require.NoError(t, os.MkdirAll(appDir, 0755))
+				require.NoError(t, ioutil.WriteFile(filepath.Join(appDir, tt.buildScript), []byte(tt.content), 0644))
+			}
+
+			flavors, err := detectAndroidFlavors(projectLocation)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, flavors)
+		})
+	}
+}
+
+func TestIOSFlavor(t *testing.T) {
+	tests := []struct {
+		scheme string
+		want   string
+	}{
+		{scheme: "Runner", want: ""},
+		{scheme: "dev", want: "dev"},
+		{scheme: "Runner-prod", want: "prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			require.Equal(t, tt.want, iosFlavor(tt.scheme))
+		})
+	}
+}
+
+func TestFlavorSchemes(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemes []string
+		flavor  string
+		want    []string
+	}{
+		{
+			name:    "flavor scheme",
+			schemes: []string{"Runner", "dev", "prod"},
+			flavor:  "dev",
+			want:    []string{"dev"},
+		},
+		{
+			name:    "prefixed flavor scheme",
+			schemes: []string{"Runner", "Runner-dev", "Runner-prod"},
+			flavor:  "prod",
+			want:    []string{"Runner-prod"},
+		},
+		{
+			name:    "no matching scheme",
+			schemes: []string{"Runner"},
+			flavor:  "dev",
+			want:    []string{"Runner"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, flavorSchemes(tt.schemes, tt.flavor))
+		})
+	}
+}
+
+func TestSchemeFlavors(t *testing.T) {
+	tests := []struct {
+		name              string
+		xcodeProjectPaths map[string][]string
+		want              []string
+	}{
+		{
+			name:              "no flavor schemes",
+			xcodeProjectPaths: map[string][]string{"ios/Runner.xcworkspace": {"Runner"}},
+			want:              nil,
+		},
+		{
+			name: "flavor schemes of several containers",
+			xcodeProjectPaths: map[string][]string{
+				"ios/Runner.xcworkspace": {"Runner", "prod", "dev"},
+				"ios/Runner.xcodeproj":   {"Runner-dev", "Runner-qa"},
+			},
+			want: []string{"dev", "prod", "qa"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, schemeFlavors(tt.xcodeProjectPaths))
+		})
+	}
+}
+
+func TestPlatformFlavors(t *testing.T) {
+	proj := project{
+		hasAndroidProject: true,
+		hasIosProject:     true,
+		androidFlavors:    []string{"dev", "staging", "prod"},
+		iosFlavors:        []string{"dev", "prod", "qa"},
+	}
+
+	tests := []struct {
+		name     string
+		project  project
+		platform string
+		want     []string
+	}{
+		{name: "android", project: proj, platform: androidPlatform, want: []string{"dev", "staging", "prod"}},
+		{name: "ios", project: proj, platform: iosPlatform, want: []string{"dev", "prod", "qa"}},
+		{name: "both", project: proj, platform: bothPlatform, want: []string{"dev", "prod"}},
+		{name: "web", project: proj, platform: webPlatform, want: nil},
+		{
+			name:     "both without an iOS project",
+			project:  project{hasAndroidProject: true, androidFlavors: []string{"dev"}},
+			platform: bothPlatform,
+			want:     []string{"dev"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.project.platformFlavors(tt.platform))
+		})
+	}
+}
+
+func TestDeployPlatforms(t *testing.T) {
+	tests := []struct {
+		name    string
+		project project
+		want    []string
+	}{
+		{
+			name:    "no platforms",
+			project: project{},
+			want:    nil,
+		},
+		{
+			name:    "android",
+			project: project{hasAndroidProject: true},
+			want:    []string{androidPlatform},
+		},
+		{
+			name:    "ios",
+			project: project{hasIosProject: true},
+			want:    []string{iosPlatform},
+		},
+		{
+			name:    "android and ios",
+			project: project{hasAndroidProject: true, hasIosProject: true},
+			want:    []string{bothPlatform, androidPlatform, iosPlatform},
+		},
+		{
+			name:    "additional platforms without windows",
+			project: project{hasIosProject: true, additionalPlatforms: []string{webPlatform, macosPlatform, windowsPlatform}},
+			want:    []string{iosPlatform, webPlatform, macosPlatform},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.project.deployPlatforms())
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package flutter
+
+import (
+	"bufio"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	fvmrcFileName          = ".fvmrc"
+	fvmConfigFilePth       = ".fvm/fvm_config.json"
+	flutterVersionFileName = ".flutter-version"
+	toolVersionsFileName   = ".tool-versions"
+	pubspecFileName        = "pubspec.yaml"
+
+	stableChannel = "stable"
+)
+
+// flutterChannels are the release channels, which can be installed instead of a version.
+var flutterChannels = []string{"stable", "beta", "dev", "master", "main"}
+
+var (
+	// 3.19.6 / 3.22.0-0.1.pre
+	flutterVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[\w.]+)?$`)
+	// 3.19.6-stable (the asdf plugin's version format)
+	channelSuffixPattern = regexp.MustCompile(`-(stable|beta|dev)$`)
+	// 3.19.6 / =3.19.6 / "3.19.6"
+	exactConstraintPattern = regexp.MustCompile(`^["']?=?\s*(\d+\.\d+\.\d+)["']?$`)
+)
+
+// sdkVersion is the Flutter SDK version (or channel) required by a project.
+type sdkVersion struct {
+	// Version is a version (like 3.19.6) or a channel (like stable), installable by the flutter-installer step.
+	Version string
+	// Source is the file defining the version, relative to the search dir.
+	Source string
+	// Constraint is the pubspec.yaml environment.flutter constraint, if the version is resolved from it.
+	Constraint string
+}
+
+// Metadata returns the detected version for the scan result.
+func (version sdkVersion) Metadata() map[string]string {
+	if version.Version == "" {
+		return nil
+	}
+	metadata := map[string]string{
+		"flutter_version":        version.Version,
+		"flutter_version_source": version.Source,
+	}
+	if version.Constraint != "" {
+		metadata["flutter_version_constraint"] = version.Constraint
+	}
+	return metadata
+}
+
+// detectSDKVersion resolves the Flutter SDK version required by the project at projectLocation, from (in priority order):
+// the FVM config (.fvmrc or .fvm/fvm_config.json), .flutter-version, the asdf .tool-versions (in the project or the search dir),
+// and the pubspec.yaml environment.flutter constraint.
+func detectSDKVersion(projectLocation string) (sdkVersion, error) {
+	dirs := []string{projectLocation}
+	if filepath.Clean(projectLocation) != "." {
+		// the version files of a monorepo are in its root
+		dirs = append(dirs, ".")
+	}
+
+	for _, dir := range dirs {
+		for _, source := range []struct {
+			pth   string
+			parse func(string) string
+		}{
+			{pth: fvmrcFileName, parse: parseFVMConfig},
+			{pth: fvmConfigFilePth, parse: parseFVMConfig},
+			{pth: flutterVersionFileName, parse: parseFlutterVersionFile},
+			{pth: toolVersionsFileName, parse: parseToolVersions},
+		} {
+			pth := filepath.Join(dir, source.pth)
+			if exist, err := pathutil.IsPathExists(pth); err != nil {
+				return sdkVersion{}, err
+			} else if !exist {
+				continue
+			}
+
+			content, err := fileutil.ReadStringFromFile(pth)
+			if err != nil {
+				return sdkVersion{}, err
+			}
+			if version := source.parse(content); version != "" {
+				return sdkVersion{Version: version, Source: pth}, nil
+			}
+		}
+	}
+
+	pth := filepath.Join(projectLocation, pubspecFileName)
+	content, err := fileutil.ReadStringFromFile(pth)
+	if err != nil {
+		return sdkVersion{}, err
+	}
+	constraint, err := parsePubspecFlutterConstraint(content)
+	if err != nil {
+		return sdkVersion{}, err
+	}
+	if constraint == "" {
+		return sdkVersion{}, nil
+	}
+	return sdkVersion{Version: versionForConstraint(constraint), Source: pth, Constraint: constraint}, nil
+}
+
+// parseFVMConfig returns the version of an FVM config: {"flutter": "3.19.6"} (.fvmrc, FVM 3)
+// or {"flutterSdkVersion": "3.19.6"} (.fvm/fvm_config.json, FVM 2).
+func parseFVMConfig(content string) string {
+	var config struct {
+		Flutter           string `json:"flutter"`
+		FlutterSdkVersion string `json:"flutterSdkVersion"`
+	}
+	if err := json.Unmarshal([]byte(content), &config); err != nil {
+		return ""
+	}
+
+	version := config.Flutter
+	if version == "" {
+		version = config.FlutterSdkVersion
+	}
+	// 3.19.6@beta: a version from a specific channel
+	return normalizeVersion(strings.Split(version, "@")[0])
+}
+
+func parseFlutterVersionFile(content string) string {
+	return normalizeVersion(strings.TrimSpace(content))
+}
+
+// parseToolVersions returns the flutter version of an asdf .tool-versions file: flutter 3.19.6-stable
+func parseToolVersions(content string) string {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(strings.Split(scanner.Text(), "#")[0])
+		if len(fields) >= 2 && fields[0] == "flutter" {
+			return normalizeVersion(channelSuffixPattern.ReplaceAllString(fields[1], ""))
+		}
+	}
+	return ""
+}
+
+// parsePubspecFlutterConstraint returns the environment.flutter constraint of pubspec.yaml.
+func parsePubspecFlutterConstraint(content string) (string, error) {
+	var spec struct {
+		Environment map[string]string `yaml:"environment"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &spec); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(spec.Environment["flutter"]), nil
+}
+
+// versionForConstraint returns the pinned version of an exact constraint (3.19.6),
+// and the stable channel for a version range (>=3.10.0), which is satisfied by the latest release.
+func versionForConstraint(constraint string) string {
+	if match := exactConstraintPattern.FindStringSubmatch(constraint); match != nil {
+		return match[1]
+	}
+	return stableChannel
+}
+
+// normalizeVersion returns the version or channel, empty if it is not installable.
+func normalizeVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if flutterVersionPattern.MatchString(version) {
+		return version
+	}
+	for _, channel := range flutterChannels {
+		if version == channel {
+			return version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,170 @@
+package flutter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPubspec = `name: app
+environment:
+  sdk: ">=3.0.0 <4.0.0"
+  flutter: ">=3.10.0"
+`
+
+func TestDetectSDKVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		files           map[string]string
+		projectLocation string
+		want            sdkVersion
+	}{
+		{
+			name: "fvmrc first",
+			files: map[string]string{
+				".fvmrc":               `{"flutter": "3.19.6"}`,
+				".fvm/fvm_config.json": `{"flutterSdkVersion": "3.16.0"}`,
+				".flutter-version":     "3.13.0",
+				".tool-versions":       "flutter 3.10.0-stable",
+				"pubspec.yaml":         testPubspec,
+			},
+			projectLocation: ".",
+			want:            sdkVersion{Version: "3.19.6", Source: ".fvmrc"},
+		},
+		{
+			name: "fvm config before flutter-version",
+			files: map[string]string{
+				".fvm/fvm_config.json": `{"flutterSdkVersion": "3.16.0"}`,
+				".flutter-version":     "3.13.0",
+				"pubspec.yaml":         testPubspec,
+			},
+			projectLocation: ".",
+			want:            sdkVersion{Version: "3.16.0", Source: ".fvm/fvm_config.json"},
+		},
+		{
+			name: "flutter-version before tool-versions",
+			files: map[string]string{
+				".flutter-version": "v3.13.0\n",
+				".tool-versions":   "flutter 3.10.0-stable",
+				"pubspec.yaml":     testPubspec,
+			},
+			projectLocation: ".",
+			want:            sdkVersion{Version: "3.13.0", Source: ".flutter-version"},
+		},
+		{
+			name: "tool-versions before pubspec",
+			files: map[string]string{
+				".tool-versions": "ruby 3.2.2\nflutter 3.10.0-stable # pinned\n",
+				"pubspec.yaml":   testPubspec,
+			},
+			projectLocation: ".",
+			want:            sdkVersion{Version: "3.10.0", Source: ".tool-versions"},
+		},
+		{
+			name: "pubspec constraint",
+			files: map[string]string{
+				"pubspec.yaml": testPubspec,
+			},
+			projectLocation: ".",
+			want:            sdkVersion{Version: "stable", Source: "pubspec.yaml", Constraint: ">=3.10.0"},
+		},
+		{
+			name: "no version",
+			files: map[string]string{
+				"pubspec.yaml": "name: app\n",
+			},
+			projectLocation: ".",
+			want:            sdkVersion{},
+		},
+		{
+			name: "channel of the fvm version stripped",
+			files: map[string]string{
+				".fvmrc":       `{"flutter": "3.22.0-0.1.pre@beta"}`,
+				"pubspec.yaml": testPubspec,
+			},
+			projectLocation: ".",
+			want:            sdkVersion{Version: "3.22.0-0.1.pre", Source: ".fvmrc"},
+		},
+		{
+			name: "fvm channel",
+			files: map[string]string{
+				".fvmrc":       `{"flutter": "beta"}`,
+				"pubspec.yaml": testPubspec,
+			},
+			projectLocation: ".",
+			want:            sdkVersion{Version: "beta", Source: ".fvmrc"},
+		},
+		{
+			name: "invalid version skipped",
+			files: map[string]string{
+				".flutter-version": "latest",
+				"pubspec.yaml":     testPubspec,
+			},
+			projectLocation: ".",
+			want:            sdkVersion{Version: "stable", Source: "pubspec.yaml", Constraint: ">=3.10.0"},
+		},
+		{
+			name: "monorepo root fallback",
+			files: map[string]string{
+				".fvmrc":                `{"flutter": "3.19.6"}`,
+				"apps/app/pubspec.yaml": testPubspec,
+			},
+			projectLocation: "apps/app",
+			want:            sdkVersion{Version: "3.19.6", Source: ".fvmrc"},
+		},
+		{
+			name: "project version before the monorepo root",
+			files: map[string]string{
+				".fvmrc":                    `{"flutter": "3.19.6"}`,
+				"apps/app/.flutter-version": "3.16.0",
+				"apps/app/pubspec.yaml":     testPubspec,
+			},
+			projectLocation: "apps/app",
+			want:            sdkVersion{Version: "3.16.0", Source: "apps/app/.flutter-version"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchDir := t.TempDir()
+			for pth, content := range tt.files {
+				pth = filepath.Join(searchDir, pth)
+				require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+				require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+			}
+
+			// the project locations are relative to the search dir, which is the working directory of the scanners
+			wd, err := os.Getwd()
+			require.NoError(t, err)
+			require.NoError(t, os.Chdir(searchDir))
+			defer func() {
+				require.NoError(t, os.Chdir(wd))
+			}()
+
+			version, err := detectSDKVersion(tt.projectLocation)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, version)
+		})
+	}
+}
+
+func TestVersionForConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{constraint: "3.19.6", want: "3.19.6"},
+		{constraint: "=3.19.6", want: "3.19.6"},
+		{constraint: `"3.19.6"`, want: "3.19.6"},
+		{constraint: ">=3.10.0", want: "stable"},
+		{constraint: "^3.10.0", want: "stable"},
+		{constraint: ">=3.10.0 <4.0.0", want: "stable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			require.Equal(t, tt.want, versionForConstraint(tt.constraint))
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package ionic
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/scanners/android"
+	"github.com/bitrise-io/bitrise-init/scanners/ios"
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/bitrise-init/utility"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const capacitorConfigName = "capacitor-config"
+
+// capacitorProjectType is the project type of the Capacitor apps without Ionic.
+const capacitorProjectType = "capacitor"
+
+const (
+	capacitorDependencyPrefix = "@capacitor/"
+	capacitorSyncScriptTitle  = "Sync the web build to the native projects"
+
+	defaultCapacitorAndroidDir = "android"
+	defaultCapacitorIOSDir     = "ios"
+	defaultCapacitorWebDir     = "www"
+)
+
+// capacitorConfigNames are the Capacitor config files, the JSON config is generated into the native projects by cap sync.
+var capacitorConfigNames = []string{"capacitor.config.json", "capacitor.config.ts", "capacitor.config.js"}
+
+var (
+	// webDir: 'www' / "webDir": "dist"
+	capacitorWebDirPattern = regexp.MustCompile(`["']?\bwebDir["']?\s*:\s*["'` + "`" + `]([^"'` + "`" + `\n]+)`)
+	// android: { path: 'native/android' } / "ios": { "path": "native/ios" }
+	capacitorPlatformPathPattern = `["']?\b%s["']?\s*:\s*\{[^}]*["']?\bpath["']?\s*:\s*["'` + "`" + `]([^"'` + "`" + `\n]+)`
+)
+
+// capacitorProject is a Capacitor app, with or without Ionic.
+type capacitorProject struct {
+	// configPth is the Capacitor config file, relative to the search dir.
+	configPth string
+	isIonic   bool
+	webDir    string
+	// androidDir and iosDir are the native projects relative to the search dir, empty if not added to the app.
+	androidDir string
+	iosDir     string
+
+	hasBuildScript   bool
+	hasTest          bool
+	jsPackageManager utility.JSPackageManager
+}
+
+// capacitorConfigDescriptor describes the config generated for a Capacitor app.
+type capacitorConfigDescriptor struct {
+	android bool
+	ios     bool
+	hasTest bool
+}
+
+func (descriptor capacitorConfigDescriptor) configName() string {
+	name := strings.TrimSuffix(capacitorConfigName, "-config")
+	if descriptor.android {
+		name += "-android"
+	}
+	if descriptor.ios {
+		name += "-ios"
+	}
+	if descriptor.hasTest {
+		name += "-test"
+	}
+	return name + "-config"
+}
+
+// detectCapacitorProject returns the Capacitor app closest to the search dir,
+// or nil if no Capacitor config with the @capacitor/* dependencies is found.
+func detectCapacitorProject(searchDir string, fileList []string) (*capacitorProject, error) {
+	configPth := ""
+	for _, name := range capacitorConfigNames {
+		pth, err := FilterRootFile(fileList, name)
+		if err != nil {
+			return nil, err
+		}
+		if pth == "" || strings.Contains(pth, "node_modules") {
+			continue
+		}
+		if configPth == "" || len(strings.Split(pth, string(filepath.Separator))) < len(strings.Split(configPth, string(filepath.Separator))) {
+			configPth = pth
+		}
+	}
+	if configPth == "" {
+		return nil, nil
+	}
+
+	projectDir := filepath.Dir(configPth)
+	packageJSONPth := filepath.Join(projectDir, "package.json")
+	if exist, err := pathutil.IsPathExists(packageJSONPth); err != nil {
+		return nil, err
+	} else if !exist {
+		log.TPrintf("No package.json found next to %s", configPth)
+		return nil, nil
+	}
+
+	packages, err := utility.ParsePackagesJSON(packageJSONPth)
+	if err != nil {
+		return nil, err
+	}
+	hasCapacitorDependency := false
+	for _, dependencies := range []map[string]string{packages.Dependencies, packages.DevDependencies} {
+		for dependency := range dependencies {
+			hasCapacitorDependency = hasCapacitorDependency || strings.HasPrefix(dependency, capacitorDependencyPrefix)
+		}
+	}
+	if !hasCapacitorDependency {
+		log.TPrintf("No %s* dependency found in %s", capacitorDependencyPrefix, packageJSONPth)
+		return nil, nil
+	}
+
+	project := capacitorProject{configPth: configPth}
+	_, project.hasBuildScript = packages.Scripts["build"]
+	_, project.hasTest = packages.Scripts["test"]
+
+	if project.isIonic, err = pathutil.IsPathExists(filepath.Join(projectDir, "ionic.config.json")); err != nil {
+		return nil, err
+	}
+
+	content, err := fileutil.ReadStringFromFile(configPth)
+	if err != nil {
+		return nil, err
+	}
+	project.webDir = defaultCapacitorWebDir
+	androidDir, iosDir := defaultCapacitorAndroidDir, defaultCapacitorIOSDir
+	if match := capacitorWebDirPattern.FindStringSubmatch(content); match != nil {
+		project.webDir = match[1]
+	}
+	if match := regexp.MustCompile(fmt.Sprintf(capacitorPlatformPathPattern, "android")).FindStringSubmatch(content); match != nil {
+		androidDir = match[1]
+	}
+	if match := regexp.MustCompile(fmt.Sprintf(capacitorPlatformPathPattern, "ios")).FindStringSubmatch(content); match != nil {
+		iosDir = match[1]
+	}
+
+	if exist, err := pathutil.IsDirExists(filepath.Join(projectDir, androidDir)); err != nil {
+		return nil, err
+	} else if exist {
+		project.androidDir = filepath.Join(projectDir, androidDir)
+	}
+	if exist, err := pathutil.IsDirExists(filepath.Join(projectDir, iosDir)); err != nil {
+		return nil, err
+	} else if exist {
+		project.iosDir = filepath.Join(projectDir, iosDir)
+	}
+
+	if project.jsPackageManager, err = utility.DetectJSPackageManager(searchDir, projectDir); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// projectType returns the project type of the generated configs.
+func (project capacitorProject) projectType() string {
+	if project.isIonic {
+		return scannerName
+	}
+	return capacitorProjectType
+}
+
+// webDirWarning returns a warning if cap sync can not find the web assets:
+// the app has no build script to create them, and the web dir is not committed either.
+func (project capacitorProject) webDirWarning() (string, error) {
+	if project.hasBuildScript {
+		return "", nil
+	}
+
+	webDir := filepath.Join(filepath.Dir(project.configPth), project.webDir)
+	if exist, err := pathutil.IsDirExists(webDir); err != nil || exist {
+		return "", err
+	}
+	return fmt.Sprintf(`No build script found in the package.json of the Capacitor app, and the web assets directory (%s) is not committed.
+cap sync copies the web assets to the native projects, add a build script creating them.`, webDir), nil
+}
+
+func (project capacitorProject) String() string {
+	return fmt.Sprintf("config: %s, ionic: %v, web dir: %s, android: %s, ios: %s", project.configPth, project.isIonic, project.webDir, project.androidDir, project.iosDir)
+}
+
+// capacitorOptions returns the options of the native projects of the Capacitor app:
+// the Android project location, module and variant, and the Xcode project, scheme and export method.
+func (scanner *Scanner) capacitorOptions() (models.OptionNode, models.Warnings, models.Icons, error) {
+	warnings := models.Warnings{}
+	project := scanner.capacitorProject
+
+	if warning, err := project.webDirWarning(); err != nil {
+		return models.OptionNode{}, warnings, nil, err
+	} else if warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	var androidOptions *models.OptionNode
+	if project.androidDir != "" {
+		androidScanner := android.NewScanner()
+		androidScanner.ExcludeTest = true
+		androidScanner.ExcludeAppIcon = true
+		if detected, err := androidScanner.DetectPlatform(scanner.searchDir); err != nil {
+			return models.OptionNode{}, warnings, nil, err
+		} else if detected {
+			absAndroidDir, err := pathutil.AbsPath(project.androidDir)
+			if err != nil {
+				return models.OptionNode{}, warnings, nil, err
+			}
+			found := false
+			for _, projectRoot := range androidScanner.ProjectRoots {
+				found = found || projectRoot == absAndroidDir
+			}
+
+			if found {
+				androidScanner.ProjectRoots = []string{absAndroidDir}
+				options, warns, _, err := androidScanner.Options()
+				warnings = append(warnings, warns...)
+				if err != nil {
+					return models.OptionNode{}, warnings, nil, err
+				}
+				androidOptions = &options
+			}
+		}
+		if androidOptions == nil {
+			warnings = append(warnings, fmt.Sprintf("No Gradle project found in the Capacitor Android directory (%s).", project.androidDir))
+		}
+	}
+
+	var iosOptions *models.OptionNode
+	if project.iosDir != "" {
+		iosScanner := ios.NewScanner()
+		iosScanner.ExcludeAppIcon = true
+		iosScanner.SuppressPodFileParseError = true
+		if detected, err := iosScanner.DetectPlatform(scanner.searchDir); err != nil {
+			return models.OptionNode{}, warnings, nil, err
+		} else if detected {
+			options, warns, _, err := iosScanner.Options()
+			warnings = append(warnings, warns...)
+			if err != nil {
+				return models.OptionNode{}, warnings, nil, err
+			}
+
+			// only the Xcode project of the app (ios/App/App.xcworkspace) is offered
+			for pth := range options.ChildOptionMap {
+				if !strings.HasPrefix(filepath.Clean(pth), filepath.Clean(project.iosDir)+string(filepath.Separator)) {
+					delete(options.ChildOptionMap, pth)
+				}
+			}
+			if len(options.ChildOptionMap) > 0 {
+				iosOptions = &options
+			}
+		}
+		if iosOptions == nil {
+			warnings = append(warnings, fmt.Sprintf("No Xcode project found in the Capacitor iOS directory (%s).", project.iosDir))
+		}
+	}
+
+	if androidOptions == nil && iosOptions == nil {
+		return models.OptionNode{}, warnings, nil, errors.New("no native project found in the Capacitor app, add the Android or iOS platform by running 'npx cap add android' or 'npx cap add ios' and commit the native projects")
+	}
+
+	descriptor := capacitorConfigDescriptor{
+		android: androidOptions != nil,
+		ios:     iosOptions != nil,
+		hasTest: project.hasTest,
+	}
+	scanner.capacitorConfigDescriptor = descriptor
+
+	lastOptions := iosOptions
+	if lastOptions == nil {
+		lastOptions = androidOptions
+	}
+	for _, lastChild := range lastOptions.LastChilds() {
+		for _, child := range lastChild.ChildOptionMap {
+			child.Config = descriptor.configName()
+		}
+	}
+
+	if androidOptions == nil {
+		return *iosOptions, warnings, nil, nil
+	}
+	if iosOptions != nil {
+		androidOptions.RemoveConfigs()
+		androidOptions.AttachToLastChilds(iosOptions)
+	}
+	return *androidOptions, warnings, nil, nil
+}
+
+// capacitorConfigs generates the workflows running the web build and cap sync, followed by the native builds.
+func (scanner *Scanner) capacitorConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	project := scanner.capacitorProject
+	descriptor := scanner.capacitorConfigDescriptor
+
+	projectDir := filepath.Dir(project.configPth)
+	workdir, err := utility.RelPath(scanner.searchDir, projectDir)
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+	if workdir == "." {
+		workdir = ""
+	}
+
+	jsProjectDirs := []string{projectDir}
+	if project.jsPackageManager.InstallDir != "" {
+		jsProjectDirs = append(jsProjectDirs, project.jsPackageManager.InstallDir)
+	}
+	caches, err := utility.DetectDependencyCaches(scanner.searchDir, jsProjectDirs, utility.JSDependencyManagers...)
+	if err != nil {
+		return models.BitriseConfigMap{}, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+	}
+	if descriptor.android {
+		androidCaches, err := utility.DetectDependencyCaches(scanner.searchDir, []string{project.androidDir}, utility.GradleDependencyManager)
+		if err != nil {
+			return models.BitriseConfigMap{}, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+		}
+		caches = append(caches, androidCaches...)
+	}
+	if descriptor.ios {
+		iosCaches, err := utility.DetectDependencyCaches(scanner.searchDir, []string{filepath.Join(project.iosDir, "App")}, utility.CocoaPodsDependencyManager)
+		if err != nil {
+			return models.BitriseConfigMap{}, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+		}
+		caches = append(caches, iosCaches...)
+	}
+	caches = utility.MergeDependencyCaches(caches...)
+
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+	buildWorkflowID := models.PrimaryWorkflowID
+	if descriptor.hasTest {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, project.jsPackageManager.InstallStepListItem(workdir))
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, project.jsPackageManager.RunStepListItem("test", workdir))
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
+
+		buildWorkflowID = models.DeployWorkflowID
+	}
+
+	configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+	configBuilder.AppendStepListItemsTo(buildWorkflowID, project.jsPackageManager.InstallStepListItem(workdir))
+	if project.hasBuildScript {
+		configBuilder.AppendStepListItemsTo(buildWorkflowID, project.jsPackageManager.RunStepListItem("build", workdir))
+	}
+	syncInputs := []envmanModels.EnvironmentItemModel{
+		{"content": "#!/usr/bin/env bash\nset -ex\n\n" + project.jsPackageManager.ExecCommand("cap sync")},
+	}
+	if workdir != "" {
+		syncInputs = append(syncInputs, envmanModels.EnvironmentItemModel{"working_dir": workdir})
+	}
+	configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.ScriptSteplistItem(capacitorSyncScriptTitle, syncInputs...))
+
+	if descriptor.android {
+		configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
+			envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: "$" + android.ProjectLocationInputEnvKey + "/gradlew"},
+		))
+		configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.AndroidBuildStepListItem(
+			envmanModels.EnvironmentItemModel{android.ProjectLocationInputKey: "$" + android.ProjectLocationInputEnvKey},
+			envmanModels.EnvironmentItemModel{android.ModuleInputKey: "$" + android.ModuleInputEnvKey},
+			envmanModels.EnvironmentItemModel{android.VariantInputKey: "$" + android.VariantInputEnvKey},
+		))
+	}
+
+	if descriptor.ios {
+		// cap sync installs the pods of the iOS project
+		configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+		configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.XcodeArchiveStepListItem(
+			envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
+			envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
+			envmanModels.EnvironmentItemModel{ios.DistributionMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
+			envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
+		))
+	}
+
+	configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
+
+	config, err := configBuilder.Generate(project.projectType())
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+
+	return models.BitriseConfigMap{
+		descriptor.configName(): string(data),
+	}, nil
+}
@@ -0,0 +1,215 @@
+package ionic
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testCapacitorPackageJSON = `{
+  "name": "app",
+  "scripts": { "build": "ng build", "test": "ng test" },
+  "dependencies": { "@capacitor/core": "^6.0.0", "@ionic/angular": "^8.0.0" },
+  "devDependencies": { "@capacitor/cli": "^6.0.0" }
+}`
+
+const testCapacitorTSConfig = `import type { CapacitorConfig } from '@capacitor/cli';
+
+const config: CapacitorConfig = {
+  appId: 'io.ionic.starter',
+  appName: 'app',
+  webDir: 'dist/app',
+  android: {
+    path: 'native/android',
+  },
+  ios: {
+    path: 'native/ios',
+    contentInset: 'always',
+  },
+};
+
+export default config;
+`
+
+func TestDetectCapacitorProject(t *testing.T) {
+	npm := utility.JSPackageManager{Name: utility.NpmPackageManager}
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		dirs  []string
+		want  *capacitorProject
+	}{
+		{
+			name: "no capacitor config",
+			files: map[string]string{
+				"package.json":      testCapacitorPackageJSON,
+				"ionic.config.json": `{"name": "app", "type": "angular"}`,
+			},
+			want: nil,
+		},
+		{
+			name: "no capacitor dependency",
+			files: map[string]string{
+				"package.json":          `{"name": "app", "dependencies": {"react": "^18.0.0"}}`,
+				"capacitor.config.json": `{"appId": "io.sample", "webDir": "www"}`,
+			},
+			want: nil,
+		},
+		{
+			name: "capacitor config without package.json",
+			files: map[string]string{
+				"capacitor.config.json": `{"appId": "io.sample", "webDir": "www"}`,
+			},
+			want: nil,
+		},
+		{
+			name: "json config with the default native projects",
+			files: map[string]string{
+				"package.json":          `{"name": "app", "dependencies": {"@capacitor/core": "^6.0.0"}}`,
+				"capacitor.config.json": `{"appId": "io.sample", "appName": "app", "webDir": "www"}`,
+			},
+			dirs: []string{"android", "ios"},
+			want: &capacitorProject{
+				configPth:        "capacitor.config.json",
+				webDir:           "www",
+				androidDir:       "android",
+				iosDir:           "ios",
+				jsPackageManager: npm,
+			},
+		},
+		{
+			name: "ionic app with custom native project paths",
+			files: map[string]string{
+				"package.json":        testCapacitorPackageJSON,
+				"ionic.config.json":   `{"name": "app", "type": "angular"}`,
+				"capacitor.config.ts": testCapacitorTSConfig,
+			},
+			dirs: []string{"native/android", "native/ios", "android"},
+			want: &capacitorProject{
+				configPth:        "capacitor.config.ts",
+				isIonic:          true,
+				webDir:           "dist/app",
+				androidDir:       "native/android",
+				iosDir:           "native/ios",
+				hasBuildScript:   true,
+				hasTest:          true,
+				jsPackageManager: npm,
+			},
+		},
+		{
+			name: "native platform not added",
+			files: map[string]string{
+				"package.json":        testCapacitorPackageJSON,
+				"capacitor.config.js": `module.exports = { appId: 'io.sample', webDir: "build" };`,
+			},
+			dirs: []string{"ios"},
+			want: &capacitorProject{
+				configPth:        "capacitor.config.js",
+				webDir:           "build",
+				iosDir:           "ios",
+				hasBuildScript:   true,
+				hasTest:          true,
+				jsPackageManager: npm,
+			},
+		},
+		{
+			name: "app in a subdirectory",
+			files: map[string]string{
+				"package.json":                              `{"name": "monorepo", "private": true}`,
+				"apps/mobile/package.json":                  testCapacitorPackageJSON,
+				"apps/mobile/capacitor.config.json":         `{"appId": "io.sample", "webDir": "www"}`,
+				"node_modules/plugin/capacitor.config.json": `{"appId": "io.plugin", "webDir": "www"}`,
+			},
+			dirs: []string{"apps/mobile/android"},
+			want: &capacitorProject{
+				configPth:        "apps/mobile/capacitor.config.json",
+				webDir:           "www",
+				androidDir:       "apps/mobile/android",
+				hasBuildScript:   true,
+				hasTest:          true,
+				jsPackageManager: npm,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchDir := t.TempDir()
+			for name, content := range tt.files {
+				pth := filepath.Join(searchDir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+				require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+			}
+			for _, dir := range tt.dirs {
+				require.NoError(t, os.MkdirAll(filepath.Join(searchDir, dir), 0755))
+			}
+
+			// the file list is relative to the search dir, which is the working directory of the scanners
+			wd, err := os.Getwd()
+			require.NoError(t, err)
+			require.NoError(t, os.Chdir(searchDir))
+			defer func() {
+				require.NoError(t, os.Chdir(wd))
+			}()
+
+			fileList, err := pathutil.ListPathInDirSortedByComponents(".", true)
+			require.NoError(t, err)
+
+			got, err := detectCapacitorProject(".", fileList)
+			require.NoError(t, err)
+			if tt.want == nil {
+				require.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			require.Equal(t, *tt.want, *got)
+		})
+	}
+}
+
+func TestCapacitorConfigDescriptorConfigName(t *testing.T) {
+	tests := []struct {
+		descriptor capacitorConfigDescriptor
+		want       string
+	}{
+		{descriptor: capacitorConfigDescriptor{}, want: "capacitor-config"},
+		{descriptor: capacitorConfigDescriptor{android: true}, want: "capacitor-android-config"},
+		{descriptor: capacitorConfigDescriptor{android: true, ios: true, hasTest: true}, want: "capacitor-android-ios-test-config"},
+		{descriptor: capacitorConfigDescriptor{ios: true, hasTest: true}, want: "capacitor-ios-test-config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.descriptor.configName())
+		})
+	}
+}
+
+func TestCapacitorProjectWebDirWarning(t *testing.T) {
+	searchDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(searchDir, "www"), 0755))
+
+	project := capacitorProject{configPth: filepath.Join(searchDir, "capacitor.config.json"), webDir: "www"}
+	warning, err := project.webDirWarning()
+	require.NoError(t, err)
+	require.Empty(t, warning)
+
+	project.webDir = "dist"
+	warning, err = project.webDirWarning()
+	require.NoError(t, err)
+	require.Contains(t, warning, filepath.Join(searchDir, "dist"))
+
+	project.hasBuildScript = true
+	warning, err = project.webDirWarning()
+	require.NoError(t, err)
+	require.Empty(t, warning)
+}
+
+func TestCapacitorProjectProjectType(t *testing.T) {
+	require.Equal(t, "capacitor", capacitorProject{}.projectType())
+	require.Equal(t, "ionic", capacitorProject{isIonic: true}.projectType())
+}
@@ -56,6 +56,10 @@ type Scanner struct {
 	searchDir           string
 	hasKarmaJasmineTest bool
 	hasJasmineTest      bool
+
+	// capacitorProject is set if the app uses Capacitor instead of Cordova.
+	capacitorProject          *capacitorProject
+	capacitorConfigDescriptor capacitorConfigDescriptor
 }
 
 // NewScanner ...
@@ -75,6 +79,21 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 		return false, fmt.Errorf("failed to search for files in (%s), error: %s", searchDir, err)
 	}
 
+	// Capacitor apps, with or without Ionic
+	log.TInfof("Searching for Capacitor config")
+	capacitorProject, err := detectCapacitorProject(searchDir, fileList)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if project is a Capacitor project, error: %s", err)
+	}
+	if capacitorProject != nil {
+		log.TSuccessf("Platform detected (Capacitor)")
+		log.TPrintf("Capacitor project: %s", capacitorProject)
+
+		scanner.capacitorProject = capacitorProject
+		scanner.searchDir = searchDir
+		return true, nil
+	}
+
 	// Ensure it is an ionic project
 	ionicConfigPath, err := FilterRootFile(fileList, "ionic.config.json")
 	if err != nil {
@@ -114,6 +133,10 @@ func (Scanner) ExcludedScannerNames() []string {
 
 // Options ...
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	if scanner.capacitorProject != nil {
+		return scanner.capacitorOptions()
+	}
+
 	warnings := models.Warnings{}
 
 	projectRootDir := filepath.Dir(scanner.ionicConfigPath)
@@ -276,17 +299,39 @@ func (Scanner) DefaultOptions() models.OptionNode {
 }
 
 // Configs ...
-func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	if scanner.capacitorProject != nil {
+		return scanner.capacitorConfigs(triggerPreset)
+	}
 
+	packageJSONDir := filepath.Dir(scanner.ionicConfigPath)
+	jsPackageManager, err := utility.DetectJSPackageManager(scanner.searchDir, packageJSONDir)
+	if err != nil {
+		return models.BitriseConfigMap{}, fmt.Errorf("failed to detect the JavaScript package manager, error: %s", err)
+	}
+	log.TPrintf("Js dependency manager: %s", jsPackageManager)
+
+	jsProjectDirs := []string{packageJSONDir}
+	if jsPackageManager.InstallDir != "" {
+		jsProjectDirs = append(jsProjectDirs, jsPackageManager.InstallDir)
+	}
+	caches, err := utility.DetectDependencyCaches(scanner.searchDir, jsProjectDirs, utility.JSDependencyManagers...)
+	if err != nil {
+		return models.BitriseConfigMap{}, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+	}
+
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+
+	workdir := ""
 	workdirEnvList := []envmanModels.EnvironmentItemModel{}
 	if scanner.relCordovaConfigDir != "" {
-		workdirEnvList = append(workdirEnvList, envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey})
+		workdir = "$" + workDirInputEnvKey
+		workdirEnvList = append(workdirEnvList, envmanModels.EnvironmentItemModel{workDirInputKey: workdir})
 	}
 
 	if scanner.hasJasmineTest || scanner.hasKarmaJasmineTest {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, jsPackageManager.InstallStepListItem(workdir))
 
 		// CI
 		if scanner.hasKarmaJasmineTest {
@@ -294,13 +339,13 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 		} else if scanner.hasJasmineTest {
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.JasmineTestRunnerStepListItem(workdirEnvList...))
 		}
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 		// CD
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, jsPackageManager.InstallStepListItem(workdir))
 
 		if scanner.hasKarmaJasmineTest {
 			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.KarmaJasmineTestRunnerStepListItem(workdirEnvList...))
@@ -318,7 +363,7 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 			ionicArchiveEnvs = append(ionicArchiveEnvs, envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey})
 		}
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.IonicArchiveStepListItem(ionicArchiveEnvs...))
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 		config, err := configBuilder.Generate(scannerName)
 		if err != nil {
@@ -336,7 +381,7 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 	}
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, jsPackageManager.InstallStepListItem(workdir))
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.GenerateCordovaBuildConfigStepListItem())
 
@@ -348,7 +393,7 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 		ionicArchiveEnvs = append(ionicArchiveEnvs, envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey})
 	}
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.IonicArchiveStepListItem(ionicArchiveEnvs...))
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 	config, err := configBuilder.Generate(scannerName)
 	if err != nil {
@@ -366,8 +411,8 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 }
 
 // DefaultConfigs ...
-func (Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func (Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
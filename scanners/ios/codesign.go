@@ -0,0 +1,185 @@
+package ios
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"github.com/bitrise-io/go-xcode/xcodeproject/serialized"
+	"github.com/bitrise-io/go-xcode/xcodeproject/xcodeproj"
+	"github.com/bitrise-io/go-xcode/xcodeproject/xcscheme"
+)
+
+// CodeSignStyle is the code signing style of the archivable targets of a scheme.
+type CodeSignStyle string
+
+const (
+	// CodeSignStyleAutomatic means Xcode manages the signing certificates and provisioning profiles.
+	CodeSignStyleAutomatic CodeSignStyle = "automatic"
+	// CodeSignStyleManual means the provisioning profiles are selected in the project.
+	CodeSignStyleManual CodeSignStyle = "manual"
+)
+
+const (
+	// AutomaticCodeSigningInputKey ...
+	AutomaticCodeSigningInputKey = "automatic_code_signing"
+	// AutomaticCodeSigningInputAPIKeyValue uses the App Store Connect API key connected to the app.
+	AutomaticCodeSigningInputAPIKeyValue = "api-key"
+)
+
+const defaultArchiveConfiguration = "Release"
+
+// SchemeCodeSigning describes the code signing of the app archived by a scheme, including its app extensions and App Clips.
+type SchemeCodeSigning struct {
+	Style             CodeSignStyle
+	DevelopmentTeams  []string
+	ProfileSpecifiers []string
+	// BundleIDs are the bundle IDs of the archived targets, which need provisioning profiles.
+	BundleIDs []string
+}
+
+// Metadata returns the detected signing properties for the scan result, the keys are prefixed by the scheme name.
+func (signing SchemeCodeSigning) Metadata(scheme string) map[string]string {
+	metadata := map[string]string{}
+	if signing.Style == "" {
+		return metadata
+	}
+	metadata[scheme+"/code_sign_style"] = string(signing.Style)
+	if len(signing.DevelopmentTeams) > 0 {
+		metadata[scheme+"/development_team"] = strings.Join(signing.DevelopmentTeams, ",")
+	}
+	if len(signing.BundleIDs) > 0 {
+		metadata[scheme+"/bundle_ids"] = strings.Join(signing.BundleIDs, ",")
+	}
+	return metadata
+}
+
+// DetectSchemeCodeSigning reads the code signing build settings of the targets archived by the shared scheme,
+// which is defined by one of the containers, for the given build configuration (the archive configuration of the scheme if empty).
+// Relative paths are resolved against the searchDir.
+func DetectSchemeCodeSigning(searchDir, scheme string, containerPths []string, configuration string) (SchemeCodeSigning, error) {
+	content, containerPth, err := readSharedScheme(searchDir, scheme, containerPths...)
+	if err != nil || content == nil {
+		return SchemeCodeSigning{}, err
+	}
+
+	var s xcscheme.Scheme
+	if err := xml.Unmarshal(content, &s); err != nil {
+		return SchemeCodeSigning{}, err
+	}
+
+	entry, ok := s.AppBuildActionEntry()
+	if !ok {
+		return SchemeCodeSigning{}, nil
+	}
+
+	projectPth, err := entry.BuildableReference.ReferencedContainerAbsPath(filepath.Dir(containerPth))
+	if err != nil {
+		return SchemeCodeSigning{}, err
+	}
+
+	project, err := xcodeproj.Open(projectPth)
+	if err != nil {
+		return SchemeCodeSigning{}, err
+	}
+
+	if configuration == "" {
+		configuration = s.ArchiveAction.BuildConfiguration
+	}
+	if configuration == "" {
+		configuration = defaultArchiveConfiguration
+	}
+
+	var mainTarget *xcodeproj.Target
+	for i, target := range project.Proj.Targets {
+		if target.ID == entry.BuildableReference.BlueprintIdentifier {
+			mainTarget = &project.Proj.Targets[i]
+			break
+		}
+	}
+	if mainTarget == nil {
+		return SchemeCodeSigning{}, nil
+	}
+
+	// the App Clips and app extensions embedded in the app are archived (and signed) with it
+	targets := append([]xcodeproj.Target{*mainTarget}, mainTarget.DependentExecutableProductTargets(false)...)
+
+	targetAttributes, err := project.TargetAttributes()
+	if err != nil {
+		targetAttributes = serialized.Object{}
+	}
+
+	projectBuildSettings := buildSettingsOf(project.Proj.BuildConfigurationList, configuration)
+
+	signing := SchemeCodeSigning{Style: CodeSignStyleAutomatic}
+	for _, target := range targets {
+		buildSettings := serialized.Object{
+			"TARGET_NAME":  target.Name,
+			"PRODUCT_NAME": target.Name,
+		}
+		for key, value := range projectBuildSettings {
+			buildSettings[key] = value
+		}
+		for key, value := range buildSettingsOf(target.BuildConfigurationList, configuration) {
+			buildSettings[key] = value
+		}
+
+		attributes, err := targetAttributes.Object(target.ID)
+		if err != nil {
+			attributes = serialized.Object{}
+		}
+
+		style := stringSetting(buildSettings, "CODE_SIGN_STYLE")
+		if style == "" {
+			style = stringSetting(attributes, "ProvisioningStyle")
+		}
+		profileSpecifier := stringSetting(buildSettings, "PROVISIONING_PROFILE_SPECIFIER")
+		if strings.EqualFold(style, "Manual") || (style == "" && profileSpecifier != "") {
+			signing.Style = CodeSignStyleManual
+		}
+
+		if profileSpecifier != "" && !sliceutil.IsStringInSlice(profileSpecifier, signing.ProfileSpecifiers) {
+			signing.ProfileSpecifiers = append(signing.ProfileSpecifiers, profileSpecifier)
+		}
+
+		team := stringSetting(buildSettings, "DEVELOPMENT_TEAM")
+		if team == "" {
+			team = stringSetting(attributes, "DevelopmentTeam")
+		}
+		if team != "" && !sliceutil.IsStringInSlice(team, signing.DevelopmentTeams) {
+			signing.DevelopmentTeams = append(signing.DevelopmentTeams, team)
+		}
+
+		if bundleID := stringSetting(buildSettings, "PRODUCT_BUNDLE_IDENTIFIER"); bundleID != "" {
+			if resolved, err := xcodeproj.Resolve(bundleID, buildSettings); err == nil {
+				bundleID = resolved
+			}
+			if !sliceutil.IsStringInSlice(bundleID, signing.BundleIDs) {
+				signing.BundleIDs = append(signing.BundleIDs, bundleID)
+			}
+		}
+	}
+	sort.Strings(signing.BundleIDs)
+
+	return signing, nil
+}
+
+// buildSettingsOf returns the build settings of the configuration, or nil if the configuration is not found.
+func buildSettingsOf(configurationList xcodeproj.ConfigurationList, configuration string) serialized.Object {
+	for _, buildConfiguration := range configurationList.BuildConfigurations {
+		if buildConfiguration.Name == configuration {
+			return buildConfiguration.BuildSettings
+		}
+	}
+	return nil
+}
+
+func stringSetting(settings serialized.Object, key string) string {
+	value, err := settings.String(key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testCodeSigningSchemeContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme LastUpgradeVersion = "0810" version = "1.3">
+   <BuildAction parallelizeBuildables = "YES" buildImplicitDependencies = "YES">
+      <BuildActionEntries>
+         <BuildActionEntry buildForTesting = "YES" buildForRunning = "YES" buildForProfiling = "YES" buildForArchiving = "YES" buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "13C4D5A61DDDDED300D5DC29"
+               BuildableName = "BitriseFastlaneSample.app"
+               BlueprintName = "BitriseFastlaneSample"
+               ReferencedContainer = "container:BitriseFastlaneSample.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+   <ArchiveAction buildConfiguration = "Release" revealArchiveInOrganizer = "YES"></ArchiveAction>
+</Scheme>`
+
+func TestDetectSchemeCodeSigning(t *testing.T) {
+	searchDir, err := pathutil.NormalizedOSTempDirPath("__scheme_code_signing__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(searchDir))
+	}()
+
+	projectPth := filepath.Join(searchDir, "BitriseFastlaneSample.xcodeproj")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPth, "xcshareddata", "xcschemes"), 0755))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(projectPth, "project.pbxproj"), testIOSPbxprojContent))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(projectPth, "xcshareddata", "xcschemes", "BitriseFastlaneSample.xcscheme"), testCodeSigningSchemeContent))
+
+	codeSigning, err := DetectSchemeCodeSigning(searchDir, "BitriseFastlaneSample", []string{"BitriseFastlaneSample.xcodeproj"}, "")
+	require.NoError(t, err)
+	require.Equal(t, SchemeCodeSigning{
+		Style:             CodeSignStyleManual,
+		DevelopmentTeams:  []string{"9NS44DLTN7"},
+		ProfileSpecifiers: []string{"match AppStore com.bitrise.BitriseFastlaneSample"},
+		BundleIDs:         []string{"com.bitrise.BitriseFastlaneSample"},
+	}, codeSigning)
+	require.Equal(t, map[string]string{
+		"BitriseFastlaneSample/code_sign_style":  "manual",
+		"BitriseFastlaneSample/development_team": "9NS44DLTN7",
+		"BitriseFastlaneSample/bundle_ids":       "com.bitrise.BitriseFastlaneSample",
+	}, codeSigning.Metadata("BitriseFastlaneSample"))
+
+	// the signing style set in the target attributes
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(projectPth, "project.pbxproj"), strings.Replace(testIOSPbxprojContent, "ProvisioningStyle = Manual", "ProvisioningStyle = Automatic", 1)))
+
+	codeSigning, err = DetectSchemeCodeSigning(searchDir, "BitriseFastlaneSample", []string{"BitriseFastlaneSample.xcodeproj"}, "")
+	require.NoError(t, err)
+	require.Equal(t, CodeSignStyleAutomatic, codeSigning.Style)
+
+	codeSigning, err = DetectSchemeCodeSigning(searchDir, "Missing", []string{"BitriseFastlaneSample.xcodeproj"}, "")
+	require.NoError(t, err)
+	require.Equal(t, SchemeCodeSigning{}, codeSigning)
+	require.Equal(t, map[string]string{}, codeSigning.Metadata("Missing"))
+}
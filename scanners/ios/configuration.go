@@ -0,0 +1,118 @@
+package ios
+
+import (
+	"encoding/xml"
+	"path/filepath"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"github.com/bitrise-io/go-xcode/xcodeproject/xcodeproj"
+	"github.com/bitrise-io/go-xcode/xcodeproject/xcscheme"
+)
+
+const (
+	// ConfigurationInputEnvKey ...
+	ConfigurationInputEnvKey = "BITRISE_CONFIGURATION"
+	// ConfigurationInputTitle ...
+	ConfigurationInputTitle = "Build configuration"
+	// ConfigurationInputSummary ...
+	ConfigurationInputSummary = "The build configuration used by the archive step, for example, Staging or Production. If not set, the configuration of the scheme's archive action is used. You can change this at any time."
+
+	// TestConfigurationInputEnvKey ...
+	TestConfigurationInputEnvKey = "BITRISE_TEST_CONFIGURATION"
+	// TestConfigurationInputTitle ...
+	TestConfigurationInputTitle = "Test build configuration"
+	// TestConfigurationInputSummary ...
+	TestConfigurationInputSummary = "The build configuration used by the test steps, for example, Debug. If not set, the configuration of the scheme's test action is used. You can change this at any time."
+)
+
+// AddOptionFunc adds the option of the value to the parent option, and returns the leaves of the added options.
+type AddOptionFunc func(parent *models.OptionNode, value string) []*models.OptionNode
+
+// SchemeConfigurations are the build configurations a scheme can be built with.
+type SchemeConfigurations struct {
+	ArchiveConfiguration string
+	TestConfiguration    string
+	// Configurations are the build configurations of the project, the configuration of the archive action comes first.
+	Configurations []string
+}
+
+// DetectSchemeConfigurations returns the build configurations of the shared scheme, which is defined by one of the containers,
+// and the build configurations of the projects.
+// Relative paths are resolved against the searchDir.
+func DetectSchemeConfigurations(searchDir, scheme string, containerPths []string, projectPths []string) (SchemeConfigurations, error) {
+	content, _, err := readSharedScheme(searchDir, scheme, containerPths...)
+	if err != nil || content == nil {
+		return SchemeConfigurations{}, err
+	}
+
+	var s xcscheme.Scheme
+	if err := xml.Unmarshal(content, &s); err != nil {
+		return SchemeConfigurations{}, err
+	}
+
+	configurations := SchemeConfigurations{
+		ArchiveConfiguration: s.ArchiveAction.BuildConfiguration,
+		TestConfiguration:    s.TestAction.BuildConfiguration,
+	}
+	if configurations.ArchiveConfiguration != "" {
+		configurations.Configurations = append(configurations.Configurations, configurations.ArchiveConfiguration)
+	}
+
+	for _, projectPth := range projectPths {
+		if !filepath.IsAbs(projectPth) {
+			projectPth = filepath.Join(searchDir, projectPth)
+		}
+
+		project, err := xcodeproj.Open(projectPth)
+		if err != nil {
+			return SchemeConfigurations{}, err
+		}
+
+		for _, buildConfiguration := range project.Proj.BuildConfigurationList.BuildConfigurations {
+			if !sliceutil.IsStringInSlice(buildConfiguration.Name, configurations.Configurations) {
+				configurations.Configurations = append(configurations.Configurations, buildConfiguration.Name)
+			}
+		}
+	}
+
+	return configurations, nil
+}
+
+// HasConfigurations ...
+func (configurations SchemeConfigurations) HasConfigurations() bool {
+	return len(configurations.Configurations) > 0
+}
+
+// HasTestConfiguration ...
+func (configurations SchemeConfigurations) HasTestConfiguration() bool {
+	return configurations.TestConfiguration != ""
+}
+
+// AddOptions adds the optional build configuration selector of the archive step to the parent option,
+// followed by the optional build configuration input of the test steps if the scheme has tests.
+// The test configuration is an input defaulting to the configuration of the test action,
+// so that it does not multiply the options by the number of configurations. The options of the next level are added by next.
+func (configurations SchemeConfigurations) AddOptions(parent *models.OptionNode, value string, hasTest bool, next AddOptionFunc) []*models.OptionNode {
+	addTestConfigurationOptions := func(parent *models.OptionNode, value string) []*models.OptionNode {
+		if !hasTest || !configurations.HasTestConfiguration() {
+			return next(parent, value)
+		}
+
+		testConfigurationOption := models.NewOption(TestConfigurationInputTitle, TestConfigurationInputSummary, TestConfigurationInputEnvKey, models.TypeOptionalUserInput)
+		parent.AddOption(value, testConfigurationOption)
+		return next(testConfigurationOption, configurations.TestConfiguration)
+	}
+
+	if !configurations.HasConfigurations() {
+		return addTestConfigurationOptions(parent, value)
+	}
+
+	var leaves []*models.OptionNode
+	configurationOption := models.NewOption(ConfigurationInputTitle, ConfigurationInputSummary, ConfigurationInputEnvKey, models.TypeOptionalSelector)
+	parent.AddOption(value, configurationOption)
+	for _, configuration := range configurations.Configurations {
+		leaves = append(leaves, addTestConfigurationOptions(configurationOption, configuration)...)
+	}
+	return leaves
+}
@@ -0,0 +1,102 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSchemeConfigurations(t *testing.T) {
+	searchDir, err := pathutil.NormalizedOSTempDirPath("__scheme_configurations__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(searchDir))
+	}()
+
+	projectPth := filepath.Join(searchDir, "BitriseFastlaneSample.xcodeproj")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPth, "xcshareddata", "xcschemes"), 0755))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(projectPth, "project.pbxproj"), testIOSPbxprojContent))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(projectPth, "xcshareddata", "xcschemes", "BitriseFastlaneSample.xcscheme"), `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme LastUpgradeVersion = "0810" version = "1.3">
+   <TestAction buildConfiguration = "Debug"></TestAction>
+   <ArchiveAction buildConfiguration = "Release" revealArchiveInOrganizer = "YES"></ArchiveAction>
+</Scheme>`))
+
+	configurations, err := DetectSchemeConfigurations(searchDir, "BitriseFastlaneSample", []string{"BitriseFastlaneSample.xcodeproj"}, []string{"BitriseFastlaneSample.xcodeproj"})
+	require.NoError(t, err)
+	require.Equal(t, SchemeConfigurations{
+		ArchiveConfiguration: "Release",
+		TestConfiguration:    "Debug",
+		Configurations:       []string{"Release", "Debug"},
+	}, configurations)
+
+	configurations, err = DetectSchemeConfigurations(searchDir, "Missing", []string{"BitriseFastlaneSample.xcodeproj"}, []string{"BitriseFastlaneSample.xcodeproj"})
+	require.NoError(t, err)
+	require.Equal(t, SchemeConfigurations{}, configurations)
+}
+
+func TestSchemeConfigurationsAddOptions(t *testing.T) {
+	configurations := SchemeConfigurations{
+		ArchiveConfiguration: "Release",
+		TestConfiguration:    "Debug",
+		Configurations:       []string{"Release", "Debug"},
+	}
+	next := func(parent *models.OptionNode, value string) []*models.OptionNode {
+		exportMethodOption := models.NewOption(IosExportMethodInputTitle, IosExportMethodInputSummary, ExportMethodInputEnvKey, models.TypeSelector)
+		parent.AddOption(value, exportMethodOption)
+		return []*models.OptionNode{exportMethodOption}
+	}
+
+	tests := []struct {
+		name           string
+		configurations SchemeConfigurations
+		hasTest        bool
+		wantEnvKeys    []string
+		wantLeaves     int
+	}{
+		{
+			name:           "archive and test configurations",
+			configurations: configurations,
+			hasTest:        true,
+			wantEnvKeys:    []string{ConfigurationInputEnvKey, TestConfigurationInputEnvKey, ExportMethodInputEnvKey},
+			wantLeaves:     2,
+		},
+		{
+			name:           "no tests",
+			configurations: configurations,
+			hasTest:        false,
+			wantEnvKeys:    []string{ConfigurationInputEnvKey, ExportMethodInputEnvKey},
+			wantLeaves:     2,
+		},
+		{
+			name:           "no configurations",
+			configurations: SchemeConfigurations{},
+			hasTest:        true,
+			wantEnvKeys:    []string{ExportMethodInputEnvKey},
+			wantLeaves:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemeOption := models.NewOption(SchemeInputTitle, SchemeInputSummary, SchemeInputEnvKey, models.TypeSelector)
+			leaves := tt.configurations.AddOptions(schemeOption, "Scheme", tt.hasTest, next)
+			require.Equal(t, tt.wantLeaves, len(leaves))
+
+			var envKeys []string
+			for option := schemeOption.ChildOptionMap["Scheme"]; option != nil; {
+				envKeys = append(envKeys, option.EnvKey)
+				var child *models.OptionNode
+				for _, c := range option.ChildOptionMap {
+					child = c
+				}
+				option = child
+			}
+			require.Equal(t, tt.wantEnvKeys, envKeys)
+		})
+	}
+}
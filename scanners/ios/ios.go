@@ -10,8 +10,11 @@ import "github.com/bitrise-io/bitrise-init/models"
 type Scanner struct {
 	SearchDir                 string
 	ConfigDescriptors         []ConfigDescriptor
+	metadata                  models.Metadata
 	ExcludeAppIcon            bool
 	SuppressPodFileParseError bool
+	// excludedDirs are the directories of the projects scanned by an other scanner, relative to the SearchDir.
+	excludedDirs []string
 }
 
 // NewScanner ...
@@ -28,7 +31,7 @@ func (Scanner) Name() string {
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	scanner.SearchDir = searchDir
 
-	detected, err := Detect(XcodeProjectTypeIOS, searchDir)
+	detected, err := Detect(XcodeProjectTypeIOS, searchDir, scanner.excludedDirs...)
 	if err != nil {
 		return false, err
 	}
@@ -41,31 +44,42 @@ func (Scanner) ExcludedScannerNames() []string {
 	return []string{}
 }
 
+// ExcludeDirs ...
+func (scanner *Scanner) ExcludeDirs(dirs []string) {
+	scanner.excludedDirs = dirs
+}
+
 // Options ...
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
-	options, configDescriptors, icons, warnings, err := GenerateOptions(XcodeProjectTypeIOS, scanner.SearchDir, scanner.ExcludeAppIcon, scanner.SuppressPodFileParseError)
+	options, configDescriptors, icons, metadata, warnings, err := GenerateOptions(XcodeProjectTypeIOS, scanner.SearchDir, scanner.ExcludeAppIcon, scanner.SuppressPodFileParseError, scanner.excludedDirs...)
 	if err != nil {
 		return models.OptionNode{}, warnings, nil, err
 	}
 
 	scanner.ConfigDescriptors = configDescriptors
+	scanner.metadata = metadata
 
 	return options, warnings, icons, nil
 }
 
+// Metadata ...
+func (scanner *Scanner) Metadata() models.Metadata {
+	return scanner.metadata
+}
+
 // DefaultOptions ...
 func (Scanner) DefaultOptions() models.OptionNode {
 	return GenerateDefaultOptions(XcodeProjectTypeIOS)
 }
 
 // Configs ...
-func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	return GenerateConfig(XcodeProjectTypeIOS, scanner.ConfigDescriptors, true)
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	return GenerateConfig(XcodeProjectTypeIOS, scanner.ConfigDescriptors, true, triggerPreset)
 }
 
 // DefaultConfigs ...
-func (Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
-	return GenerateDefaultConfig(XcodeProjectTypeIOS, true)
+func (Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	return GenerateDefaultConfig(XcodeProjectTypeIOS, true, triggerPreset)
 }
 
 // GetProjectType returns the project_type property used in a bitrise config
@@ -24,7 +24,29 @@ type podfileParser struct {
 	suppressPodFileParseError bool
 }
 
+// readPodfileDefinition parses the Podfile in Go, errUnsupportedPodfile is returned if it can only be evaluated by CocoaPods.
+func (podfileParser podfileParser) readPodfileDefinition() (podfileDefinition, error) {
+	content, err := fileutil.ReadStringFromFile(podfileParser.podfilePth)
+	if err != nil {
+		return podfileDefinition{}, fmt.Errorf("failed to read podfile (%s), error: %s", podfileParser.podfilePth, err)
+	}
+	return parsePodfile(content)
+}
+
 func (podfileParser podfileParser) getTargetDefinitionProjectMap(cocoapodsVersion string) (map[string]string, error) {
+	definition, err := podfileParser.readPodfileDefinition()
+	if err == nil {
+		return definition.TargetProjectPaths, nil
+	}
+	if !errors.Is(err, errUnsupportedPodfile) {
+		return map[string]string{}, err
+	}
+
+	log.TWarnf("%s, evaluating the Podfile (%s) with CocoaPods", err, podfileParser.podfilePth)
+	return podfileParser.getTargetDefinitionProjectMapWithRuby(cocoapodsVersion)
+}
+
+func (podfileParser podfileParser) getTargetDefinitionProjectMapWithRuby(cocoapodsVersion string) (map[string]string, error) {
 	gemfileCocoapodsVersion := ""
 	if cocoapodsVersion != "" {
 		gemfileCocoapodsVersion = fmt.Sprintf(`, '%s'`, cocoapodsVersion)
@@ -127,6 +149,19 @@ func (podfileParser podfileParser) getUserDefinedProjectRelavtivePath(cocoapodsV
 }
 
 func (podfileParser podfileParser) getUserDefinedWorkspaceRelativePath(cocoapodsVersion string) (string, error) {
+	definition, err := podfileParser.readPodfileDefinition()
+	if err == nil {
+		return definition.WorkspacePath, nil
+	}
+	if !errors.Is(err, errUnsupportedPodfile) {
+		return "", err
+	}
+
+	log.TWarnf("%s, evaluating the Podfile (%s) with CocoaPods", err, podfileParser.podfilePth)
+	return podfileParser.getUserDefinedWorkspaceRelativePathWithRuby(cocoapodsVersion)
+}
+
+func (podfileParser podfileParser) getUserDefinedWorkspaceRelativePathWithRuby(cocoapodsVersion string) (string, error) {
 	gemfileCocoapodsVersion := ""
 	if cocoapodsVersion != "" {
 		gemfileCocoapodsVersion = fmt.Sprintf(`, '%s'`, cocoapodsVersion)
@@ -0,0 +1,184 @@
+package ios
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// errUnsupportedPodfile is returned if the Podfile defines its workspace, projects or targets
+// by Ruby code (variables, loops, methods), which can only be evaluated by CocoaPods.
+var errUnsupportedPodfile = errors.New("the Podfile uses Ruby constructs not supported by the Podfile parser")
+
+const rootTargetDefinitionName = "Pods"
+
+var (
+	// workspace 'MyWorkspace' / project 'MyProject', 'Debug' => :debug / target :App, :exclusive => true do / abstract_target 'Shared' do
+	podfileStatementPattern = regexp.MustCompile(`^(workspace|project|xcodeproj|target|abstract_target)(?:\s+|\s*\(\s*)(.*)$`)
+	// 'name' / "name" / :name
+	podfileLiteralPattern = regexp.MustCompile(`^(?:'([^']*)'|"([^"]*)"|:(\w+))`)
+	// post_install do |installer| / targets.each do |target|
+	rubyDoBlockPattern = regexp.MustCompile(`\bdo\s*(?:\|[^|]*\|)?\s*$`)
+	// if / unless / def ... opening a block closed by end, but not the one-liners (def a; end)
+	rubyKeywordBlockPattern = regexp.MustCompile(`^(?:if|unless|while|until|case|def|begin|class|module|for)\b`)
+	rubyEndPattern          = regexp.MustCompile(`^end\b`)
+	rubyOneLinerEndPattern  = regexp.MustCompile(`\bend\s*$`)
+)
+
+// podfileTargetDefinition is a target or abstract_target block of a Podfile (or the implicit root definition).
+type podfileTargetDefinition struct {
+	name    string
+	project string
+	parent  *podfileTargetDefinition
+}
+
+// userProjectPath returns the project of the target definition, inherited from the parent definitions if not set.
+func (definition *podfileTargetDefinition) userProjectPath() string {
+	for d := definition; d != nil; d = d.parent {
+		if d.project != "" {
+			return d.project
+		}
+	}
+	return ""
+}
+
+// podfileDefinition holds the Podfile properties used to map the CocoaPods workspace to its projects.
+type podfileDefinition struct {
+	// WorkspacePath is the workspace defined in the Podfile, empty if not defined.
+	WorkspacePath string
+	// TargetProjectPaths maps the target definitions (including the root Pods definition) to their user project paths,
+	// targets without a project are left out.
+	TargetProjectPaths map[string]string
+}
+
+// stripRubyComments removes the line comments and the =begin/=end block comments from a Ruby source.
+func stripRubyComments(content string) string {
+	var lines []string
+	inBlockComment := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "=begin") {
+			inBlockComment = true
+		}
+		if inBlockComment {
+			if strings.HasPrefix(line, "=end") {
+				inBlockComment = false
+			}
+			lines = append(lines, "")
+			continue
+		}
+
+		lines = append(lines, stripRubyLineComment(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stripRubyLineComment removes the # comment from the end of the line, a # inside a string literal is kept.
+func stripRubyLineComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// podfileLiteral returns the value of the string or symbol literal the argument list starts with.
+func podfileLiteral(args string) (string, error) {
+	match := podfileLiteralPattern.FindStringSubmatch(args)
+	if match == nil || strings.Contains(match[2], "#{") {
+		return "", errUnsupportedPodfile
+	}
+	return match[1] + match[2] + match[3], nil
+}
+
+// parsePodfile evaluates the workspace, project (xcodeproj) and target (abstract_target) definitions of a Podfile.
+func parsePodfile(content string) (podfileDefinition, error) {
+	root := &podfileTargetDefinition{name: rootTargetDefinitionName}
+	definitions := []*podfileTargetDefinition{root}
+
+	// the open blocks, nil for the non target definition blocks (like post_install or if)
+	stack := []*podfileTargetDefinition{root}
+	workspace := ""
+
+	for _, line := range strings.Split(stripRubyComments(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if rubyEndPattern.MatchString(line) {
+			if len(stack) == 1 {
+				return podfileDefinition{}, fmt.Errorf("%w: unexpected end", errUnsupportedPodfile)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		// an assignment to a local variable (target = ...) is not a DSL statement
+		if match := podfileStatementPattern.FindStringSubmatch(line); match != nil && !strings.HasPrefix(match[2], "=") {
+			current := stack[len(stack)-1]
+			if current == nil {
+				// defined conditionally or by a method
+				return podfileDefinition{}, errUnsupportedPodfile
+			}
+
+			keyword, args := match[1], match[2]
+			value, err := podfileLiteral(args)
+			if err != nil {
+				return podfileDefinition{}, err
+			}
+
+			switch keyword {
+			case "workspace":
+				workspace = value
+			case "project", "xcodeproj":
+				current.project = value
+			case "target", "abstract_target":
+				if !rubyDoBlockPattern.MatchString(line) {
+					return podfileDefinition{}, errUnsupportedPodfile
+				}
+				definition := &podfileTargetDefinition{name: value, parent: current}
+				definitions = append(definitions, definition)
+				stack = append(stack, definition)
+			}
+			continue
+		}
+
+		if rubyDoBlockPattern.MatchString(line) || (rubyKeywordBlockPattern.MatchString(line) && !rubyOneLinerEndPattern.MatchString(line)) {
+			stack = append(stack, nil)
+		}
+	}
+
+	if len(stack) != 1 {
+		return podfileDefinition{}, fmt.Errorf("%w: unclosed block", errUnsupportedPodfile)
+	}
+
+	definition := podfileDefinition{TargetProjectPaths: map[string]string{}}
+	if workspace != "" {
+		definition.WorkspacePath = withExtension(workspace, ".xcworkspace")
+	}
+	for _, target := range definitions {
+		if project := target.userProjectPath(); project != "" {
+			definition.TargetProjectPaths[target.name] = withExtension(project, ".xcodeproj")
+		}
+	}
+	return definition, nil
+}
+
+// withExtension appends the extension to the path, like CocoaPods does for the workspace and project paths.
+func withExtension(pth, ext string) string {
+	if filepath.Ext(pth) == ext {
+		return pth
+	}
+	return pth + ext
+}
@@ -0,0 +1,120 @@
+package ios
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePodfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		podfile string
+		want    podfileDefinition
+		wantErr error
+	}{
+		{
+			name: "root project and workspace",
+			podfile: `platform :ios, '9.0'
+workspace 'MyWorkspace'
+project "MyXcodeProject.xcodeproj"
+pod 'Alamofire', '~> 3.4'
+`,
+			want: podfileDefinition{
+				WorkspacePath:      "MyWorkspace.xcworkspace",
+				TargetProjectPaths: map[string]string{"Pods": "MyXcodeProject.xcodeproj"},
+			},
+		},
+		{
+			name: "nested and abstract targets inherit the project",
+			podfile: `# project 'Commented'
+xcodeproj 'App/App', 'Debug' => :debug
+
+abstract_target 'Shared' do
+  pod 'Alamofire'
+
+  target 'App' do
+  end
+
+  target :Widget do
+    project('Widget/Widget.xcodeproj')
+
+    target 'WidgetTests' do
+      inherit! :search_paths
+    end
+  end
+end
+
+post_install do |installer|
+  installer.pods_project.targets.each do |target|
+    target.build_configurations.each do |config|
+      config.build_settings['ONLY_ACTIVE_ARCH'] = 'NO' # project 'Ignored'
+    end
+  end
+end
+`,
+			want: podfileDefinition{
+				TargetProjectPaths: map[string]string{
+					"Pods":        "App/App.xcodeproj",
+					"Shared":      "App/App.xcodeproj",
+					"App":         "App/App.xcodeproj",
+					"Widget":      "Widget/Widget.xcodeproj",
+					"WidgetTests": "Widget/Widget.xcodeproj",
+				},
+			},
+		},
+		{
+			name: "cocoapods 0.38 exclusive target",
+			podfile: `=begin
+workspace 'Commented'
+=end
+target :SampleAppWithCocoapodsTests, :exclusive => true do
+  pod 'Kiwi'
+end
+`,
+			want: podfileDefinition{TargetProjectPaths: map[string]string{}},
+		},
+		{
+			name: "project defined by a variable",
+			podfile: `project_name = 'App'
+project project_name
+`,
+			wantErr: errUnsupportedPodfile,
+		},
+		{
+			name: "project defined by string interpolation",
+			podfile: `project "#{ENV['PROJECT']}"
+`,
+			wantErr: errUnsupportedPodfile,
+		},
+		{
+			name: "target defined by a method",
+			podfile: `def app_target(name)
+  target name do
+  end
+end
+`,
+			wantErr: errUnsupportedPodfile,
+		},
+		{
+			name: "target defined conditionally",
+			podfile: `if ENV['WIDGET']
+  target 'Widget' do
+  end
+end
+`,
+			wantErr: errUnsupportedPodfile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePodfile(tt.podfile)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
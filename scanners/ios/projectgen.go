@@ -0,0 +1,466 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-init/steps"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"github.com/bitrise-io/go-xcode/pathfilters"
+)
+
+// ProjectGenerator is a tool generating the Xcode project from a manifest committed instead of the project.
+type ProjectGenerator string
+
+const (
+	// ProjectGeneratorXcodeGen ...
+	ProjectGeneratorXcodeGen ProjectGenerator = "xcodegen"
+	// ProjectGeneratorTuist ...
+	ProjectGeneratorTuist ProjectGenerator = "tuist"
+)
+
+const (
+	xcodeGenSpecBase        = "project.yml"
+	tuistProjectBase        = "Project.swift"
+	tuistWorkspaceBase      = "Workspace.swift"
+	tuistPackageManifestPth = "Tuist/Package.swift"
+)
+
+const (
+	xcodeGenGenerateScriptTitle = "Generate Xcode project with XcodeGen"
+	xcodeGenGenerateScript      = `#!/usr/bin/env bash
+set -euxo pipefail
+
+# XcodeGen generates the project next to its spec
+cd "$(dirname "$BITRISE_PROJECT_PATH")"
+
+if ! command -v xcodegen &> /dev/null; then
+  brew install xcodegen
+fi
+
+xcodegen generate
+`
+
+	tuistGenerateScriptTitle = "Generate Xcode project with Tuist"
+	tuistGenerateScript      = `#!/usr/bin/env bash
+set -euxo pipefail
+
+# Tuist generates the workspace next to the manifest
+cd "$(dirname "$BITRISE_PROJECT_PATH")"
+
+if ! command -v tuist &> /dev/null; then
+  brew tap tuist/tuist
+  brew install --formula tuist
+fi
+
+if [ -f Tuist/Package.swift ]; then
+  tuist install
+fi
+
+tuist generate --no-open
+`
+)
+
+// generatedScheme is a scheme of a generated project.
+type generatedScheme struct {
+	Name       string
+	HasXCTest  bool
+	HasAppClip bool
+}
+
+// generatedProject is an Xcode project (or workspace) generated by XcodeGen or Tuist.
+type generatedProject struct {
+	Generator   ProjectGenerator
+	ManifestPth string
+	// Pth is the path of the generated project or workspace used by the Xcode steps.
+	Pth              string
+	HasPodfile       bool
+	HasSwiftPackages bool
+	Schemes          []generatedScheme
+	// MissingSharedSchemes is true if the manifest defines no schemes,
+	// Schemes then lists the app targets, the schemes of which are recreated.
+	MissingSharedSchemes bool
+}
+
+// generatedTarget is a target defined in a project manifest.
+type generatedTarget struct {
+	Name         string
+	Platforms    []string
+	IsTest       bool
+	IsAppClip    bool
+	IsApp        bool
+	Dependencies []string
+}
+
+func (target generatedTarget) isPlatform(projectType XcodeProjectType) bool {
+	return sliceutil.IsStringInSlice(manifestPlatform(projectType), target.Platforms)
+}
+
+// manifestPlatform returns the platform name used by both XcodeGen and Tuist (iOS or macOS).
+func manifestPlatform(projectType XcodeProjectType) string {
+	if projectType == XcodeProjectTypeMacOS {
+		return "macOS"
+	}
+	return "iOS"
+}
+
+// hasTestTargetFor returns true if a test target depends on the target.
+func hasTestTargetFor(name string, targets []generatedTarget) bool {
+	for _, target := range targets {
+		if target.IsTest && sliceutil.IsStringInSlice(name, target.Dependencies) {
+			return true
+		}
+	}
+	return false
+}
+
+func generatedProjectManifestFilter(base string) []pathutil.FilterFunc {
+	return []pathutil.FilterFunc{
+		pathutil.BaseFilter(base, true),
+		pathfilters.ForbidGitDirComponentFilter,
+		pathfilters.ForbidPodsDirComponentFilter,
+		pathfilters.ForbidCarthageDirComponentFilter,
+		pathfilters.ForbidNodeModulesComponentFilter,
+		pathutil.ComponentFilter("build", false),
+		pathutil.ComponentFilter(".build", false),
+	}
+}
+
+// findGeneratedProjects returns the XcodeGen specs and Tuist manifests, which define a project of the given type,
+// a manifest is left out if its project is committed (the committed project is scanned instead).
+// fileList contains the paths relative to the searchDir.
+func findGeneratedProjects(projectType XcodeProjectType, searchDir string, fileList []string) ([]generatedProject, error) {
+	var projects []generatedProject
+
+	xcodeGenSpecs, err := pathutil.FilterPaths(fileList, generatedProjectManifestFilter(xcodeGenSpecBase)...)
+	if err != nil {
+		return nil, err
+	}
+	for _, specPth := range xcodeGenSpecs {
+		content, err := fileutil.ReadStringFromFile(filepath.Join(searchDir, specPth))
+		if err != nil {
+			return nil, err
+		}
+
+		project, ok, err := parseXcodeGenSpec(projectType, specPth, content)
+		if err != nil {
+			log.TWarnf("Failed to parse XcodeGen spec (%s), error: %s", specPth, err)
+			continue
+		}
+		if !ok || sliceutil.IsStringInSlice(project.Pth, fileList) {
+			continue
+		}
+		projects = append(projects, project)
+	}
+
+	tuistManifests, err := pathutil.FilterPaths(fileList, generatedProjectManifestFilter(tuistProjectBase)...)
+	if err != nil {
+		return nil, err
+	}
+	for _, manifestPth := range tuistManifests {
+		content, err := fileutil.ReadStringFromFile(filepath.Join(searchDir, manifestPth))
+		if err != nil {
+			return nil, err
+		}
+
+		workspaceContent := ""
+		if workspacePth := filepath.Join(filepath.Dir(manifestPth), tuistWorkspaceBase); sliceutil.IsStringInSlice(workspacePth, fileList) {
+			if workspaceContent, err = fileutil.ReadStringFromFile(filepath.Join(searchDir, workspacePth)); err != nil {
+				return nil, err
+			}
+		}
+
+		project, ok := parseTuistManifest(projectType, manifestPth, content, workspaceContent)
+		if !ok || sliceutil.IsStringInSlice(project.Pth, fileList) {
+			continue
+		}
+		if sliceutil.IsStringInSlice(filepath.Join(filepath.Dir(manifestPth), tuistPackageManifestPth), fileList) {
+			project.HasSwiftPackages = true
+		}
+		projects = append(projects, project)
+	}
+
+	for i, project := range projects {
+		podfilePth := filepath.Join(filepath.Dir(project.ManifestPth), podfileBase)
+		if !sliceutil.IsStringInSlice(podfilePth, fileList) {
+			continue
+		}
+
+		// the Xcode steps use the workspace created by pod install
+		project.HasPodfile = true
+		project.Pth = strings.TrimSuffix(project.Pth, filepath.Ext(project.Pth)) + ".xcworkspace"
+		parser := podfileParser{podfilePth: filepath.Join(searchDir, podfilePth)}
+		if definition, err := parser.readPodfileDefinition(); err != nil {
+			log.TWarnf("Failed to read the workspace defined in the Podfile (%s), error: %s", podfilePth, err)
+		} else if definition.WorkspacePath != "" {
+			project.Pth = filepath.Join(filepath.Dir(podfilePth), definition.WorkspacePath)
+		}
+		projects[i] = project
+	}
+
+	return projects, nil
+}
+
+// xcodeGenSpec is the part of an XcodeGen project spec, which describes the targets and schemes.
+type xcodeGenSpec struct {
+	Name     string                 `yaml:"name"`
+	Packages map[string]interface{} `yaml:"packages"`
+	Targets  map[string]struct {
+		Type                  string      `yaml:"type"`
+		Platform              interface{} `yaml:"platform"`
+		SupportedDestinations []string    `yaml:"supportedDestinations"`
+		Dependencies          []struct {
+			Target string `yaml:"target"`
+		} `yaml:"dependencies"`
+		Scheme *struct {
+			TestTargets []interface{} `yaml:"testTargets"`
+		} `yaml:"scheme"`
+	} `yaml:"targets"`
+	Schemes map[string]struct {
+		Build struct {
+			Targets map[string]interface{} `yaml:"targets"`
+		} `yaml:"build"`
+		Test struct {
+			Targets []interface{} `yaml:"targets"`
+		} `yaml:"test"`
+	} `yaml:"schemes"`
+}
+
+// parseXcodeGenSpec returns the project generated from the spec at specPth,
+// false is returned if the spec defines no target of the given type.
+func parseXcodeGenSpec(projectType XcodeProjectType, specPth, content string) (generatedProject, bool, error) {
+	var spec xcodeGenSpec
+	if err := yaml.Unmarshal([]byte(content), &spec); err != nil {
+		return generatedProject{}, false, err
+	}
+	if spec.Name == "" {
+		return generatedProject{}, false, fmt.Errorf("no project name defined")
+	}
+
+	var targets []generatedTarget
+	for name, definition := range spec.Targets {
+		target := generatedTarget{
+			Name:      name,
+			IsApp:     strings.HasPrefix(definition.Type, "application"),
+			IsAppClip: definition.Type == "application.on-demand-install-capable",
+			IsTest:    definition.Type == "bundle.unit-test" || definition.Type == "bundle.ui-testing",
+		}
+
+		switch platform := definition.Platform.(type) {
+		case string:
+			target.Platforms = append(target.Platforms, platform)
+		case []interface{}:
+			for _, p := range platform {
+				target.Platforms = append(target.Platforms, fmt.Sprint(p))
+			}
+		}
+		target.Platforms = append(target.Platforms, definition.SupportedDestinations...)
+
+		for _, dependency := range definition.Dependencies {
+			if dependency.Target != "" {
+				target.Dependencies = append(target.Dependencies, dependency.Target)
+			}
+		}
+
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+
+	targetByName := map[string]generatedTarget{}
+	isRelevant := false
+	for _, target := range targets {
+		targetByName[target.Name] = target
+		if target.isPlatform(projectType) {
+			isRelevant = true
+		}
+	}
+	if !isRelevant {
+		return generatedProject{}, false, nil
+	}
+
+	project := generatedProject{
+		Generator:        ProjectGeneratorXcodeGen,
+		ManifestPth:      specPth,
+		Pth:              filepath.Join(filepath.Dir(specPth), spec.Name+".xcodeproj"),
+		HasSwiftPackages: len(spec.Packages) > 0,
+	}
+
+	for name, definition := range spec.Schemes {
+		scheme := generatedScheme{Name: name, HasXCTest: len(definition.Test.Targets) > 0}
+		isRelevant := false
+		for targetName := range definition.Build.Targets {
+			target := targetByName[targetName]
+			isRelevant = isRelevant || target.isPlatform(projectType)
+			scheme.HasAppClip = scheme.HasAppClip || target.IsAppClip
+		}
+		if isRelevant {
+			project.Schemes = append(project.Schemes, scheme)
+		}
+	}
+	// a target with a scheme property gets a scheme of the same name
+	for _, target := range targets {
+		definition := spec.Targets[target.Name]
+		if definition.Scheme != nil && target.isPlatform(projectType) {
+			project.Schemes = append(project.Schemes, generatedScheme{
+				Name:       target.Name,
+				HasXCTest:  len(definition.Scheme.TestTargets) > 0,
+				HasAppClip: target.IsAppClip,
+			})
+		}
+	}
+
+	if len(project.Schemes) == 0 {
+		project.MissingSharedSchemes = true
+		for _, target := range targets {
+			if target.IsApp && target.isPlatform(projectType) {
+				project.Schemes = append(project.Schemes, generatedScheme{
+					Name:       target.Name,
+					HasXCTest:  hasTestTargetFor(target.Name, targets),
+					HasAppClip: target.IsAppClip,
+				})
+			}
+		}
+	}
+	sort.Slice(project.Schemes, func(i, j int) bool { return project.Schemes[i].Name < project.Schemes[j].Name })
+
+	return project, true, nil
+}
+
+var (
+	tuistProjectNamePattern   = regexp.MustCompile(`Project\(\s*name:\s*"([^"]+)"`)
+	tuistWorkspaceNamePattern = regexp.MustCompile(`Workspace\(\s*name:\s*"([^"]+)"`)
+	// Target(name: "App", ...) / .target(name: "App", ...), also matching the .target(name: "App") dependencies
+	tuistTargetPattern      = regexp.MustCompile(`(?:\bTarget|\.target)\(\s*name:\s*"([^"]+)"`)
+	tuistSchemePattern      = regexp.MustCompile(`(?:\bScheme|\.scheme)\(\s*name:\s*"([^"]+)"`)
+	tuistDestinationPattern = regexp.MustCompile(`(?:destinations|platform):\s*(?:\.(\w+)|\[([^\]]*)\])`)
+	tuistProductPattern     = regexp.MustCompile(`product:\s*\.(\w+)`)
+	tuistPackagesPattern    = regexp.MustCompile(`packages:\s*\[\s*\.`)
+)
+
+// tuistPlatforms maps the Tuist destinations and platforms to the platform names.
+var tuistPlatforms = map[string]string{
+	"iOS":    "iOS",
+	"iPhone": "iOS",
+	"iPad":   "iOS",
+	"macOS":  "macOS",
+	"mac":    "macOS",
+}
+
+// callArguments returns the arguments of the call, the opening parenthesis of which is at content[open].
+func callArguments(content string, open int) string {
+	depth := 0
+	for i := open; i < len(content); i++ {
+		switch content[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return content[open+1 : i]
+			}
+		}
+	}
+	return content[open+1:]
+}
+
+// parseTuistManifest returns the workspace generated by Tuist from the Project.swift at manifestPth,
+// false is returned if no target of the given type is found.
+// The manifests are Swift code, so only the literal target and scheme definitions are recognised.
+func parseTuistManifest(projectType XcodeProjectType, manifestPth, content, workspaceContent string) (generatedProject, bool) {
+	match := tuistProjectNamePattern.FindStringSubmatch(content)
+	if match == nil {
+		return generatedProject{}, false
+	}
+	workspaceName := match[1]
+	if match := tuistWorkspaceNamePattern.FindStringSubmatch(workspaceContent); match != nil {
+		workspaceName = match[1]
+	}
+
+	var targets []generatedTarget
+	for _, loc := range tuistTargetPattern.FindAllStringSubmatchIndex(content, -1) {
+		args := callArguments(content, strings.Index(content[loc[0]:], "(")+loc[0])
+		product := tuistProductPattern.FindStringSubmatch(args)
+		if product == nil {
+			// a target dependency
+			continue
+		}
+
+		target := generatedTarget{
+			Name:      content[loc[2]:loc[3]],
+			IsApp:     product[1] == "app" || product[1] == "appClip",
+			IsAppClip: product[1] == "appClip",
+			IsTest:    product[1] == "unitTests" || product[1] == "uiTests",
+		}
+		if destinations := tuistDestinationPattern.FindStringSubmatch(args); destinations != nil {
+			for _, destination := range strings.Split(destinations[1]+destinations[2], ",") {
+				if platform, ok := tuistPlatforms[strings.TrimPrefix(strings.TrimSpace(destination), ".")]; ok && !sliceutil.IsStringInSlice(platform, target.Platforms) {
+					target.Platforms = append(target.Platforms, platform)
+				}
+			}
+		}
+		for _, dependency := range tuistTargetPattern.FindAllStringSubmatch(args, -1) {
+			target.Dependencies = append(target.Dependencies, dependency[1])
+		}
+
+		targets = append(targets, target)
+	}
+
+	isRelevant := false
+	for _, target := range targets {
+		if target.isPlatform(projectType) {
+			isRelevant = true
+		}
+	}
+	if !isRelevant {
+		return generatedProject{}, false
+	}
+
+	project := generatedProject{
+		Generator:        ProjectGeneratorTuist,
+		ManifestPth:      manifestPth,
+		Pth:              filepath.Join(filepath.Dir(manifestPth), workspaceName+".xcworkspace"),
+		HasSwiftPackages: tuistPackagesPattern.MatchString(content),
+	}
+
+	for _, loc := range tuistSchemePattern.FindAllStringSubmatchIndex(content, -1) {
+		args := callArguments(content, strings.Index(content[loc[0]:], "(")+loc[0])
+		project.Schemes = append(project.Schemes, generatedScheme{
+			Name:      content[loc[2]:loc[3]],
+			HasXCTest: strings.Contains(args, "testAction:"),
+		})
+	}
+	// Tuist generates a scheme for every target
+	for _, target := range targets {
+		isDefined := false
+		for _, scheme := range project.Schemes {
+			isDefined = isDefined || scheme.Name == target.Name
+		}
+		if target.IsApp && target.isPlatform(projectType) && !isDefined {
+			project.Schemes = append(project.Schemes, generatedScheme{
+				Name:       target.Name,
+				HasXCTest:  hasTestTargetFor(target.Name, targets),
+				HasAppClip: target.IsAppClip,
+			})
+		}
+	}
+
+	return project, true
+}
+
+// generateProjectStepListItem returns the Script step generating the project, it has to run before the dependency managers.
+func generateProjectStepListItem(generator ProjectGenerator) bitriseModels.StepListItemModel {
+	if generator == ProjectGeneratorTuist {
+		return steps.ScriptSteplistItem(tuistGenerateScriptTitle, envmanModels.EnvironmentItemModel{"content": tuistGenerateScript})
+	}
+	return steps.ScriptSteplistItem(xcodeGenGenerateScriptTitle, envmanModels.EnvironmentItemModel{"content": xcodeGenGenerateScript})
+}
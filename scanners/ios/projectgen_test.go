@@ -0,0 +1,134 @@
+package ios
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseXcodeGenSpec(t *testing.T) {
+	spec := `name: MyApp
+packages:
+  Alamofire:
+    url: https://github.com/Alamofire/Alamofire
+    from: 5.0.0
+targets:
+  MyApp:
+    type: application
+    platform: iOS
+    scheme:
+      testTargets:
+        - MyAppTests
+  MyAppTests:
+    type: bundle.unit-test
+    platform: iOS
+    dependencies:
+      - target: MyApp
+  MyMacApp:
+    type: application
+    platform: macOS
+schemes:
+  MyApp-Staging:
+    build:
+      targets:
+        MyApp: all
+    test:
+      targets:
+        - name: MyAppTests
+`
+
+	t.Log("iOS project")
+	{
+		project, ok, err := parseXcodeGenSpec(XcodeProjectTypeIOS, "ios/project.yml", spec)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, generatedProject{
+			Generator:        ProjectGeneratorXcodeGen,
+			ManifestPth:      "ios/project.yml",
+			Pth:              "ios/MyApp.xcodeproj",
+			HasSwiftPackages: true,
+			Schemes: []generatedScheme{
+				{Name: "MyApp", HasXCTest: true},
+				{Name: "MyApp-Staging", HasXCTest: true},
+			},
+		}, project)
+	}
+
+	t.Log("macOS project without schemes")
+	{
+		project, ok, err := parseXcodeGenSpec(XcodeProjectTypeMacOS, "project.yml", spec)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.True(t, project.MissingSharedSchemes)
+		require.Equal(t, []generatedScheme{{Name: "MyMacApp"}}, project.Schemes)
+	}
+
+	t.Log("no target of the platform")
+	{
+		_, ok, err := parseXcodeGenSpec(XcodeProjectTypeMacOS, "project.yml", `name: MyApp
+targets:
+  MyApp:
+    type: application
+    platform: iOS
+`)
+		require.NoError(t, err)
+		require.False(t, ok)
+	}
+}
+
+func TestParseTuistManifest(t *testing.T) {
+	manifest := `import ProjectDescription
+
+let project = Project(
+    name: "MyApp",
+    targets: [
+        .target(
+            name: "MyApp",
+            destinations: [.iPhone, .iPad],
+            product: .app,
+            bundleId: "io.bitrise.MyApp",
+            sources: ["MyApp/Sources/**"],
+            dependencies: [
+                .external(name: "Alamofire"),
+            ]
+        ),
+        .target(
+            name: "MyAppTests",
+            destinations: .iOS,
+            product: .unitTests,
+            bundleId: "io.bitrise.MyAppTests",
+            sources: ["MyApp/Tests/**"],
+            dependencies: [.target(name: "MyApp")]
+        ),
+    ],
+    schemes: [
+        .scheme(
+            name: "MyApp-Production",
+            buildAction: .buildAction(targets: ["MyApp"]),
+            runAction: .runAction(configuration: "Release")
+        ),
+    ]
+)
+`
+
+	t.Log("iOS project")
+	{
+		project, ok := parseTuistManifest(XcodeProjectTypeIOS, "Project.swift", manifest, `let workspace = Workspace(name: "MyWorkspace", projects: ["."])`)
+		require.True(t, ok)
+		require.Equal(t, generatedProject{
+			Generator:   ProjectGeneratorTuist,
+			ManifestPth: "Project.swift",
+			Pth:         "MyWorkspace.xcworkspace",
+			Schemes: []generatedScheme{
+				{Name: "MyApp-Production"},
+				{Name: "MyApp", HasXCTest: true},
+			},
+		}, project)
+	}
+
+	t.Log("no target of the platform")
+	{
+		_, ok := parseTuistManifest(XcodeProjectTypeMacOS, "Project.swift", manifest, "")
+		require.False(t, ok)
+	}
+}
@@ -0,0 +1,90 @@
+package ios
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const (
+	remoteSwiftPackageReferenceISA = "XCRemoteSwiftPackageReference"
+	localSwiftPackageReferenceISA  = "XCLocalSwiftPackageReference"
+	packageResolvedBase            = "Package.resolved"
+)
+
+// SwiftPackages describes the Swift Package Manager dependencies of an Xcode project or workspace.
+type SwiftPackages struct {
+	// HasRemotePackages is true if a project references a package by its repository URL.
+	HasRemotePackages bool
+	// HasLocalPackages is true if a project references a package from the repository.
+	HasLocalPackages bool
+	// ResolvedPth is the path of the committed Package.resolved file, empty if not found.
+	ResolvedPth string
+}
+
+// HasDependencies ...
+func (packages SwiftPackages) HasDependencies() bool {
+	return packages.HasRemotePackages || packages.HasLocalPackages || packages.ResolvedPth != ""
+}
+
+// Warning returns a warning if remote packages are referenced, but their resolved versions are not committed.
+func (packages SwiftPackages) Warning(pth string) string {
+	if !packages.HasRemotePackages || packages.ResolvedPth != "" {
+		return ""
+	}
+	return fmt.Sprintf(`Swift packages are referenced by (%s), but no %s file is committed.
+Without it, the builds may resolve different package versions, and the Swift packages can not be cached.
+Commit the %s file found in the xcshareddata/swiftpm directory of the Xcode project or workspace.`, pth, packageResolvedBase, packageResolvedBase)
+}
+
+// resolvedFilePth returns the path of the Package.resolved file Xcode creates for the project or workspace.
+func resolvedFilePth(pth string) string {
+	if strings.HasSuffix(pth, ".xcodeproj") {
+		return filepath.Join(pth, "project.xcworkspace", "xcshareddata", "swiftpm", packageResolvedBase)
+	}
+	return filepath.Join(pth, "xcshareddata", "swiftpm", packageResolvedBase)
+}
+
+// DetectSwiftPackages looks for Swift package references in the given Xcode projects,
+// and for the Package.resolved file of the project or workspace at pth.
+// Relative paths are resolved against the searchDir.
+func DetectSwiftPackages(searchDir, pth string, projectPths ...string) (SwiftPackages, error) {
+	absPth := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(searchDir, p)
+	}
+
+	var packages SwiftPackages
+
+	resolvedPth := resolvedFilePth(pth)
+	if exist, err := pathutil.IsPathExists(absPth(resolvedPth)); err != nil {
+		return SwiftPackages{}, err
+	} else if exist {
+		packages.ResolvedPth = resolvedPth
+	}
+
+	for _, projectPth := range projectPths {
+		content, err := ioutil.ReadFile(filepath.Join(absPth(projectPth), "project.pbxproj"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return SwiftPackages{}, err
+		}
+
+		if strings.Contains(string(content), remoteSwiftPackageReferenceISA) {
+			packages.HasRemotePackages = true
+		}
+		if strings.Contains(string(content), localSwiftPackageReferenceISA) {
+			packages.HasLocalPackages = true
+		}
+	}
+
+	return packages, nil
+}
@@ -0,0 +1,53 @@
+package ios
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSwiftPackages(t *testing.T) {
+	searchDir, err := pathutil.NormalizedOSTempDirPath("__spm__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(searchDir))
+	}()
+
+	write := func(pth, content string) {
+		pth = filepath.Join(searchDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+	}
+
+	write("App.xcodeproj/project.pbxproj", `/* Begin XCRemoteSwiftPackageReference section */
+		1A2B3C /* XCRemoteSwiftPackageReference "Alamofire" */ = {
+			isa = XCRemoteSwiftPackageReference;
+		};`)
+	write("Local.xcodeproj/project.pbxproj", `isa = XCLocalSwiftPackageReference;`)
+	write("Plain.xcodeproj/project.pbxproj", `isa = PBXProject;`)
+
+	packages, err := DetectSwiftPackages(searchDir, "App.xcodeproj", "App.xcodeproj")
+	require.NoError(t, err)
+	require.Equal(t, SwiftPackages{HasRemotePackages: true}, packages)
+	require.True(t, packages.HasDependencies())
+	require.NotEmpty(t, packages.Warning("App.xcodeproj"))
+
+	write("App.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved", `{}`)
+	packages, err = DetectSwiftPackages(searchDir, "App.xcodeproj", "App.xcodeproj")
+	require.NoError(t, err)
+	require.Equal(t, SwiftPackages{HasRemotePackages: true, ResolvedPth: "App.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved"}, packages)
+	require.Empty(t, packages.Warning("App.xcodeproj"))
+
+	write("Workspace.xcworkspace/xcshareddata/swiftpm/Package.resolved", `{}`)
+	packages, err = DetectSwiftPackages(searchDir, "Workspace.xcworkspace", "Local.xcodeproj", "Plain.xcodeproj", "Missing.xcodeproj")
+	require.NoError(t, err)
+	require.Equal(t, SwiftPackages{HasLocalPackages: true, ResolvedPth: "Workspace.xcworkspace/xcshareddata/swiftpm/Package.resolved"}, packages)
+
+	packages, err = DetectSwiftPackages(searchDir, "Plain.xcodeproj", "Plain.xcodeproj")
+	require.NoError(t, err)
+	require.False(t, packages.HasDependencies())
+}
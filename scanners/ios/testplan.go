@@ -0,0 +1,233 @@
+package ios
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-xcode/xcodeproject/xcodeproj"
+)
+
+const (
+	// TestPlanInputEnvKey ...
+	TestPlanInputEnvKey = "BITRISE_TEST_PLAN"
+	// TestPlanInputTitle ...
+	TestPlanInputTitle = "Test plan"
+	// TestPlanInputSummary ...
+	TestPlanInputSummary = "The test plan of the scheme, which is run by the primary Workflow. A scheme can have several test plans, for example, for the unit and the snapshot tests. You can change this at any time."
+)
+
+const (
+	// UITestPlanInputEnvKey ...
+	UITestPlanInputEnvKey = "BITRISE_UI_TEST_PLAN"
+	// UITestPlanInputTitle ...
+	UITestPlanInputTitle = "UI test plan"
+	// UITestPlanInputSummary ...
+	UITestPlanInputSummary = "The test plan of the scheme, which runs the UI tests in the ui_test Workflow, separately from the faster tests of the primary Workflow. You can change this at any time."
+)
+
+// XcodebuildTestOptionsInputKey ...
+const XcodebuildTestOptionsInputKey = "xcodebuild_test_options"
+
+// UITestWorkflowID is the workflow running the UI test plan of the scheme.
+const UITestWorkflowID models.WorkflowID = "ui_test"
+
+const uiTestWorkflowDescription = `Runs the UI test plan of the scheme. UI tests are slow, so they run separately from the other tests.`
+
+const (
+	testPlanExt              = ".xctestplan"
+	containerReferencePrefix = "container:"
+)
+
+// TestPlans are the test plans of a scheme, the default plan comes first.
+type TestPlans struct {
+	Plans   []string
+	UIPlans []string
+	// Default is the default test plan of the scheme, which is run if no test plan is selected.
+	Default string
+}
+
+// xcschemeTestPlans is the part of an .xcscheme file, which references the test plans.
+type xcschemeTestPlans struct {
+	TestAction struct {
+		TestPlanReferences []struct {
+			Reference string `xml:"reference,attr"`
+			Default   string `xml:"default,attr"`
+		} `xml:"TestPlans>TestPlanReference"`
+	}
+}
+
+// xctestplan is the part of an .xctestplan file, which lists the test targets.
+type xctestplan struct {
+	TestTargets []struct {
+		Target struct {
+			ContainerPath string `json:"containerPath"`
+			Identifier    string `json:"identifier"`
+			Name          string `json:"name"`
+		} `json:"target"`
+	} `json:"testTargets"`
+}
+
+// readSharedScheme returns the content of the shared scheme and the project or workspace defining it,
+// nil is returned if none of the projects or workspaces defines the scheme.
+// Relative paths are resolved against the searchDir.
+func readSharedScheme(searchDir, scheme string, containerPths ...string) ([]byte, string, error) {
+	for _, containerPth := range containerPths {
+		if !filepath.IsAbs(containerPth) {
+			containerPth = filepath.Join(searchDir, containerPth)
+		}
+
+		content, err := ioutil.ReadFile(filepath.Join(containerPth, "xcshareddata", "xcschemes", scheme+".xcscheme"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, "", err
+		}
+		return content, containerPth, nil
+	}
+	return nil, "", nil
+}
+
+// DetectTestPlans returns the test plans of the shared scheme, which is defined by one of the projects or workspaces.
+// Relative paths are resolved against the searchDir.
+func DetectTestPlans(searchDir, scheme string, containerPths ...string) (TestPlans, error) {
+	content, containerPth, err := readSharedScheme(searchDir, scheme, containerPths...)
+	if err != nil || content == nil {
+		return TestPlans{}, err
+	}
+
+	var references xcschemeTestPlans
+	if err := xml.Unmarshal(content, &references); err != nil {
+		return TestPlans{}, err
+	}
+
+	// container: references are relative to the directory of the project or workspace
+	containerDir := filepath.Dir(containerPth)
+
+	var plans TestPlans
+	for _, reference := range references.TestAction.TestPlanReferences {
+		testPlanPth := filepath.Join(containerDir, strings.TrimPrefix(reference.Reference, containerReferencePrefix))
+		name := strings.TrimSuffix(filepath.Base(testPlanPth), testPlanExt)
+
+		isUITestPlan, err := isUITestPlan(containerDir, testPlanPth)
+		if err != nil {
+			log.TWarnf("Failed to read test plan (%s), error: %s", testPlanPth, err)
+		}
+
+		list := &plans.Plans
+		if isUITestPlan {
+			list = &plans.UIPlans
+		}
+		if reference.Default == "YES" {
+			plans.Default = name
+			*list = append([]string{name}, *list...)
+		} else {
+			*list = append(*list, name)
+		}
+	}
+	return plans, nil
+}
+
+// isUITestPlan returns true if every test target of the plan is a UI test target.
+func isUITestPlan(containerDir, testPlanPth string) (bool, error) {
+	content, err := fileutil.ReadBytesFromFile(testPlanPth)
+	if err != nil {
+		return false, err
+	}
+
+	var plan xctestplan
+	if err := json.Unmarshal(content, &plan); err != nil {
+		return false, err
+	}
+	if len(plan.TestTargets) == 0 {
+		return false, nil
+	}
+
+	projects := map[string]xcodeproj.XcodeProj{}
+	for _, testTarget := range plan.TestTargets {
+		target := testTarget.Target
+		projectPth := filepath.Join(containerDir, strings.TrimPrefix(target.ContainerPath, containerReferencePrefix))
+
+		project, ok := projects[projectPth]
+		if !ok {
+			if project, err = xcodeproj.Open(projectPth); err != nil {
+				// the project can not be inspected, fall back to the Xcode naming convention
+				log.TWarnf("Failed to open project (%s), error: %s", projectPth, err)
+				if !strings.HasSuffix(target.Name, "UITests") {
+					return false, nil
+				}
+				continue
+			}
+			projects[projectPth] = project
+		}
+
+		isUITestTarget := false
+		for _, t := range project.Proj.Targets {
+			if t.ID == target.Identifier || t.Name == target.Name {
+				isUITestTarget = t.IsUITestProduct()
+				break
+			}
+		}
+		if !isUITestTarget {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// HasTestPlans ...
+func (plans TestPlans) HasTestPlans() bool {
+	return len(plans.Plans) > 0
+}
+
+// HasUITestPlans ...
+func (plans TestPlans) HasUITestPlans() bool {
+	return len(plans.UIPlans) > 0
+}
+
+// selectsTestPlan returns true if the test plan of the primary Workflow needs to be selected:
+// the scheme has more than one (non UI) test plan, or its only one is not the default plan.
+func (plans TestPlans) selectsTestPlan() bool {
+	return len(plans.Plans) > 1 || (len(plans.Plans) == 1 && plans.Plans[0] != plans.Default)
+}
+
+// addOptions adds the test plan options of the scheme to the scheme option, the options of the next level are added by next.
+func (plans TestPlans) addOptions(schemeOption *models.OptionNode, scheme string, next AddOptionFunc) []*models.OptionNode {
+	addUITestPlanOptions := func(parent *models.OptionNode, value string) []*models.OptionNode {
+		if !plans.HasUITestPlans() {
+			return next(parent, value)
+		}
+
+		var leaves []*models.OptionNode
+		uiTestPlanOption := models.NewOption(UITestPlanInputTitle, UITestPlanInputSummary, UITestPlanInputEnvKey, models.TypeSelector)
+		parent.AddOption(value, uiTestPlanOption)
+		for _, plan := range plans.UIPlans {
+			leaves = append(leaves, next(uiTestPlanOption, plan)...)
+		}
+		return leaves
+	}
+
+	if !plans.selectsTestPlan() {
+		return addUITestPlanOptions(schemeOption, scheme)
+	}
+
+	var leaves []*models.OptionNode
+	testPlanOption := models.NewOption(TestPlanInputTitle, TestPlanInputSummary, TestPlanInputEnvKey, models.TypeSelector)
+	schemeOption.AddOption(scheme, testPlanOption)
+	for _, plan := range plans.Plans {
+		leaves = append(leaves, addUITestPlanOptions(testPlanOption, plan)...)
+	}
+	return leaves
+}
+
+// testPlanXcodebuildOption returns the xcodebuild option selecting the test plan stored in the env var.
+func testPlanXcodebuildOption(envKey string) string {
+	return `-testPlan "$` + envKey + `"`
+}
@@ -0,0 +1,70 @@
+package ios
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTestPlans(t *testing.T) {
+	searchDir, err := pathutil.NormalizedOSTempDirPath("__test_plans__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(searchDir))
+	}()
+
+	testPlan := func(target string) string {
+		return `{
+  "configurations" : [ { "id" : "1", "name" : "Default", "options" : {} } ],
+  "testTargets" : [
+    {
+      "target" : {
+        "containerPath" : "container:MyApp.xcodeproj",
+        "identifier" : "13E3E5A5",
+        "name" : "` + target + `"
+      }
+    }
+  ],
+  "version" : 1
+}`
+	}
+
+	for pth, content := range map[string]string{
+		"ios/MyApp.xcodeproj/xcshareddata/xcschemes/MyApp.xcscheme": `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme LastUpgradeVersion = "1500" version = "1.7">
+   <TestAction buildConfiguration = "Debug">
+      <TestPlans>
+         <TestPlanReference reference = "container:TestPlans/Snapshot.xctestplan"></TestPlanReference>
+         <TestPlanReference reference = "container:TestPlans/UI.xctestplan"></TestPlanReference>
+         <TestPlanReference reference = "container:TestPlans/Unit.xctestplan" default = "YES"></TestPlanReference>
+      </TestPlans>
+   </TestAction>
+</Scheme>`,
+		"ios/MyApp.xcodeproj/xcshareddata/xcschemes/Other.xcscheme": `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme LastUpgradeVersion = "1500" version = "1.7">
+   <TestAction buildConfiguration = "Debug"></TestAction>
+</Scheme>`,
+		"ios/TestPlans/Snapshot.xctestplan": testPlan("MyAppSnapshotTests"),
+		"ios/TestPlans/UI.xctestplan":       testPlan("MyAppUITests"),
+		"ios/TestPlans/Unit.xctestplan":     testPlan("MyAppTests"),
+	} {
+		pth = filepath.Join(searchDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+	}
+
+	plans, err := DetectTestPlans(searchDir, "MyApp", "ios/MyApp.xcworkspace", "ios/MyApp.xcodeproj")
+	require.NoError(t, err)
+	require.Equal(t, TestPlans{Plans: []string{"Unit", "Snapshot"}, UIPlans: []string{"UI"}, Default: "Unit"}, plans)
+	require.True(t, plans.selectsTestPlan())
+	require.False(t, TestPlans{Plans: []string{"Unit"}, UIPlans: []string{"UI"}, Default: "Unit"}.selectsTestPlan())
+	require.True(t, TestPlans{Plans: []string{"Unit"}, UIPlans: []string{"UI"}, Default: "UI"}.selectsTestPlan())
+
+	plans, err = DetectTestPlans(searchDir, "Other", "ios/MyApp.xcodeproj")
+	require.NoError(t, err)
+	require.Equal(t, TestPlans{}, plans)
+}
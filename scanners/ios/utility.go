@@ -10,10 +10,13 @@ import (
 	"github.com/bitrise-io/bitrise-init/analytics"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/fileutil"
 	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
 	"github.com/bitrise-io/go-xcode/xcodeproj"
 )
 
@@ -48,6 +51,8 @@ const (
 const (
 	// ExportMethodInputKey ...
 	ExportMethodInputKey = "export_method"
+	// DistributionMethodInputKey ...
+	DistributionMethodInputKey = "distribution_method"
 	// ExportMethodInputEnvKey ...
 	ExportMethodInputEnvKey = "BITRISE_EXPORT_METHOD"
 	// IosExportMethodInputTitle ...
@@ -92,19 +97,29 @@ var AllowCartfileBaseFilter = pathutil.BaseFilter(cartfileBase, true)
 
 // ConfigDescriptor ...
 type ConfigDescriptor struct {
+	ProjectGenerator     ProjectGenerator
 	HasPodfile           bool
 	CarthageCommand      string
+	HasSwiftPackages     bool
 	HasTest              bool
+	HasTestPlan          bool
+	HasUITestPlan        bool
+	HasConfigurations    bool
+	HasTestConfiguration bool
+	CodeSignStyle        CodeSignStyle
 	HasAppClip           bool
 	ExportMethod         string
 	MissingSharedSchemes bool
+	// Caches are the dependency caches of the projects using the config.
+	Caches []steps.DependencyCache
 }
 
 // NewConfigDescriptor ...
-func NewConfigDescriptor(hasPodfile bool, carthageCommand string, hasXCTest, hasAppClip bool, exportMethod string, missingSharedSchemes bool) ConfigDescriptor {
+func NewConfigDescriptor(hasPodfile bool, carthageCommand string, hasSwiftPackages, hasXCTest, hasAppClip bool, exportMethod string, missingSharedSchemes bool) ConfigDescriptor {
 	return ConfigDescriptor{
 		HasPodfile:           hasPodfile,
 		CarthageCommand:      carthageCommand,
+		HasSwiftPackages:     hasSwiftPackages,
 		HasTest:              hasXCTest,
 		HasAppClip:           hasAppClip,
 		ExportMethod:         exportMethod,
@@ -115,15 +130,36 @@ func NewConfigDescriptor(hasPodfile bool, carthageCommand string, hasXCTest, has
 // ConfigName ...
 func (descriptor ConfigDescriptor) ConfigName(projectType XcodeProjectType) string {
 	qualifiers := ""
+	if descriptor.ProjectGenerator != "" {
+		qualifiers += "-" + string(descriptor.ProjectGenerator)
+	}
 	if descriptor.HasPodfile {
 		qualifiers += "-pod"
 	}
 	if descriptor.CarthageCommand != "" {
 		qualifiers += "-carthage"
 	}
+	if descriptor.HasSwiftPackages {
+		qualifiers += "-spm"
+	}
 	if descriptor.HasTest {
 		qualifiers += "-test"
 	}
+	if descriptor.HasTestPlan {
+		qualifiers += "-test-plan"
+	}
+	if descriptor.HasUITestPlan {
+		qualifiers += "-ui-test-plan"
+	}
+	if descriptor.HasConfigurations {
+		qualifiers += "-configuration"
+	}
+	if descriptor.HasTestConfiguration {
+		qualifiers += "-test-configuration"
+	}
+	if descriptor.CodeSignStyle == CodeSignStyleAutomatic {
+		qualifiers += "-auto-signing"
+	}
 	if descriptor.HasAppClip {
 		qualifiers += fmt.Sprintf("-app-clip-%s", descriptor.ExportMethod)
 	}
@@ -156,11 +192,13 @@ func HasCartfileResolvedInDirectoryOf(pth string) bool {
 }
 
 // Detect ...
-func Detect(projectType XcodeProjectType, searchDir string) (bool, error) {
+// The projects in the excludedDirs (relative to the searchDir) are skipped.
+func Detect(projectType XcodeProjectType, searchDir string, excludedDirs ...string) (bool, error) {
 	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, true)
 	if err != nil {
 		return false, err
 	}
+	fileList = utility.ExcludePathsInDirs(fileList, excludedDirs)
 
 	log.TInfof("Filter relevant Xcode project files")
 
@@ -174,7 +212,17 @@ func Detect(projectType XcodeProjectType, searchDir string) (bool, error) {
 		log.TPrintf("- %s", xcodeprojectFile)
 	}
 
-	if len(relevantXcodeprojectFiles) == 0 {
+	generatedProjects, err := findGeneratedProjects(projectType, searchDir, fileList)
+	if err != nil {
+		return false, err
+	}
+
+	log.TPrintf("%d XcodeGen or Tuist %s project manifests found", len(generatedProjects), string(projectType))
+	for _, project := range generatedProjects {
+		log.TPrintf("- %s", project.ManifestPth)
+	}
+
+	if len(relevantXcodeprojectFiles) == 0 && len(generatedProjects) == 0 {
 		log.TPrintf("platform not detected")
 		return false, nil
 	}
@@ -269,28 +317,30 @@ func projectPathByScheme(projects []xcodeproj.ProjectModel, targetScheme string)
 }
 
 // GenerateOptions ...
-func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppIcon, suppressPodFileParseError bool) (models.OptionNode, []ConfigDescriptor, models.Icons, models.Warnings, error) {
+// The projects in the excludedDirs (relative to the searchDir) are skipped.
+func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppIcon, suppressPodFileParseError bool, excludedDirs ...string) (models.OptionNode, []ConfigDescriptor, models.Icons, models.Metadata, models.Warnings, error) {
 	warnings := models.Warnings{}
 
 	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, true)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, nil, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, models.Warnings{}, err
 	}
+	fileList = utility.ExcludePathsInDirs(fileList, excludedDirs)
 
 	// Separate workspaces and standalon projects
 	projectFiles, err := FilterRelevantProjectFiles(fileList, projectType)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, nil, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, models.Warnings{}, err
 	}
 
 	workspaceFiles, err := FilterRelevantWorkspaceFiles(fileList, projectType)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, nil, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, models.Warnings{}, err
 	}
 
 	standaloneProjects, workspaces, err := CreateStandaloneProjectsAndWorkspaces(projectFiles, workspaceFiles)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, nil, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, models.Warnings{}, err
 	}
 
 	exportMethodInputTitle := ""
@@ -311,14 +361,28 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 
 	podfiles, err := FilterRelevantPodfiles(fileList)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, nil, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, models.Warnings{}, err
 	}
 
 	log.TPrintf("%d Podfiles detected", len(podfiles))
 
+	generatedProjects, err := findGeneratedProjects(projectType, searchDir, fileList)
+	if err != nil {
+		return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, models.Warnings{}, err
+	}
+
 	for _, podfile := range podfiles {
 		log.TPrintf("- %s", podfile)
 
+		isGeneratedProjectPodfile := false
+		for _, project := range generatedProjects {
+			isGeneratedProjectPodfile = isGeneratedProjectPodfile || (project.HasPodfile && filepath.Dir(project.ManifestPth) == filepath.Dir(podfile))
+		}
+		if isGeneratedProjectPodfile {
+			// the project is generated on the CI, the Podfile is handled with the generated projects
+			continue
+		}
+
 		podfileParser := podfileParser{
 			podfilePth:                podfile,
 			suppressPodFileParseError: suppressPodFileParseError,
@@ -349,7 +413,7 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 
 	cartfiles, err := FilterRelevantCartFile(fileList)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, nil, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, models.Warnings{}, err
 	}
 
 	log.TPrintf("%d Cartfiles detected", len(cartfiles))
@@ -360,6 +424,12 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 	// Create config descriptors & options
 	configDescriptors := []ConfigDescriptor{}
 
+	addExportMethodOption := func(parent *models.OptionNode, value string) []*models.OptionNode {
+		exportMethodOption := models.NewOption(exportMethodInputTitle, exportMethodInputSummary, ExportMethodInputEnvKey, models.TypeSelector)
+		parent.AddOption(value, exportMethodOption)
+		return []*models.OptionNode{exportMethodOption}
+	}
+
 	defaultGitignorePth := filepath.Join(searchDir, ".gitignore")
 
 	projectPathOption := models.NewOption(ProjectPathInputTitle, ProjectPathInputSummary, ProjectPathInputEnvKey, models.TypeSelector)
@@ -367,6 +437,32 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 	// App icons, merged from every project
 	iconsForAllProjects := models.Icons{}
 
+	// The code signing of the schemes, by project or workspace path
+	metadata := models.Metadata{}
+	detectCodeSigning := func(containerPth, scheme string, containerPths []string) SchemeCodeSigning {
+		if projectType != XcodeProjectTypeIOS {
+			// the macOS configs keep installing the certificates and profiles
+			return SchemeCodeSigning{}
+		}
+
+		codeSigning, err := DetectSchemeCodeSigning(searchDir, scheme, containerPths, "")
+		if err != nil {
+			log.TWarnf("Failed to detect the code signing of scheme %s, error: %s", scheme, err)
+			return SchemeCodeSigning{}
+		}
+		if codeSigning.Style != "" {
+			log.TPrintf("  %s code signing, bundle IDs: %s", codeSigning.Style, strings.Join(codeSigning.BundleIDs, ", "))
+		}
+
+		for key, value := range codeSigning.Metadata(scheme) {
+			if metadata[containerPth] == nil {
+				metadata[containerPth] = map[string]string{}
+			}
+			metadata[containerPth][key] = value
+		}
+		return codeSigning
+	}
+
 	// Standalone Projects
 	for _, project := range standaloneProjects {
 		log.TInfof("Inspecting standalone project file: %s", project.Pth)
@@ -379,6 +475,7 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 			return models.OptionNode{},
 				[]ConfigDescriptor{},
 				nil,
+				nil,
 				warnings,
 				fmt.Errorf("failed to get project path, error: %s", err)
 		}
@@ -388,6 +485,19 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 			warnings = append(warnings, warning)
 		}
 
+		swiftPackages, err := DetectSwiftPackages(searchDir, project.Pth, project.Pth)
+		if err != nil {
+			return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, warnings, fmt.Errorf("failed to detect Swift packages, error: %s", err)
+		}
+		if warning := swiftPackages.Warning(project.Pth); warning != "" {
+			warnings = append(warnings, warning)
+		}
+
+		caches, err := projectDependencyCaches(searchDir, project.Pth)
+		if err != nil {
+			return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, warnings, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+		}
+
 		log.TPrintf("%d shared schemes detected", len(project.SharedSchemes))
 
 		if len(project.SharedSchemes) == 0 {
@@ -415,7 +525,8 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 				}
 
 				for _, exportMethod := range exportMethods {
-					configDescriptor := NewConfigDescriptor(false, carthageCommand, target.HasXCTest, target.HasAppClip, exportMethod, true)
+					configDescriptor := NewConfigDescriptor(false, carthageCommand, swiftPackages.HasDependencies(), target.HasXCTest, target.HasAppClip, exportMethod, true)
+					configDescriptor.Caches = caches
 					configDescriptors = append(configDescriptors, configDescriptor)
 					configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType), iconIDs)
 
@@ -426,8 +537,19 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 			for _, scheme := range project.SharedSchemes {
 				log.TPrintf("- %s", scheme.Name)
 
-				exportMethodOption := models.NewOption(exportMethodInputTitle, exportMethodInputSummary, ExportMethodInputEnvKey, models.TypeSelector)
-				schemeOption.AddOption(scheme.Name, exportMethodOption)
+				testPlans, err := DetectTestPlans(searchDir, scheme.Name, project.Pth)
+				if err != nil {
+					log.TWarnf("Failed to detect the test plans of scheme %s, error: %s", scheme.Name, err)
+				}
+				configurations, err := DetectSchemeConfigurations(searchDir, scheme.Name, []string{project.Pth}, []string{project.Pth})
+				if err != nil {
+					log.TWarnf("Failed to detect the build configurations of scheme %s, error: %s", scheme.Name, err)
+				}
+				codeSigning := detectCodeSigning(project.Pth, scheme.Name, []string{project.Pth})
+				hasTest := scheme.HasXCTest || testPlans.HasTestPlans()
+				exportMethodOptions := testPlans.addOptions(schemeOption, scheme.Name, func(parent *models.OptionNode, value string) []*models.OptionNode {
+					return configurations.AddOptions(parent, value, hasTest, addExportMethodOption)
+				})
 
 				iconIDs := []string{}
 				if !excludeAppIcon {
@@ -443,11 +565,18 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 				}
 
 				for _, exportMethod := range exportMethods {
-					configDescriptor := NewConfigDescriptor(false, carthageCommand, scheme.HasXCTest, schemeHasAppClipTarget(scheme, project.Targets), exportMethod, false)
+					configDescriptor := NewConfigDescriptor(false, carthageCommand, swiftPackages.HasDependencies(), hasTest, schemeHasAppClipTarget(scheme, project.Targets), exportMethod, false)
+					configDescriptor.Caches = caches
+					configDescriptor.HasTestPlan = testPlans.selectsTestPlan()
+					configDescriptor.HasUITestPlan = testPlans.HasUITestPlans()
+					configDescriptor.HasConfigurations = configurations.HasConfigurations()
+					configDescriptor.HasTestConfiguration = hasTest && configurations.HasTestConfiguration()
+					configDescriptor.CodeSignStyle = codeSigning.Style
 					configDescriptors = append(configDescriptors, configDescriptor)
-					configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType), iconIDs)
 
-					exportMethodOption.AddConfig(exportMethod, configOption)
+					for _, exportMethodOption := range exportMethodOptions {
+						exportMethodOption.AddConfig(exportMethod, models.NewConfigOption(configDescriptor.ConfigName(projectType), iconIDs))
+					}
 				}
 			}
 		}
@@ -465,6 +594,23 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 			warnings = append(warnings, warning)
 		}
 
+		var workspaceProjectPths []string
+		for _, project := range workspace.Projects {
+			workspaceProjectPths = append(workspaceProjectPths, project.Pth)
+		}
+		swiftPackages, err := DetectSwiftPackages(searchDir, workspace.Pth, workspaceProjectPths...)
+		if err != nil {
+			return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, warnings, fmt.Errorf("failed to detect Swift packages, error: %s", err)
+		}
+		if warning := swiftPackages.Warning(workspace.Pth); warning != "" {
+			warnings = append(warnings, warning)
+		}
+
+		caches, err := projectDependencyCaches(searchDir, append([]string{workspace.Pth}, workspaceProjectPths...)...)
+		if err != nil {
+			return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, warnings, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+		}
+
 		sharedSchemes := workspace.GetSharedSchemes()
 		log.TPrintf("%d shared schemes detected", len(sharedSchemes))
 
@@ -496,7 +642,8 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 					}
 
 					for _, exportMethod := range exportMethods {
-						configDescriptor := NewConfigDescriptor(workspace.IsPodWorkspace, carthageCommand, target.HasXCTest, target.HasAppClip, exportMethod, true)
+						configDescriptor := NewConfigDescriptor(workspace.IsPodWorkspace, carthageCommand, swiftPackages.HasDependencies(), target.HasXCTest, target.HasAppClip, exportMethod, true)
+						configDescriptor.Caches = caches
 						configDescriptors = append(configDescriptors, configDescriptor)
 						configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType), iconIDs)
 
@@ -508,8 +655,23 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 			for _, scheme := range sharedSchemes {
 				log.TPrintf("- %s", scheme.Name)
 
-				exportMethodOption := models.NewOption(exportMethodInputTitle, exportMethodInputSummary, ExportMethodInputEnvKey, models.TypeSelector)
-				schemeOption.AddOption(scheme.Name, exportMethodOption)
+				testPlans, err := DetectTestPlans(searchDir, scheme.Name, append([]string{workspace.Pth}, workspaceProjectPths...)...)
+				if err != nil {
+					log.TWarnf("Failed to detect the test plans of scheme %s, error: %s", scheme.Name, err)
+				}
+				schemeProjectPths := workspaceProjectPths
+				if projectPth := projectPathByScheme(workspace.Projects, scheme.Name); projectPth != "" {
+					schemeProjectPths = []string{projectPth}
+				}
+				configurations, err := DetectSchemeConfigurations(searchDir, scheme.Name, append([]string{workspace.Pth}, workspaceProjectPths...), schemeProjectPths)
+				if err != nil {
+					log.TWarnf("Failed to detect the build configurations of scheme %s, error: %s", scheme.Name, err)
+				}
+				codeSigning := detectCodeSigning(workspace.Pth, scheme.Name, append([]string{workspace.Pth}, workspaceProjectPths...))
+				hasTest := scheme.HasXCTest || testPlans.HasTestPlans()
+				exportMethodOptions := testPlans.addOptions(schemeOption, scheme.Name, func(parent *models.OptionNode, value string) []*models.OptionNode {
+					return configurations.AddOptions(parent, value, hasTest, addExportMethodOption)
+				})
 
 				iconIDs := []string{}
 				if !excludeAppIcon {
@@ -543,24 +705,77 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string, excludeAppI
 
 				for _, exportMethod := range exportMethods {
 					// only add appclip for development and ad-hoc
-					configDescriptor := NewConfigDescriptor(workspace.IsPodWorkspace, carthageCommand, scheme.HasXCTest, schemeHasAppClipTarget(scheme, workspace.GetTargets()), exportMethod, false)
+					configDescriptor := NewConfigDescriptor(workspace.IsPodWorkspace, carthageCommand, swiftPackages.HasDependencies(), hasTest, schemeHasAppClipTarget(scheme, workspace.GetTargets()), exportMethod, false)
+					configDescriptor.Caches = caches
+					configDescriptor.HasTestPlan = testPlans.selectsTestPlan()
+					configDescriptor.HasUITestPlan = testPlans.HasUITestPlans()
+					configDescriptor.HasConfigurations = configurations.HasConfigurations()
+					configDescriptor.HasTestConfiguration = hasTest && configurations.HasTestConfiguration()
+					configDescriptor.CodeSignStyle = codeSigning.Style
 					configDescriptors = append(configDescriptors, configDescriptor)
-					configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType), iconIDs)
 
-					exportMethodOption.AddConfig(exportMethod, configOption)
+					for _, exportMethodOption := range exportMethodOptions {
+						exportMethodOption.AddConfig(exportMethod, models.NewConfigOption(configDescriptor.ConfigName(projectType), iconIDs))
+					}
 				}
 			}
 		}
 	}
 
+	// Projects generated by XcodeGen or Tuist
+	for _, project := range generatedProjects {
+		log.TInfof("Inspecting %s manifest: %s", project.Generator, project.ManifestPth)
+
+		schemeOption := models.NewOption(SchemeInputTitle, SchemeInputSummary, SchemeInputEnvKey, models.TypeSelector)
+		projectPathOption.AddOption(project.Pth, schemeOption)
+
+		carthageCommand, warning := detectCarthageCommand(project.ManifestPth)
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+
+		caches, err := projectDependencyCaches(searchDir, project.ManifestPth)
+		if err != nil {
+			return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, warnings, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+		}
+
+		log.TPrintf("%d schemes detected", len(project.Schemes))
+
+		if project.MissingSharedSchemes {
+			warnings = append(warnings, fmt.Sprintf(`No schemes defined in %s, the schemes of the app targets will be recreated.
+Define the schemes in the manifest for the expected behaviour.`, project.ManifestPth))
+		}
+
+		// the project is generated on the CI, the test plans, build configurations and code signing are only known by the project
+		warnings = append(warnings, fmt.Sprintf(`The Xcode project is generated from %s, the test plans, build configurations and code signing of its schemes can not be detected.
+The workflows run the default test plan and build configuration of the scheme, and install the code signing files uploaded to Bitrise.`, project.ManifestPth))
+
+		for _, scheme := range project.Schemes {
+			log.TPrintf("- %s", scheme.Name)
+
+			exportMethodOption := models.NewOption(exportMethodInputTitle, exportMethodInputSummary, ExportMethodInputEnvKey, models.TypeSelector)
+			schemeOption.AddOption(scheme.Name, exportMethodOption)
+
+			for _, exportMethod := range exportMethods {
+				configDescriptor := NewConfigDescriptor(project.HasPodfile, carthageCommand, project.HasSwiftPackages, scheme.HasXCTest, scheme.HasAppClip, exportMethod, project.MissingSharedSchemes)
+				configDescriptor.Caches = caches
+				configDescriptor.ProjectGenerator = project.Generator
+				configDescriptors = append(configDescriptors, configDescriptor)
+				configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType), nil)
+
+				exportMethodOption.AddConfig(exportMethod, configOption)
+			}
+		}
+	}
+
 	configDescriptors = RemoveDuplicatedConfigDescriptors(configDescriptors, projectType)
 
 	if len(configDescriptors) == 0 {
 		log.TErrorf("No valid %s config found", string(projectType))
-		return models.OptionNode{}, []ConfigDescriptor{}, nil, warnings, fmt.Errorf("No valid %s config found", string(projectType))
+		return models.OptionNode{}, []ConfigDescriptor{}, nil, nil, warnings, fmt.Errorf("No valid %s config found", string(projectType))
 	}
 
-	return *projectPathOption, configDescriptors, iconsForAllProjects, warnings, nil
+	return *projectPathOption, configDescriptors, iconsForAllProjects, metadata, warnings, nil
 }
 
 // GenerateDefaultOptions ...
@@ -594,58 +809,112 @@ func GenerateDefaultOptions(projectType XcodeProjectType) models.OptionNode {
 	return *projectPathOption
 }
 
-// GenerateConfigBuilder ...
-func GenerateConfigBuilder(
-	projectType XcodeProjectType,
-	hasPodfile,
-	hasTest,
-	hasAppClip,
-	missingSharedSchemes bool,
-	carthageCommand string,
-	isIncludeCache bool,
-	exportMethod string,
-) models.ConfigBuilderModel {
-	configBuilder := models.NewDefaultConfigBuilder()
+// projectDependencyCaches returns the key-based dependency caches of an Xcode project or workspace,
+// the containerPths are the paths of the project, workspace or manifest and of the projects it references.
+func projectDependencyCaches(searchDir string, containerPths ...string) ([]steps.DependencyCache, error) {
+	projectDirs := []string{"."}
+	for _, pth := range containerPths {
+		dir := filepath.Dir(pth)
+		if !sliceutil.IsStringInSlice(dir, projectDirs) {
+			projectDirs = append(projectDirs, dir)
+		}
+	}
 
-	// CI
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(isIncludeCache)...)
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+	return utility.DetectDependencyCaches(searchDir, projectDirs,
+		utility.CocoaPodsDependencyManager,
+		utility.SwiftPackageManagerDependencyManager,
+		utility.BundlerDependencyManager,
+	)
+}
 
-	if missingSharedSchemes {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.RecreateUserSchemesStepListItem(
-			envmanModels.EnvironmentItemModel{ProjectPathInputKey: "$" + ProjectPathInputEnvKey},
-		))
-	}
+// GenerateConfigBuilder returns the config builder of the config described by the descriptor.
+func GenerateConfigBuilder(projectType XcodeProjectType, descriptor ConfigDescriptor, isIncludeCache bool, triggerPreset models.TriggerPreset) models.ConfigBuilderModel {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 
-	if hasPodfile {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CocoapodsInstallStepListItem())
+	var caches []steps.DependencyCache
+	for _, cache := range descriptor.Caches {
+		if !descriptor.HasPodfile && cache.Name == utility.CocoaPodsDependencyManager.Name {
+			continue
+		}
+		if !descriptor.HasSwiftPackages && cache.Name == utility.SwiftPackageManagerDependencyManager.Name {
+			continue
+		}
+		caches = append(caches, cache)
 	}
 
-	if carthageCommand != "" {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CarthageStepListItem(
-			envmanModels.EnvironmentItemModel{CarthageCommandInputKey: carthageCommand},
-		))
+	// the steps preparing the project and its dependencies for the Xcode steps
+	appendPrepareStepList := func(workflowID models.WorkflowID) {
+		configBuilder.AppendStepListItemsTo(workflowID, steps.DefaultPrepareStepList(isIncludeCache, caches...)...)
+		if descriptor.CodeSignStyle != CodeSignStyleAutomatic {
+			configBuilder.AppendStepListItemsTo(workflowID, steps.CertificateAndProfileInstallerStepListItem())
+		}
+
+		if descriptor.ProjectGenerator != "" {
+			configBuilder.AppendStepListItemsTo(workflowID, generateProjectStepListItem(descriptor.ProjectGenerator))
+		}
+
+		if descriptor.MissingSharedSchemes {
+			configBuilder.AppendStepListItemsTo(workflowID, steps.RecreateUserSchemesStepListItem(
+				envmanModels.EnvironmentItemModel{ProjectPathInputKey: "$" + ProjectPathInputEnvKey},
+			))
+		}
+
+		if descriptor.HasPodfile {
+			configBuilder.AppendStepListItemsTo(workflowID, steps.CocoapodsInstallStepListItem())
+		}
+
+		if descriptor.CarthageCommand != "" {
+			configBuilder.AppendStepListItemsTo(workflowID, steps.CarthageStepListItem(
+				envmanModels.EnvironmentItemModel{CarthageCommandInputKey: descriptor.CarthageCommand},
+			))
+		}
 	}
 
 	xcodeStepInputModels := []envmanModels.EnvironmentItemModel{
 		{ProjectPathInputKey: "$" + ProjectPathInputEnvKey},
 		{SchemeInputKey: "$" + SchemeInputEnvKey},
 	}
-	xcodeArchiveStepInputModels := append(xcodeStepInputModels, envmanModels.EnvironmentItemModel{ExportMethodInputKey: "$" + ExportMethodInputEnvKey})
 
-	if hasTest {
-		switch projectType {
-		case XcodeProjectTypeIOS:
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeTestStepListItem(xcodeStepInputModels...))
-		case XcodeProjectTypeMacOS:
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeTestMacStepListItem(xcodeStepInputModels...))
+	// the archive step builds the archive configuration, the test steps build the test configuration
+	xcodeArchiveStepInputModels := append([]envmanModels.EnvironmentItemModel{}, xcodeStepInputModels...)
+	if descriptor.HasConfigurations {
+		xcodeArchiveStepInputModels = append(xcodeArchiveStepInputModels, envmanModels.EnvironmentItemModel{ConfigurationInputKey: "$" + ConfigurationInputEnvKey})
+	}
+	xcodeArchiveStepInputModels = append(xcodeArchiveStepInputModels, envmanModels.EnvironmentItemModel{archiveExportMethodInputKey(projectType): "$" + ExportMethodInputEnvKey})
+	if descriptor.CodeSignStyle == CodeSignStyleAutomatic {
+		// the archive step manages the certificates and profiles using the App Store Connect API key connected to the app
+		xcodeArchiveStepInputModels = append(xcodeArchiveStepInputModels, envmanModels.EnvironmentItemModel{AutomaticCodeSigningInputKey: AutomaticCodeSigningInputAPIKeyValue})
+	}
+
+	xcodeTestStepBaseInputModels := append([]envmanModels.EnvironmentItemModel{}, xcodeStepInputModels...)
+	if descriptor.HasTestConfiguration {
+		xcodeTestStepBaseInputModels = append(xcodeTestStepBaseInputModels, envmanModels.EnvironmentItemModel{ConfigurationInputKey: "$" + TestConfigurationInputEnvKey})
+	}
+	xcodeTestStepInputModels := xcodeTestStepBaseInputModels
+	if descriptor.HasTestPlan {
+		xcodeTestStepInputModels = append(append([]envmanModels.EnvironmentItemModel{}, xcodeTestStepBaseInputModels...),
+			envmanModels.EnvironmentItemModel{XcodebuildTestOptionsInputKey: testPlanXcodebuildOption(TestPlanInputEnvKey)},
+		)
+	}
+
+	xcodeTestStepListItem := func(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+		if projectType == XcodeProjectTypeMacOS {
+			return steps.XcodeTestMacStepListItem(inputs...)
 		}
+		return steps.XcodeTestStepListItem(inputs...)
+	}
+
+	// CI
+	appendPrepareStepList(models.PrimaryWorkflowID)
+
+	if descriptor.HasTest {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, xcodeTestStepListItem(xcodeTestStepInputModels...))
 	} else {
 		switch projectType {
 		case XcodeProjectTypeIOS:
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeArchiveStepListItem(xcodeArchiveStepInputModels...))
 
-			if shouldAppendExportAppClipStep(hasAppClip, exportMethod) {
+			if shouldAppendExportAppClipStep(descriptor.HasAppClip, descriptor.ExportMethod) {
 				appendExportAppClipStep(configBuilder, models.PrimaryWorkflowID)
 			}
 		case XcodeProjectTypeMacOS:
@@ -653,43 +922,35 @@ func GenerateConfigBuilder(
 		}
 	}
 
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(isIncludeCache)...)
-
-	if hasTest {
-		// CD
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(isIncludeCache)...)
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(isIncludeCache, caches...)...)
 
-		if missingSharedSchemes {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.RecreateUserSchemesStepListItem(
-				envmanModels.EnvironmentItemModel{ProjectPathInputKey: "$" + ProjectPathInputEnvKey},
-			))
-		}
-
-		if hasPodfile {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CocoapodsInstallStepListItem())
-		}
+	if descriptor.HasUITestPlan {
+		// UI tests
+		appendPrepareStepList(UITestWorkflowID)
+		configBuilder.AppendStepListItemsTo(UITestWorkflowID, xcodeTestStepListItem(append(append([]envmanModels.EnvironmentItemModel{}, xcodeTestStepBaseInputModels...),
+			envmanModels.EnvironmentItemModel{XcodebuildTestOptionsInputKey: testPlanXcodebuildOption(UITestPlanInputEnvKey)},
+		)...))
+		configBuilder.AppendStepListItemsTo(UITestWorkflowID, steps.DefaultDeployStepList(isIncludeCache, caches...)...)
+		configBuilder.SetWorkflowDescriptionTo(UITestWorkflowID, uiTestWorkflowDescription)
+	}
 
-		if carthageCommand != "" {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CarthageStepListItem(
-				envmanModels.EnvironmentItemModel{CarthageCommandInputKey: carthageCommand},
-			))
-		}
+	if descriptor.HasTest {
+		// CD
+		appendPrepareStepList(models.DeployWorkflowID)
 
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, xcodeTestStepListItem(xcodeTestStepInputModels...))
 		switch projectType {
 		case XcodeProjectTypeIOS:
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeTestStepListItem(xcodeStepInputModels...))
 			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(xcodeArchiveStepInputModels...))
 
-			if shouldAppendExportAppClipStep(hasAppClip, exportMethod) {
+			if shouldAppendExportAppClipStep(descriptor.HasAppClip, descriptor.ExportMethod) {
 				appendExportAppClipStep(configBuilder, models.DeployWorkflowID)
 			}
 		case XcodeProjectTypeMacOS:
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeTestMacStepListItem(xcodeStepInputModels...))
 			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveMacStepListItem(xcodeArchiveStepInputModels...))
 		}
 
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(isIncludeCache)...)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(isIncludeCache, caches...)...)
 	}
 
 	return *configBuilder
@@ -700,6 +961,10 @@ func RemoveDuplicatedConfigDescriptors(configDescriptors []ConfigDescriptor, pro
 	descritorNameMap := map[string]ConfigDescriptor{}
 	for _, descriptor := range configDescriptors {
 		name := descriptor.ConfigName(projectType)
+		if existing, ok := descritorNameMap[name]; ok {
+			// the config is shared by several projects, it caches the dependencies of each
+			descriptor.Caches = utility.MergeDependencyCaches(append(existing.Caches, descriptor.Caches...)...)
+		}
 		descritorNameMap[name] = descriptor
 	}
 
@@ -712,18 +977,10 @@ func RemoveDuplicatedConfigDescriptors(configDescriptors []ConfigDescriptor, pro
 }
 
 // GenerateConfig ...
-func GenerateConfig(projectType XcodeProjectType, configDescriptors []ConfigDescriptor, isIncludeCache bool) (models.BitriseConfigMap, error) {
+func GenerateConfig(projectType XcodeProjectType, configDescriptors []ConfigDescriptor, isIncludeCache bool, triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
 	bitriseDataMap := models.BitriseConfigMap{}
 	for _, descriptor := range configDescriptors {
-		configBuilder := GenerateConfigBuilder(
-			projectType,
-			descriptor.HasPodfile,
-			descriptor.HasTest,
-			descriptor.HasAppClip,
-			descriptor.MissingSharedSchemes,
-			descriptor.CarthageCommand,
-			isIncludeCache,
-			descriptor.ExportMethod)
+		configBuilder := GenerateConfigBuilder(projectType, descriptor, isIncludeCache, triggerPreset)
 
 		config, err := configBuilder.Generate(string(projectType))
 		if err != nil {
@@ -742,8 +999,8 @@ func GenerateConfig(projectType XcodeProjectType, configDescriptors []ConfigDesc
 }
 
 // GenerateDefaultConfig ...
-func GenerateDefaultConfig(projectType XcodeProjectType, isIncludeCache bool) (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func GenerateDefaultConfig(projectType XcodeProjectType, isIncludeCache bool, triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(isIncludeCache)...)
 
 	// CI
@@ -758,7 +1015,7 @@ func GenerateDefaultConfig(projectType XcodeProjectType, isIncludeCache bool) (m
 		{ProjectPathInputKey: "$" + ProjectPathInputEnvKey},
 		{SchemeInputKey: "$" + SchemeInputEnvKey},
 	}
-	xcodeArchiveStepInputModels := append(xcodeTestStepInputModels, envmanModels.EnvironmentItemModel{ExportMethodInputKey: "$" + ExportMethodInputEnvKey})
+	xcodeArchiveStepInputModels := append(append([]envmanModels.EnvironmentItemModel{}, xcodeTestStepInputModels...), envmanModels.EnvironmentItemModel{archiveExportMethodInputKey(projectType): "$" + ExportMethodInputEnvKey})
 
 	switch projectType {
 	case XcodeProjectTypeIOS:
@@ -803,6 +1060,15 @@ func GenerateDefaultConfig(projectType XcodeProjectType, isIncludeCache bool) (m
 	}, nil
 }
 
+// archiveExportMethodInputKey returns the export method input key of the archive step of the project type:
+// xcode-archive calls it distribution method, while xcode-archive-mac still calls it export method.
+func archiveExportMethodInputKey(projectType XcodeProjectType) string {
+	if projectType == XcodeProjectTypeMacOS {
+		return ExportMethodInputKey
+	}
+	return DistributionMethodInputKey
+}
+
 func schemeHasAppClipTarget(scheme xcodeproj.SchemeModel, targets []xcodeproj.TargetModel) bool {
 	for _, target := range targets {
 		for _, referenceID := range scheme.BuildableReferenceIDs {
@@ -820,6 +1086,8 @@ func shouldAppendExportAppClipStep(hasAppClip bool, exportMethod string) bool {
 		(exportMethod == "development" || exportMethod == "ad-hoc")
 }
 
+// appendExportAppClipStep appends the export-xcarchive step exporting the App Clip.
+// Its pinned major version still reads the export method from the export_method input.
 func appendExportAppClipStep(configBuilder *models.ConfigBuilderModel, workflowID models.WorkflowID) {
 	exportXCArchiveStepInputModels := []envmanModels.EnvironmentItemModel{
 		{ExportXCArchiveProductInputKey: ExportXCArchiveProductInputAppClipValue},
@@ -1,20 +1,25 @@
 package ios
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestNewConfigDescriptor(t *testing.T) {
-	descriptor := NewConfigDescriptor(false, "", false, false, "development", true)
+	descriptor := NewConfigDescriptor(false, "", false, false, false, "development", true)
 	require.Equal(t, false, descriptor.HasPodfile)
 	require.Equal(t, false, descriptor.HasTest)
 	require.Equal(t, false, descriptor.HasAppClip)
 	require.Equal(t, "development", descriptor.ExportMethod)
 	require.Equal(t, true, descriptor.MissingSharedSchemes)
 	require.Equal(t, "", descriptor.CarthageCommand)
+	require.Equal(t, false, descriptor.HasSwiftPackages)
 }
 
 func TestConfigName(t *testing.T) {
@@ -25,52 +30,128 @@ func TestConfigName(t *testing.T) {
 
 	testCases := []testCase{
 		{
-			descriptor:         NewConfigDescriptor(false, "", false, false, "development", false),
+			descriptor:         NewConfigDescriptor(false, "", false, false, false, "development", false),
 			expectedConfigName: "ios-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(true, "", false, false, "development", false),
+			descriptor:         NewConfigDescriptor(true, "", false, false, false, "development", false),
 			expectedConfigName: "ios-pod-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(false, "bootsrap", false, false, "development", false),
+			descriptor:         NewConfigDescriptor(false, "bootsrap", false, false, false, "development", false),
 			expectedConfigName: "ios-carthage-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(false, "", true, false, "development", false),
+			descriptor:         NewConfigDescriptor(false, "", false, true, false, "development", false),
 			expectedConfigName: "ios-test-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(false, "", false, false, "development", true),
+			descriptor:         NewConfigDescriptor(false, "", false, false, false, "development", true),
 			expectedConfigName: "ios-missing-shared-schemes-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(true, "bootstrap", false, false, "development", false),
+			descriptor:         NewConfigDescriptor(false, "", true, false, false, "development", false),
+			expectedConfigName: "ios-spm-config",
+		},
+		{
+			descriptor:         NewConfigDescriptor(true, "bootstrap", false, false, false, "development", false),
 			expectedConfigName: "ios-pod-carthage-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(true, "bootstrap", true, false, "development", false),
+			descriptor:         NewConfigDescriptor(false, "bootstrap", true, true, false, "development", false),
+			expectedConfigName: "ios-carthage-spm-test-config",
+		},
+		{
+			descriptor:         NewConfigDescriptor(true, "bootstrap", false, true, false, "development", false),
 			expectedConfigName: "ios-pod-carthage-test-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(true, "bootstrap", true, false, "development", true),
+			descriptor:         NewConfigDescriptor(true, "bootstrap", false, true, false, "development", true),
 			expectedConfigName: "ios-pod-carthage-test-missing-shared-schemes-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(false, "", false, true, "development", false),
+			descriptor:         NewConfigDescriptor(false, "", false, false, true, "development", false),
 			expectedConfigName: "ios-app-clip-development-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(false, "", false, true, "ad-hoc", false),
+			descriptor:         NewConfigDescriptor(false, "", false, false, true, "ad-hoc", false),
 			expectedConfigName: "ios-app-clip-ad-hoc-config",
 		},
 		{
-			descriptor:         NewConfigDescriptor(false, "", true, true, "development", false),
+			descriptor:         NewConfigDescriptor(false, "", false, true, true, "development", false),
 			expectedConfigName: "ios-test-app-clip-development-config",
 		},
+		{
+			descriptor:         ConfigDescriptor{ProjectGenerator: ProjectGeneratorXcodeGen, HasPodfile: true, HasTest: true, ExportMethod: "development"},
+			expectedConfigName: "ios-xcodegen-pod-test-config",
+		},
+		{
+			descriptor:         ConfigDescriptor{HasTest: true, HasTestPlan: true, HasUITestPlan: true, ExportMethod: "development"},
+			expectedConfigName: "ios-test-test-plan-ui-test-plan-config",
+		},
+		{
+			descriptor:         ConfigDescriptor{HasConfigurations: true, ExportMethod: "development"},
+			expectedConfigName: "ios-configuration-config",
+		},
+		{
+			descriptor:         ConfigDescriptor{HasTest: true, HasConfigurations: true, HasTestConfiguration: true, ExportMethod: "development"},
+			expectedConfigName: "ios-test-configuration-test-configuration-config",
+		},
+		{
+			descriptor:         ConfigDescriptor{HasTest: true, CodeSignStyle: CodeSignStyleAutomatic, ExportMethod: "development"},
+			expectedConfigName: "ios-test-auto-signing-config",
+		},
+		{
+			descriptor:         ConfigDescriptor{HasTest: true, CodeSignStyle: CodeSignStyleManual, ExportMethod: "development"},
+			expectedConfigName: "ios-test-config",
+		},
 	}
 
 	for _, testcase := range testCases {
 		assert.Equal(t, testcase.expectedConfigName, testcase.descriptor.ConfigName(XcodeProjectTypeIOS))
 	}
 }
+
+func TestProjectDependencyCaches(t *testing.T) {
+	searchDir := t.TempDir()
+	for _, pth := range []string{
+		"Gemfile.lock",
+		"PodsApp/PodsApp.xcworkspace/contents.xcworkspacedata",
+		"PodsApp/Podfile.lock",
+		"SPMApp/SPMApp.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(searchDir, filepath.Dir(pth)), 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(searchDir, pth), nil, 0600))
+	}
+
+	caches, err := projectDependencyCaches(searchDir, "PodsApp/PodsApp.xcworkspace")
+	require.NoError(t, err)
+	require.Equal(t, []steps.DependencyCache{
+		{Name: "cocoapods", KeyFiles: []string{"PodsApp/Podfile.lock"}, Paths: []string{"PodsApp/Pods"}},
+		{Name: "bundler", KeyFiles: []string{"Gemfile.lock"}, Paths: []string{"vendor/bundle"}},
+	}, caches)
+
+	caches, err = projectDependencyCaches(searchDir, "SPMApp/SPMApp.xcodeproj")
+	require.NoError(t, err)
+	require.Equal(t, []steps.DependencyCache{
+		{Name: "spm", KeyFiles: []string{"SPMApp/SPMApp.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved"}, Paths: []string{"~/Library/Developer/Xcode/DerivedData/**/SourcePackages"}},
+		{Name: "bundler", KeyFiles: []string{"Gemfile.lock"}, Paths: []string{"vendor/bundle"}},
+	}, caches)
+}
+
+func TestRemoveDuplicatedConfigDescriptorsMergesCaches(t *testing.T) {
+	descriptors := RemoveDuplicatedConfigDescriptors([]ConfigDescriptor{
+		{HasPodfile: true, ExportMethod: "development", Caches: []steps.DependencyCache{{Name: "cocoapods", KeyFiles: []string{"A/Podfile.lock"}, Paths: []string{"A/Pods"}}}},
+		{HasPodfile: true, ExportMethod: "development", Caches: []steps.DependencyCache{{Name: "cocoapods", KeyFiles: []string{"B/Podfile.lock"}, Paths: []string{"B/Pods"}}}},
+		{HasSwiftPackages: true, ExportMethod: "development", Caches: []steps.DependencyCache{{Name: "spm", KeyFiles: []string{"C/Package.resolved"}}}},
+	}, XcodeProjectTypeIOS)
+
+	caches := map[string][]steps.DependencyCache{}
+	for _, descriptor := range descriptors {
+		caches[descriptor.ConfigName(XcodeProjectTypeIOS)] = descriptor.Caches
+	}
+	require.Equal(t, map[string][]steps.DependencyCache{
+		"ios-pod-config": {{Name: "cocoapods", KeyFiles: []string{"A/Podfile.lock", "B/Podfile.lock"}, Paths: []string{"A/Pods", "B/Pods"}}},
+		"ios-spm-config": {{Name: "spm", KeyFiles: []string{"C/Package.resolved"}}},
+	}, caches)
+}
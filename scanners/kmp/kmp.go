@@ -0,0 +1,306 @@
+package kmp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/scanners/android"
+	"github.com/bitrise-io/bitrise-init/scanners/ios"
+	"github.com/bitrise-io/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+// Scanner ...
+type Scanner struct {
+	projects []project
+
+	// configDescriptors holds the descriptors of the configs offered in the options, by config name.
+	configDescriptors map[string]configDescriptor
+}
+
+// NewScanner ...
+func NewScanner() *Scanner {
+	return &Scanner{}
+}
+
+// Name ...
+func (Scanner) Name() string {
+	return ScannerName
+}
+
+// DetectPlatform ...
+func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
+	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, true)
+	if err != nil {
+		return false, err
+	}
+
+	log.TInfof("Search for Kotlin Multiplatform project(s)")
+	scanner.projects = nil
+	for _, pth := range fileList {
+		if base := filepath.Base(pth); base != "settings.gradle" && base != "settings.gradle.kts" {
+			continue
+		}
+		if components := strings.Split(pth, string(filepath.Separator)); hasAnyComponent(components, "node_modules", ".git", "build") {
+			continue
+		}
+
+		proj, err := detectProject(searchDir, filepath.Dir(pth), fileList)
+		if err != nil {
+			return false, fmt.Errorf("failed to inspect Gradle project at %s, error: %s", filepath.Dir(pth), err)
+		}
+		if len(proj.sharedModules) == 0 {
+			continue
+		}
+
+		log.TPrintf("- Project: %s", proj.path)
+		log.TPrintf("  Shared modules: %s", strings.Join(proj.sharedModules, ", "))
+		log.TPrintf("  Android app modules: %s", strings.Join(proj.androidModules, ", "))
+		for xcodeProjectPath, schemes := range proj.xcodeProjects {
+			log.TPrintf("  iOS app: %s (schemes: %s)", xcodeProjectPath, strings.Join(schemes, ", "))
+		}
+		log.TPrintf("  CocoaPods integration: %t", proj.cocoapods)
+
+		scanner.projects = append(scanner.projects, proj)
+	}
+
+	return len(scanner.projects) > 0, nil
+}
+
+func hasAnyComponent(components []string, names ...string) bool {
+	for _, component := range components {
+		for _, name := range names {
+			if component == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExcludedScannerNames ...
+func (Scanner) ExcludedScannerNames() []string {
+	return []string{
+		string(ios.XcodeProjectTypeIOS),
+		android.ScannerName,
+	}
+}
+
+// ExcludedDirs returns the directories of the Kotlin Multiplatform projects,
+// the iOS and Android scanners still scan the apps outside of them.
+func (scanner *Scanner) ExcludedDirs() []string {
+	var dirs []string
+	for _, proj := range scanner.projects {
+		dirs = append(dirs, proj.path)
+	}
+	return dirs
+}
+
+// Options ...
+func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	projectLocationOption := models.NewOption(ProjectLocationInputTitle, ProjectLocationInputSummary, ProjectLocationInputEnvKey, models.TypeSelector)
+	warnings := models.Warnings{}
+
+	scanner.configDescriptors = map[string]configDescriptor{}
+
+	for _, proj := range scanner.projects {
+		caches, err := proj.dependencyCaches()
+		if err != nil {
+			return models.OptionNode{}, warnings, nil, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+		}
+
+		descriptor := configDescriptor{
+			android:   len(proj.androidModules) > 0,
+			ios:       len(proj.xcodeProjects) > 0,
+			cocoapods: proj.cocoapods,
+			caches:    caches,
+		}
+
+		for xcodeProjectPath, schemes := range proj.xcodeProjects {
+			if len(schemes) == 0 {
+				warnings = append(warnings, fmt.Sprintf("no shared scheme found in %s, share the scheme of the iOS app in Xcode (Product > Scheme > Manage Schemes)", xcodeProjectPath))
+			}
+		}
+
+		sharedModuleOption := models.NewOption(SharedModuleInputTitle, SharedModuleInputSummary, SharedModuleInputEnvKey, models.TypeSelector)
+		projectLocationOption.AddOption(proj.path, sharedModuleOption)
+
+		for _, sharedModule := range proj.sharedModules {
+			if !descriptor.android {
+				scanner.addIOSOptions(sharedModuleOption, sharedModule, proj, descriptor)
+				continue
+			}
+
+			moduleOption := models.NewOption(android.ModuleInputTitle, android.ModuleInputSummary, android.ModuleInputEnvKey, models.TypeSelector)
+			sharedModuleOption.AddOption(sharedModule, moduleOption)
+			for _, module := range proj.androidModules {
+				variants := proj.androidVariants[module]
+				variantOption := models.NewOption(android.VariantInputTitle, android.VariantInputSummary, android.VariantInputEnvKey, models.TypeOptionalSelector)
+				if len(variants) == 0 {
+					variantOption = models.NewOption(android.VariantInputTitle, android.VariantInputSummary, android.VariantInputEnvKey, models.TypeOptionalUserInput)
+					variants = []string{""}
+				}
+				moduleOption.AddOption(module, variantOption)
+
+				for _, variant := range variants {
+					scanner.addIOSOptions(variantOption, variant, proj, descriptor)
+				}
+			}
+		}
+	}
+
+	return *projectLocationOption, warnings, nil, nil
+}
+
+// addIOSOptions adds the iOS app options of the project (if any) to the parent option, under the given value.
+func (scanner *Scanner) addIOSOptions(parent *models.OptionNode, value string, proj project, descriptor configDescriptor) {
+	if !descriptor.ios {
+		scanner.addConfig(parent, value, descriptor)
+		return
+	}
+
+	projectPathOption := models.NewOption(ios.ProjectPathInputTitle, ios.ProjectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeSelector)
+	parent.AddOption(value, projectPathOption)
+
+	var xcodeProjectPaths []string
+	for xcodeProjectPath := range proj.xcodeProjects {
+		xcodeProjectPaths = append(xcodeProjectPaths, xcodeProjectPath)
+	}
+	sort.Strings(xcodeProjectPaths)
+
+	for _, xcodeProjectPath := range xcodeProjectPaths {
+		schemes := proj.xcodeProjects[xcodeProjectPath]
+		schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeSelector)
+		if len(schemes) == 0 {
+			schemeOption = models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
+			schemes = []string{""}
+		}
+		projectPathOption.AddOption(xcodeProjectPath, schemeOption)
+
+		for _, scheme := range schemes {
+			configurations := proj.schemeConfigurations[xcodeProjectPath][scheme]
+
+			schemeDescriptor := descriptor
+			schemeDescriptor.iosConfigurations = configurations.HasConfigurations()
+			exportMethodOptions := configurations.AddOptions(schemeOption, scheme, false, func(parent *models.OptionNode, value string) []*models.OptionNode {
+				exportMethodOption := models.NewOption(ios.IosExportMethodInputTitle, ios.IosExportMethodInputSummary, ios.ExportMethodInputEnvKey, models.TypeSelector)
+				parent.AddOption(value, exportMethodOption)
+				return []*models.OptionNode{exportMethodOption}
+			})
+
+			for _, exportMethodOption := range exportMethodOptions {
+				for _, exportMethod := range ios.IosExportMethods {
+					scanner.addConfig(exportMethodOption, exportMethod, schemeDescriptor)
+				}
+			}
+		}
+	}
+}
+
+// addConfig adds the config of the descriptor to the parent option, under the given value.
+// The configs shared by several projects cache the dependencies of each.
+func (scanner *Scanner) addConfig(parent *models.OptionNode, value string, descriptor configDescriptor) {
+	if existing, ok := scanner.configDescriptors[descriptor.configName()]; ok {
+		descriptor.caches = utility.MergeDependencyCaches(append(existing.caches, descriptor.caches...)...)
+	}
+	scanner.configDescriptors[descriptor.configName()] = descriptor
+	parent.AddConfig(value, models.NewConfigOption(descriptor.configName(), nil))
+}
+
+// DefaultOptions ...
+func (Scanner) DefaultOptions() models.OptionNode {
+	projectLocationOption := models.NewOption(ProjectLocationInputTitle, ProjectLocationInputSummary, ProjectLocationInputEnvKey, models.TypeUserInput)
+
+	sharedModuleOption := models.NewOption(SharedModuleInputTitle, SharedModuleInputSummary, SharedModuleInputEnvKey, models.TypeUserInput)
+	projectLocationOption.AddOption("", sharedModuleOption)
+
+	platformOption := models.NewOption(platformInputTitle, platformInputSummary, "", models.TypeSelector)
+	sharedModuleOption.AddOption("", platformOption)
+
+	for _, platform := range platforms {
+		descriptor := configDescriptor{
+			android: platform == "android" || platform == "both",
+			ios:     platform == "ios" || platform == "both",
+		}
+
+		parent, value := platformOption, platform
+		if descriptor.android {
+			moduleOption := models.NewOption(android.ModuleInputTitle, android.ModuleInputSummary, android.ModuleInputEnvKey, models.TypeUserInput)
+			platformOption.AddOption(platform, moduleOption)
+			variantOption := models.NewOption(android.VariantInputTitle, android.VariantInputSummary, android.VariantInputEnvKey, models.TypeOptionalUserInput)
+			moduleOption.AddOption("", variantOption)
+			parent, value = variantOption, ""
+		}
+
+		if !descriptor.ios {
+			parent.AddConfig(value, models.NewConfigOption(descriptor.configName(), nil))
+			continue
+		}
+
+		projectPathOption := models.NewOption(ios.ProjectPathInputTitle, ios.ProjectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeUserInput)
+		parent.AddOption(value, projectPathOption)
+
+		schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
+		projectPathOption.AddOption("", schemeOption)
+
+		exportMethodOption := models.NewOption(ios.IosExportMethodInputTitle, ios.IosExportMethodInputSummary, ios.ExportMethodInputEnvKey, models.TypeSelector)
+		schemeOption.AddOption("", exportMethodOption)
+
+		for _, exportMethod := range ios.IosExportMethods {
+			exportMethodOption.AddConfig(exportMethod, models.NewConfigOption(descriptor.configName(), nil))
+		}
+	}
+
+	return *projectLocationOption
+}
+
+// Configs ...
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configs := models.BitriseConfigMap{}
+	for name, descriptor := range scanner.configDescriptors {
+		config, err := generateConfig(descriptor, triggerPreset)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+		configs[name] = config
+	}
+	return configs, nil
+}
+
+// DefaultConfigs ...
+func (Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configs := models.BitriseConfigMap{}
+	for _, platform := range platforms {
+		descriptor := configDescriptor{
+			android: platform == "android" || platform == "both",
+			ios:     platform == "ios" || platform == "both",
+		}
+		config, err := generateConfig(descriptor, triggerPreset)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+		configs[descriptor.configName()] = config
+	}
+	return configs, nil
+}
+
+func generateConfig(descriptor configDescriptor, triggerPreset models.TriggerPreset) (string, error) {
+	configBuilder := generateConfigBuilder(descriptor, triggerPreset)
+
+	config, err := configBuilder.Generate(ScannerName)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,78 @@
+package kmp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/scanners/ios"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectProject(t *testing.T) {
+	searchDir, err := pathutil.NormalizedOSTempDirPath("__kmp__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(searchDir))
+	}()
+
+	for pth, content := range map[string]string{
+		"settings.gradle.kts": `include(":shared", ":androidApp")`,
+		"gradle/libs.versions.toml": `[plugins]
+kotlinMultiplatform = { id = "org.jetbrains.kotlin.multiplatform", version = "1.9.20" }`,
+		"shared/build.gradle.kts": `plugins {
+    alias(libs.plugins.kotlinMultiplatform)
+    kotlin("native.cocoapods")
+    id("com.android.library")
+}`,
+		"androidApp/build.gradle.kts": `plugins {
+    id("com.android.application")
+    kotlin("android")
+}`,
+	} {
+		pth = filepath.Join(searchDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+	}
+
+	proj, err := detectProject(searchDir, ".", nil)
+	require.NoError(t, err)
+	require.Equal(t, project{
+		path:                 ".",
+		sharedModules:        []string{"shared"},
+		cocoapods:            true,
+		androidModules:       []string{"androidApp"},
+		androidVariants:      map[string][]string{"androidApp": {"debug", "release"}},
+		xcodeProjects:        map[string][]string{},
+		schemeConfigurations: map[string]map[string]ios.SchemeConfigurations{},
+	}, proj)
+}
+
+func TestFindXcodeProjects(t *testing.T) {
+	fileList := []string{
+		"kmp/iosApp/iosApp.xcodeproj",
+		"kmp/iosApp/iosApp.xcodeproj/project.xcworkspace",
+		"kmp/iosApp/iosApp.xcworkspace",
+		"kmp/iosApp/Pods/Pods.xcodeproj",
+		"kmp/watchApp/watchApp.xcodeproj",
+		"other/other.xcodeproj",
+	}
+
+	xcodeProjects, err := findXcodeProjects("kmp", fileList)
+	require.NoError(t, err)
+	require.Equal(t, []string{"kmp/iosApp/iosApp.xcworkspace", "kmp/watchApp/watchApp.xcodeproj"}, xcodeProjects)
+}
+
+func TestConfigDescriptorConfigName(t *testing.T) {
+	require.Equal(t, "kmp-config", configDescriptor{}.configName())
+	require.Equal(t, "kmp-android-config", configDescriptor{android: true, cocoapods: true}.configName())
+	require.Equal(t, "kmp-android-ios-cocoapods-config", configDescriptor{android: true, ios: true, cocoapods: true}.configName())
+	require.Equal(t, "kmp-ios-configuration-config", configDescriptor{ios: true, iosConfigurations: true}.configName())
+}
+
+func TestExcludedDirs(t *testing.T) {
+	scanner := Scanner{projects: []project{{path: "kmp"}, {path: "apps/shared"}}}
+	require.Equal(t, []string{"kmp", "apps/shared"}, scanner.ExcludedDirs())
+}
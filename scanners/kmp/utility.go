@@ -0,0 +1,362 @@
+package kmp
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/scanners/android"
+	"github.com/bitrise-io/bitrise-init/scanners/ios"
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/bitrise-init/utility"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"github.com/bitrise-io/go-xcode/pathfilters"
+	"github.com/bitrise-io/go-xcode/xcodeproject/xcodeproj"
+	"github.com/bitrise-io/go-xcode/xcodeproject/xcworkspace"
+)
+
+const (
+	// ScannerName ...
+	ScannerName = "kmp"
+	// ConfigName ...
+	ConfigName = "kmp-config"
+
+	multiplatformPluginID = "org.jetbrains.kotlin.multiplatform"
+	cocoapodsPluginID     = "org.jetbrains.kotlin.native.cocoapods"
+)
+
+const (
+	// ProjectLocationInputEnvKey ...
+	ProjectLocationInputEnvKey = "KMP_PROJECT_LOCATION"
+	// ProjectLocationInputTitle ...
+	ProjectLocationInputTitle = "Kotlin Multiplatform project location"
+	// ProjectLocationInputSummary ...
+	ProjectLocationInputSummary = "The root directory of the Gradle project holding the Kotlin Multiplatform modules, stored as an Environment Variable. In your Workflows, you can specify paths relative to this path. You can change this at any time."
+)
+
+const (
+	// SharedModuleInputEnvKey ...
+	SharedModuleInputEnvKey = "KMP_SHARED_MODULE"
+	// SharedModuleInputTitle ...
+	SharedModuleInputTitle = "Shared module"
+	// SharedModuleInputSummary ...
+	SharedModuleInputSummary = "The Kotlin Multiplatform module, which holds the code shared by the Android and iOS apps. Its allTests task runs the tests of every target. You can change this at any time."
+)
+
+const (
+	platformInputTitle   = "Platform"
+	platformInputSummary = "The apps built by the deploy Workflow. Your options are Android, iOS, both, or neither."
+)
+
+var platforms = []string{"none", "android", "ios", "both"}
+
+// project is a Gradle project with Kotlin Multiplatform modules.
+type project struct {
+	// path is the root directory of the Gradle project, relative to the search dir.
+	path string
+	// sharedModules are the modules applying the Kotlin Multiplatform plugin.
+	sharedModules []string
+	// cocoapods is true if a shared module is integrated into the iOS app by the Kotlin CocoaPods plugin.
+	cocoapods bool
+	// androidModules are the Android application modules.
+	androidModules []string
+	// androidVariants are the build variants of the Android application modules, by module.
+	androidVariants map[string][]string
+	// xcodeProjects are the iOS app Xcode projects (or workspaces) and their shared schemes, relative to the search dir.
+	xcodeProjects map[string][]string
+	// schemeConfigurations are the build configurations of the shared schemes, by Xcode project (or workspace) and scheme.
+	schemeConfigurations map[string]map[string]ios.SchemeConfigurations
+}
+
+// configDescriptor describes the config generated for a project.
+type configDescriptor struct {
+	android   bool
+	ios       bool
+	cocoapods bool
+	// iosConfigurations is true if the iOS app is archived with the build configuration selected by the configuration option.
+	iosConfigurations bool
+	// caches are the dependency caches of the projects using the config, they do not affect the config name.
+	caches []steps.DependencyCache
+}
+
+func (descriptor configDescriptor) configName() string {
+	var modifiers []string
+	if descriptor.android {
+		modifiers = append(modifiers, "android")
+	}
+	if descriptor.ios {
+		modifiers = append(modifiers, "ios")
+		if descriptor.cocoapods {
+			modifiers = append(modifiers, "cocoapods")
+		}
+		if descriptor.iosConfigurations {
+			modifiers = append(modifiers, "configuration")
+		}
+	}
+	if len(modifiers) == 0 {
+		return ConfigName
+	}
+	return strings.TrimSuffix(ConfigName, "-config") + "-" + strings.Join(modifiers, "-") + "-config"
+}
+
+// dependencyCaches returns the dependency caches of the project: the Gradle caches,
+// and the CocoaPods caches of the iOS apps if the shared module is integrated by the Kotlin CocoaPods plugin.
+func (proj project) dependencyCaches() ([]steps.DependencyCache, error) {
+	caches, err := utility.DetectDependencyCaches(".", []string{proj.path}, utility.GradleDependencyManager)
+	if err != nil {
+		return nil, err
+	}
+
+	if proj.cocoapods {
+		for xcodeProjectPath := range proj.xcodeProjects {
+			iosCaches, err := utility.DetectDependencyCaches(".", []string{filepath.Dir(xcodeProjectPath)}, utility.CocoaPodsDependencyManager)
+			if err != nil {
+				return nil, err
+			}
+			caches = append(caches, iosCaches...)
+		}
+	}
+
+	return utility.MergeDependencyCaches(caches...), nil
+}
+
+// detectProject returns the Kotlin Multiplatform modules and the apps of the Gradle project,
+// fileList contains the paths of the search dir, relative to the search dir.
+func detectProject(searchDir, projectPath string, fileList []string) (project, error) {
+	proj := project{
+		path:                 projectPath,
+		androidVariants:      map[string][]string{},
+		xcodeProjects:        map[string][]string{},
+		schemeConfigurations: map[string]map[string]ios.SchemeConfigurations{},
+	}
+
+	modules, err := android.GradleModules(filepath.Join(searchDir, projectPath))
+	if err != nil {
+		return project{}, err
+	}
+
+	for _, module := range modules {
+		if module.Application {
+			proj.androidModules = append(proj.androidModules, module.Name)
+			proj.androidVariants[module.Name] = module.Variants
+		}
+		if sliceutil.IsStringInSlice(multiplatformPluginID, module.PluginIDs) {
+			proj.sharedModules = append(proj.sharedModules, module.Name)
+			if sliceutil.IsStringInSlice(cocoapodsPluginID, module.PluginIDs) {
+				proj.cocoapods = true
+			}
+		}
+	}
+	if len(proj.sharedModules) == 0 {
+		return proj, nil
+	}
+
+	xcodeProjectPaths, err := findXcodeProjects(projectPath, fileList)
+	if err != nil {
+		return project{}, err
+	}
+	for _, xcodeProjectPath := range xcodeProjectPaths {
+		schemes, err := sharedSchemes(filepath.Join(searchDir, xcodeProjectPath))
+		if err != nil {
+			log.TWarnf("Failed to read the schemes of %s, error: %s", xcodeProjectPath, err)
+			continue
+		}
+		proj.xcodeProjects[xcodeProjectPath] = schemes
+
+		proj.schemeConfigurations[xcodeProjectPath] = map[string]ios.SchemeConfigurations{}
+		for _, scheme := range schemes {
+			configurations, err := ios.DetectSchemeConfigurations(searchDir, scheme, []string{xcodeProjectPath}, xcodeProjectFiles(searchDir, xcodeProjectPath))
+			if err != nil {
+				log.TWarnf("Failed to detect the build configurations of scheme %s, error: %s", scheme, err)
+				continue
+			}
+			proj.schemeConfigurations[xcodeProjectPath][scheme] = configurations
+		}
+	}
+
+	return proj, nil
+}
+
+// findXcodeProjects returns the Xcode workspaces and projects inside the Gradle project,
+// a project is left out if a workspace exists next to it (created by CocoaPods).
+func findXcodeProjects(projectPath string, fileList []string) ([]string, error) {
+	var projectFiles []string
+	for _, pth := range fileList {
+		if rel, err := filepath.Rel(projectPath, pth); err == nil && !strings.HasPrefix(rel, "..") {
+			projectFiles = append(projectFiles, pth)
+		}
+	}
+
+	filters := []pathutil.FilterFunc{
+		pathfilters.ForbidEmbeddedWorkspaceRegexpFilter,
+		pathfilters.ForbidGitDirComponentFilter,
+		pathfilters.ForbidPodsDirComponentFilter,
+		pathfilters.ForbidCarthageDirComponentFilter,
+		pathfilters.ForbidFramworkComponentWithExtensionFilter,
+		pathfilters.ForbidNodeModulesComponentFilter,
+		pathutil.ComponentFilter("build", false),
+	}
+
+	workspaces, err := pathutil.FilterPaths(projectFiles, append([]pathutil.FilterFunc{pathfilters.AllowXCWorkspaceExtFilter}, filters...)...)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := pathutil.FilterPaths(projectFiles, append([]pathutil.FilterFunc{pathfilters.AllowXcodeProjExtFilter}, filters...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	xcodeProjects := append([]string{}, workspaces...)
+	for _, xcodeProject := range projects {
+		standalone := true
+		for _, workspace := range workspaces {
+			if filepath.Dir(workspace) == filepath.Dir(xcodeProject) {
+				standalone = false
+				break
+			}
+		}
+		if standalone {
+			xcodeProjects = append(xcodeProjects, xcodeProject)
+		}
+	}
+	return xcodeProjects, nil
+}
+
+// xcodeProjectFiles returns the Xcode project of a project path, or the Xcode projects of a workspace (except the Pods project),
+// relative to the search dir.
+func xcodeProjectFiles(searchDir, pth string) []string {
+	if xcodeproj.IsXcodeProj(pth) {
+		return []string{pth}
+	}
+
+	workspace, err := xcworkspace.Open(filepath.Join(searchDir, pth))
+	if err != nil {
+		return nil
+	}
+	locations, err := workspace.ProjectFileLocations()
+	if err != nil {
+		return nil
+	}
+
+	var projectPths []string
+	for _, location := range locations {
+		if filepath.Base(location) == "Pods.xcodeproj" {
+			continue
+		}
+		if rel, err := filepath.Rel(searchDir, location); err == nil {
+			projectPths = append(projectPths, rel)
+		}
+	}
+	return projectPths
+}
+
+// sharedSchemes returns the names of the shared schemes of an Xcode project or workspace.
+func sharedSchemes(pth string) ([]string, error) {
+	var names []string
+	if xcodeproj.IsXcodeProj(pth) {
+		proj, err := xcodeproj.Open(pth)
+		if err != nil {
+			return nil, err
+		}
+		schemes, err := proj.Schemes()
+		if err != nil {
+			return nil, err
+		}
+		for _, scheme := range schemes {
+			names = append(names, scheme.Name)
+		}
+		return names, nil
+	}
+
+	workspace, err := xcworkspace.Open(pth)
+	if err != nil {
+		return nil, err
+	}
+	schemesByContainer, err := workspace.Schemes()
+	if err != nil {
+		return nil, err
+	}
+	for _, schemes := range schemesByContainer {
+		for _, scheme := range schemes {
+			if !sliceutil.IsStringInSlice(scheme.Name, names) {
+				names = append(names, scheme.Name)
+			}
+		}
+	}
+	return names, nil
+}
+
+func gradleTask(task string) string {
+	return ":$" + SharedModuleInputEnvKey + ":" + task
+}
+
+func generateConfigBuilder(descriptor configDescriptor, triggerPreset models.TriggerPreset) models.ConfigBuilderModel {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+	caches := descriptor.caches
+	gradlewPath := "$" + ProjectLocationInputEnvKey + "/gradlew"
+
+	//-- primary
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(true, caches...)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
+		envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: gradlewPath},
+	))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.GradleRunnerStepListItem(
+		envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: gradlewPath},
+		envmanModels.EnvironmentItemModel{android.GradleTaskInputKey: gradleTask("allTests")},
+	))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(true, caches...)...)
+
+	if !descriptor.android && !descriptor.ios {
+		return *configBuilder
+	}
+
+	//-- deploy
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(true, caches...)...)
+	if descriptor.ios {
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+	}
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
+		envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: gradlewPath},
+	))
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.GradleRunnerStepListItem(
+		envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: gradlewPath},
+		envmanModels.EnvironmentItemModel{android.GradleTaskInputKey: gradleTask("allTests")},
+	))
+
+	if descriptor.android {
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.AndroidBuildStepListItem(
+			envmanModels.EnvironmentItemModel{android.ProjectLocationInputKey: "$" + ProjectLocationInputEnvKey},
+			envmanModels.EnvironmentItemModel{android.ModuleInputKey: "$" + android.ModuleInputEnvKey},
+			envmanModels.EnvironmentItemModel{android.VariantInputKey: "$" + android.VariantInputEnvKey},
+		))
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.SignAPKStepListItem())
+	}
+
+	if descriptor.ios {
+		if descriptor.cocoapods {
+			// the podInstall task generates the shared framework's podspec and a placeholder framework before installing the pods,
+			// a plain pod install fails, as the framework is built by Gradle
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.GradleRunnerStepListItem(
+				envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: gradlewPath},
+				envmanModels.EnvironmentItemModel{android.GradleTaskInputKey: gradleTask("podInstall")},
+			))
+		}
+		// without a detected configuration, the scheme is archived with the configuration of its archive action
+		xcodeArchiveInputs := []envmanModels.EnvironmentItemModel{
+			{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
+			{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
+			{ios.DistributionMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
+		}
+		if descriptor.iosConfigurations {
+			xcodeArchiveInputs = append(xcodeArchiveInputs, envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "$" + ios.ConfigurationInputEnvKey})
+		}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(xcodeArchiveInputs...))
+	}
+
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(true, caches...)...)
+
+	return *configBuilder
+}
@@ -0,0 +1,259 @@
+package maui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/bitrise-init/utility"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const (
+	projectInputEnvKey  = "BITRISE_PROJECT_PATH"
+	projectInputTitle   = "Path to the .NET MAUI project file"
+	projectInputSummary = "The csproj file of your .NET MAUI app, which uses <UseMaui>true</UseMaui>. It is published by the dotnet CLI."
+)
+
+const (
+	targetFrameworkInputEnvKey  = "BITRISE_MAUI_TARGET_FRAMEWORK"
+	targetFrameworkInputTitle   = "Target framework"
+	targetFrameworkInputSummary = "The target framework of the app you wish to publish in your first build, like net8.0-ios or net8.0-android. You can change this at any time in your Workflows."
+)
+
+const (
+	configurationInputEnvKey  = "BITRISE_MAUI_CONFIGURATION"
+	configurationInputTitle   = ".NET MAUI build configuration"
+	configurationInputSummary = "The build configuration that you wish to run in your first build. You can change this at any time in your Workflows."
+)
+
+const (
+	platformInputTitle   = "Platform"
+	platformInputSummary = "The platform of the target framework. Your options are iOS or Android."
+)
+
+const (
+	workloadRestoreScriptTitle = "Restore .NET workloads"
+	publishScriptTitle         = "Publish the app"
+)
+
+var platforms = []string{iosPlatform, androidPlatform}
+
+func configName(platform string) string {
+	return "maui-" + platform + "-config"
+}
+
+func defaultConfigName(platform string) string {
+	return "default-" + configName(platform)
+}
+
+// Scanner ...
+type Scanner struct {
+	projects []project
+}
+
+// NewScanner ...
+func NewScanner() *Scanner {
+	return &Scanner{}
+}
+
+// Name ...
+func (Scanner) Name() string {
+	return ScannerName
+}
+
+// DetectPlatform ...
+func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
+	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, true)
+	if err != nil {
+		return false, fmt.Errorf("failed to search for files in (%s), error: %s", searchDir, err)
+	}
+
+	log.TInfof("Searching for .NET MAUI projects")
+
+	projectFiles, err := FilterProjectFiles(fileList)
+	if err != nil {
+		return false, fmt.Errorf("failed to search for project files, error: %s", err)
+	}
+
+	scanner.projects = nil
+	for _, projectFile := range projectFiles {
+		content, err := fileutil.ReadStringFromFile(filepath.Join(searchDir, projectFile))
+		if err != nil {
+			return false, fmt.Errorf("failed to read project file (%s), error: %s", projectFile, err)
+		}
+
+		proj, isMAUI, err := parseProjectContent(projectFile, content)
+		if err != nil {
+			log.TWarnf("Failed to parse project file (%s), error: %s", projectFile, err)
+			continue
+		}
+		if !isMAUI {
+			continue
+		}
+		if len(proj.targetFrameworks) == 0 {
+			log.TWarnf("No iOS or Android target framework found in %s", projectFile)
+			continue
+		}
+
+		log.TPrintf("- %s", proj.path)
+		log.TPrintf("  Target frameworks: %s", strings.Join(proj.targetFrameworks, ", "))
+		log.TPrintf("  Configurations: %s", strings.Join(proj.configurations, ", "))
+
+		scanner.projects = append(scanner.projects, proj)
+	}
+
+	if len(scanner.projects) == 0 {
+		log.TPrintf("platform not detected")
+		return false, nil
+	}
+
+	log.TSuccessf("Platform detected")
+
+	return true, nil
+}
+
+// ExcludedScannerNames ...
+func (Scanner) ExcludedScannerNames() []string {
+	return []string{}
+}
+
+// Options ...
+func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	projectOption := models.NewOption(projectInputTitle, projectInputSummary, projectInputEnvKey, models.TypeSelector)
+
+	for _, proj := range scanner.projects {
+		targetFrameworkOption := models.NewOption(targetFrameworkInputTitle, targetFrameworkInputSummary, targetFrameworkInputEnvKey, models.TypeSelector)
+		projectOption.AddOption(proj.path, targetFrameworkOption)
+
+		for _, targetFramework := range proj.targetFrameworks {
+			configurationOption := models.NewOption(configurationInputTitle, configurationInputSummary, configurationInputEnvKey, models.TypeSelector)
+			targetFrameworkOption.AddOption(targetFramework, configurationOption)
+
+			for _, configuration := range proj.configurations {
+				configOption := models.NewConfigOption(configName(targetFrameworkPlatform(targetFramework)), nil)
+				configurationOption.AddConfig(configuration, configOption)
+			}
+		}
+	}
+
+	return *projectOption, models.Warnings{}, nil, nil
+}
+
+// DefaultOptions ...
+func (Scanner) DefaultOptions() models.OptionNode {
+	projectOption := models.NewOption(projectInputTitle, projectInputSummary, projectInputEnvKey, models.TypeUserInput)
+
+	platformOption := models.NewOption(platformInputTitle, platformInputSummary, "", models.TypeSelector)
+	projectOption.AddOption("", platformOption)
+
+	for _, platform := range platforms {
+		targetFrameworkOption := models.NewOption(targetFrameworkInputTitle, targetFrameworkInputSummary, targetFrameworkInputEnvKey, models.TypeUserInput)
+		platformOption.AddOption(platform, targetFrameworkOption)
+
+		configurationOption := models.NewOption(configurationInputTitle, configurationInputSummary, configurationInputEnvKey, models.TypeUserInput)
+		targetFrameworkOption.AddOption("", configurationOption)
+
+		configOption := models.NewConfigOption(defaultConfigName(platform), nil)
+		configurationOption.AddConfig("", configOption)
+	}
+
+	return *projectOption
+}
+
+// Configs ...
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	var projectDirs []string
+	configPlatforms := map[string]bool{}
+	for _, proj := range scanner.projects {
+		projectDirs = append(projectDirs, filepath.Dir(proj.path))
+		for _, targetFramework := range proj.targetFrameworks {
+			configPlatforms[targetFrameworkPlatform(targetFramework)] = true
+		}
+	}
+	caches, err := utility.DetectDependencyCaches(".", projectDirs, utility.NuGetDependencyManager)
+	if err != nil {
+		return models.BitriseConfigMap{}, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+	}
+
+	configs := models.BitriseConfigMap{}
+	for platform := range configPlatforms {
+		config, err := generateConfig(platform, triggerPreset, caches...)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+		configs[configName(platform)] = config
+	}
+	return configs, nil
+}
+
+// DefaultConfigs ...
+func (Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configs := models.BitriseConfigMap{}
+	for _, platform := range platforms {
+		config, err := generateConfig(platform, triggerPreset)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+		configs[defaultConfigName(platform)] = config
+	}
+	return configs, nil
+}
+
+func generateConfig(platform string, triggerPreset models.TriggerPreset, caches ...steps.DependencyCache) (string, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+
+	if platform == iosPlatform {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+	}
+
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ScriptSteplistItem(workloadRestoreScriptTitle,
+		envmanModels.EnvironmentItemModel{"content": workloadRestoreScript()},
+	))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ScriptSteplistItem(publishScriptTitle,
+		envmanModels.EnvironmentItemModel{"content": publishScript(platform)},
+	))
+
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
+
+	config, err := configBuilder.Generate(ScannerName)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func workloadRestoreScript() string {
+	return fmt.Sprintf(`#!/usr/bin/env bash
+set -ex
+
+dotnet workload restore "$%s"`, projectInputEnvKey)
+}
+
+// publishScript returns the script publishing the selected target framework and configuration of the project into the deploy dir:
+// the signed ipa for iOS, the signed aab and apk for Android.
+func publishScript(platform string) string {
+	args := fmt.Sprintf(`"$%s" --framework "$%s" --configuration "$%s" --output "$BITRISE_DEPLOY_DIR"`, projectInputEnvKey, targetFrameworkInputEnvKey, configurationInputEnvKey)
+	if platform == iosPlatform {
+		// the ipa is only created for a device runtime
+		args += " -p:RuntimeIdentifier=ios-arm64"
+	}
+
+	return fmt.Sprintf(`#!/usr/bin/env bash
+set -ex
+
+dotnet publish %s`, args)
+}
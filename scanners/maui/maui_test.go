@@ -0,0 +1,82 @@
+package maui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterProjectFiles(t *testing.T) {
+	fileList := []string{
+		"MauiApp/MauiApp.csproj",
+		"MauiApp/obj/Debug/MauiApp.csproj",
+		"MauiApp/bin/Release/MauiApp.csproj",
+		"MauiApp.sln",
+	}
+
+	files, err := FilterProjectFiles(fileList)
+	require.NoError(t, err)
+	require.Equal(t, []string{"MauiApp/MauiApp.csproj"}, files)
+}
+
+func TestParseProjectContent(t *testing.T) {
+	t.Log("MAUI project")
+	{
+		proj, isMAUI, err := parseProjectContent("MauiApp/MauiApp.csproj", testMAUIProjectContent)
+		require.NoError(t, err)
+		require.True(t, isMAUI)
+		require.Equal(t, project{
+			path:             "MauiApp/MauiApp.csproj",
+			targetFrameworks: []string{"net8.0-android", "net8.0-ios"},
+			configurations:   []string{"Debug", "Release"},
+		}, proj)
+	}
+
+	t.Log("class library")
+	{
+		proj, isMAUI, err := parseProjectContent("Core/Core.csproj", `<Project Sdk="Microsoft.NET.Sdk">
+	<PropertyGroup>
+		<TargetFramework>net8.0</TargetFramework>
+		<Configurations>Debug;Release;Staging</Configurations>
+	</PropertyGroup>
+</Project>`)
+		require.NoError(t, err)
+		require.False(t, isMAUI)
+		require.Equal(t, []string(nil), proj.targetFrameworks)
+		require.Equal(t, []string{"Debug", "Release", "Staging"}, proj.configurations)
+	}
+}
+
+func TestTargetFrameworkPlatform(t *testing.T) {
+	require.Equal(t, "ios", targetFrameworkPlatform("net8.0-ios"))
+	require.Equal(t, "android", targetFrameworkPlatform("net9.0-android35.0"))
+	require.Equal(t, "", targetFrameworkPlatform("net8.0-maccatalyst"))
+}
+
+const testMAUIProjectContent = `<Project Sdk="Microsoft.NET.Sdk">
+
+	<PropertyGroup>
+		<TargetFrameworks>net8.0-android;net8.0-ios;net8.0-maccatalyst</TargetFrameworks>
+		<TargetFrameworks Condition="$([MSBuild]::IsOSPlatform('windows'))">$(TargetFrameworks);net8.0-windows10.0.19041.0</TargetFrameworks>
+
+		<OutputType>Exe</OutputType>
+		<RootNamespace>MauiApp</RootNamespace>
+		<UseMaui>true</UseMaui>
+		<SingleProject>true</SingleProject>
+		<ImplicitUsings>enable</ImplicitUsings>
+
+		<ApplicationTitle>MauiApp</ApplicationTitle>
+		<ApplicationId>com.companyname.mauiapp</ApplicationId>
+		<ApplicationDisplayVersion>1.0</ApplicationDisplayVersion>
+		<ApplicationVersion>1</ApplicationVersion>
+	</PropertyGroup>
+
+	<ItemGroup>
+		<MauiIcon Include="Resources\AppIcon\appicon.svg" ForegroundFile="Resources\AppIcon\appiconfg.svg" Color="#512BD4" />
+	</ItemGroup>
+
+	<ItemGroup>
+		<PackageReference Include="Microsoft.Maui.Controls" Version="$(MauiVersion)" />
+	</ItemGroup>
+
+</Project>`
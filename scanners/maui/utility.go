@@ -0,0 +1,131 @@
+package maui
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+const (
+	// ScannerName ...
+	ScannerName = "maui"
+
+	projectExtension = ".csproj"
+
+	iosPlatform     = "ios"
+	androidPlatform = "android"
+)
+
+// defaultConfigurations are the configurations offered if the project does not declare its Configurations.
+var defaultConfigurations = []string{"Debug", "Release"}
+
+// targetFrameworkRegexp matches the iOS and Android target framework monikers, like net8.0-ios or net8.0-android34.0.
+var targetFrameworkRegexp = regexp.MustCompile(`^net\d+\.\d+-(ios|android)[\d.]*$`)
+
+var allowProjectExtensionFilter = pathutil.ExtensionFilter(projectExtension, true)
+var forbidBinDirComponentFilter = pathutil.ComponentFilter("bin", false)
+var forbidObjDirComponentFilter = pathutil.ComponentFilter("obj", false)
+var forbidNodeModulesDirComponentFilter = pathutil.ComponentFilter("node_modules", false)
+
+// project is a .NET MAUI app project.
+type project struct {
+	// path is the path of the csproj file, relative to the search dir.
+	path string
+	// targetFrameworks are the iOS and Android target frameworks of the project, like net8.0-ios.
+	targetFrameworks []string
+	// configurations are the build configurations of the project.
+	configurations []string
+}
+
+type propertyGroupModel struct {
+	UseMaui          []string `xml:"UseMaui"`
+	TargetFramework  []string `xml:"TargetFramework"`
+	TargetFrameworks []string `xml:"TargetFrameworks"`
+	Configurations   []string `xml:"Configurations"`
+}
+
+type projectModel struct {
+	PropertyGroups []propertyGroupModel `xml:"PropertyGroup"`
+}
+
+// FilterProjectFiles ...
+func FilterProjectFiles(fileList []string) ([]string, error) {
+	return pathutil.FilterPaths(fileList,
+		allowProjectExtensionFilter,
+		forbidBinDirComponentFilter,
+		forbidObjDirComponentFilter,
+		forbidNodeModulesDirComponentFilter)
+}
+
+// IsMAUIProject returns true if the csproj file at pth is an SDK-style project using .NET MAUI (<UseMaui>true</UseMaui>).
+func IsMAUIProject(pth string) (bool, error) {
+	content, err := fileutil.ReadStringFromFile(pth)
+	if err != nil {
+		return false, err
+	}
+	_, isMAUI, err := parseProjectContent(pth, content)
+	return isMAUI, err
+}
+
+// parseProjectContent parses the csproj content, and returns the project and whether it uses .NET MAUI.
+func parseProjectContent(pth, content string) (project, bool, error) {
+	var model projectModel
+	if err := xml.Unmarshal([]byte(content), &model); err != nil {
+		return project{}, false, err
+	}
+
+	isMAUI := false
+	proj := project{path: pth}
+	for _, group := range model.PropertyGroups {
+		for _, useMaui := range group.UseMaui {
+			isMAUI = isMAUI || strings.EqualFold(strings.TrimSpace(useMaui), "true")
+		}
+
+		for _, value := range append(group.TargetFramework, group.TargetFrameworks...) {
+			for _, targetFramework := range splitPropertyList(value) {
+				if targetFrameworkRegexp.MatchString(targetFramework) && !sliceutil.IsStringInSlice(targetFramework, proj.targetFrameworks) {
+					proj.targetFrameworks = append(proj.targetFrameworks, targetFramework)
+				}
+			}
+		}
+
+		for _, value := range group.Configurations {
+			for _, configuration := range splitPropertyList(value) {
+				if !sliceutil.IsStringInSlice(configuration, proj.configurations) {
+					proj.configurations = append(proj.configurations, configuration)
+				}
+			}
+		}
+	}
+	if len(proj.configurations) == 0 {
+		proj.configurations = defaultConfigurations
+	}
+
+	return proj, isMAUI, nil
+}
+
+// splitPropertyList splits a semicolon separated MSBuild property list,
+// the items referencing properties (like $(TargetFrameworks)) are skipped.
+func splitPropertyList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" || strings.Contains(item, "$(") {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// targetFrameworkPlatform returns the platform (ios or android) of the target framework.
+func targetFrameworkPlatform(targetFramework string) string {
+	if match := targetFrameworkRegexp.FindStringSubmatch(targetFramework); len(match) == 2 {
+		return match[1]
+	}
+	return ""
+}
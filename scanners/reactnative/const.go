@@ -37,7 +37,7 @@ and **Force code signing with Provisioning Profile** inputs regarding to the upl
 1. Specify manual codesign style
 If the codesigning files, are generated manually on the Apple Developer Portal,  
 you need to explicitly specify to use manual coedsign settings  
-(as ejected rn projects have xcode managed codesigning turned on).  
+(as the generated rn projects have xcode managed codesigning turned on).  
 To do so, add 'CODE_SIGN_STYLE="Manual"' to 'Additional options for xcodebuild call' input
 
 ## To run this workflow
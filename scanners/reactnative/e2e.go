@@ -0,0 +1,369 @@
+package reactnative
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/scanners/android"
+	"github.com/bitrise-io/bitrise-init/scanners/ios"
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// E2ETestWorkflowID is the workflow running the end-to-end tests of the app.
+const E2ETestWorkflowID models.WorkflowID = "e2e_test"
+
+const (
+	iosPlatform     = "ios"
+	androidPlatform = "android"
+
+	maestroFlowsDirName = ".maestro"
+)
+
+const e2eTestWorkflowDescription = `Builds the app for testing and runs the end-to-end tests (Detox configurations and Maestro flows) on an iOS simulator or Android emulator.`
+
+const (
+	installAppleSimUtilsScriptTitle = "Install applesimutils"
+	installAppleSimUtilsScript      = `#!/usr/bin/env bash
+set -ex
+
+brew tap wix/brew
+brew install applesimutils`
+
+	detoxBuildScriptTitleFormat = "Build the Detox configuration %s"
+	detoxTestScriptTitleFormat  = "Run the Detox configuration %s"
+
+	maestroTestScriptTitle = "Run the Maestro flows"
+	installMaestroScript   = `curl -Ls "https://get.maestro.mobile.dev" | bash
+export PATH="$PATH:$HOME/.maestro/bin"
+`
+	maestroAndroidInstallScript = `adb install -r "$BITRISE_APK_PATH"
+`
+	maestroIOSInstallScript = `udid=$(xcrun simctl list devices available -j | jq -r '[.devices[][] | select(.name | startswith("iPhone"))][0].udid')
+xcrun simctl boot "$udid"
+xcrun simctl install "$udid" "$BITRISE_APP_DIR_PATH"
+`
+	maestroTestCommand = `maestro test --format junit --output "$BITRISE_DEPLOY_DIR/maestro-report.xml" `
+)
+
+// detoxConfigFileNames are the Detox config files, looked for in the app directory (besides the detox field of package.json).
+var detoxConfigFileNames = []string{".detoxrc.js", ".detoxrc.cjs", ".detoxrc.json", ".detoxrc", "detox.config.js", "detox.config.cjs", "detox.config.json"}
+
+var (
+	// configurations: {
+	detoxJSConfigurationsPattern = regexp.MustCompile(`\bconfigurations\s*:\s*\{`)
+	// 'ios.sim.debug': / "android.emu.release": / ios:
+	detoxJSKeyPattern = regexp.MustCompile(`['"]?([\w.-]+)['"]?\s*:\s*$`)
+	// type: 'ios.simulator'
+	detoxJSTypePattern = regexp.MustCompile(`\btype\s*:\s*['"](ios|android)\.`)
+	// device: 'simulator'
+	detoxJSDeviceAliasPattern = regexp.MustCompile(`\bdevice\s*:\s*['"]([\w.-]+)['"]`)
+)
+
+// detoxConfiguration is a configuration of the Detox config, the app built for testing and the device running the tests.
+type detoxConfiguration struct {
+	name     string
+	platform string
+}
+
+// e2eTests describes the end-to-end test suites of a React Native app.
+type e2eTests struct {
+	detoxConfigurations []detoxConfiguration
+	// maestroFlowsDir is the directory of the Maestro flows relative to the app directory, empty if not found.
+	maestroFlowsDir string
+}
+
+// detectE2ETests returns the Detox configurations and the Maestro flows of the app in the projectDir.
+func detectE2ETests(projectDir string) (e2eTests, error) {
+	var tests e2eTests
+
+	configurations, err := detectDetoxConfigurations(projectDir)
+	if err != nil {
+		return e2eTests{}, err
+	}
+	tests.detoxConfigurations = configurations
+
+	flows, err := filepath.Glob(filepath.Join(projectDir, maestroFlowsDirName, "*.y*ml"))
+	if err != nil {
+		return e2eTests{}, err
+	}
+	if len(flows) > 0 {
+		tests.maestroFlowsDir = maestroFlowsDirName
+	}
+
+	return tests, nil
+}
+
+func (tests e2eTests) isDetected() bool {
+	return len(tests.detoxConfigurations) > 0 || tests.maestroFlowsDir != ""
+}
+
+// configName returns the name of the config, extended with the end-to-end tests if detected.
+func (tests e2eTests) configName(name string) string {
+	if !tests.isDetected() {
+		return name
+	}
+	return strings.TrimSuffix(name, "-config") + "-e2e-config"
+}
+
+func (tests e2eTests) String() string {
+	var details []string
+	for _, configuration := range tests.detoxConfigurations {
+		details = append(details, fmt.Sprintf("detox %s (%s)", configuration.name, configuration.platform))
+	}
+	if tests.maestroFlowsDir != "" {
+		details = append(details, "maestro flows: "+tests.maestroFlowsDir)
+	}
+	return strings.Join(details, ", ")
+}
+
+// detectDetoxConfigurations returns the configurations of the Detox config of the app, in alphabetical order.
+func detectDetoxConfigurations(projectDir string) ([]detoxConfiguration, error) {
+	for _, name := range detoxConfigFileNames {
+		pth := filepath.Join(projectDir, name)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return nil, err
+		} else if !exist {
+			continue
+		}
+
+		content, err := fileutil.ReadBytesFromFile(pth)
+		if err != nil {
+			return nil, err
+		}
+		if ext := filepath.Ext(name); ext == ".js" || ext == ".cjs" {
+			return parseDetoxJSConfig(string(content)), nil
+		}
+		return parseDetoxJSONConfig(content)
+	}
+
+	// the Detox config set in the detox field of package.json
+	content, err := fileutil.ReadBytesFromFile(filepath.Join(projectDir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var packageJSON struct {
+		Detox json.RawMessage `json:"detox"`
+	}
+	if err := json.Unmarshal(content, &packageJSON); err != nil {
+		return nil, err
+	}
+	if len(packageJSON.Detox) == 0 || packageJSON.Detox[0] != '{' {
+		return nil, nil
+	}
+	return parseDetoxJSONConfig(packageJSON.Detox)
+}
+
+func parseDetoxJSONConfig(content []byte) ([]detoxConfiguration, error) {
+	var config struct {
+		Devices        map[string]json.RawMessage `json:"devices"`
+		Configurations map[string]struct {
+			// type is set by the legacy configs
+			Type   string          `json:"type"`
+			Device json.RawMessage `json:"device"`
+		} `json:"configurations"`
+	}
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, err
+	}
+
+	// the device is an alias of the devices section, or an inline device config
+	deviceType := func(device json.RawMessage) string {
+		var alias string
+		if err := json.Unmarshal(device, &alias); err == nil {
+			device = config.Devices[alias]
+		}
+		var inline struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(device, &inline); err != nil {
+			return ""
+		}
+		return inline.Type
+	}
+
+	var configurations []detoxConfiguration
+	for name, configuration := range config.Configurations {
+		typ := configuration.Type
+		if typ == "" && len(configuration.Device) > 0 {
+			typ = deviceType(configuration.Device)
+		}
+		configurations = append(configurations, detoxConfiguration{name: name, platform: detoxPlatform(name, typ)})
+	}
+	sort.Slice(configurations, func(i, j int) bool {
+		return configurations[i].name < configurations[j].name
+	})
+	return configurations, nil
+}
+
+// parseDetoxJSConfig reads the configurations of a JavaScript Detox config, which are defined as an object literal.
+func parseDetoxJSConfig(content string) []detoxConfiguration {
+	loc := detoxJSConfigurationsPattern.FindStringIndex(content)
+	if loc == nil {
+		return nil
+	}
+
+	var configurations []detoxConfiguration
+	depth, key, start := 1, "", loc[1]
+	for i := loc[1]; i < len(content) && depth > 0; i++ {
+		switch content[i] {
+		case '{':
+			if depth == 1 {
+				if match := detoxJSKeyPattern.FindStringSubmatch(content[start:i]); match != nil {
+					key = match[1]
+				}
+				start = i
+			}
+			depth++
+		case '}':
+			depth--
+			if depth == 1 && key != "" {
+				body := content[start:i]
+				typ := ""
+				if match := detoxJSTypePattern.FindStringSubmatch(body); match != nil {
+					typ = match[1]
+				} else if match := detoxJSDeviceAliasPattern.FindStringSubmatch(body); match != nil {
+					typ = detoxJSDeviceType(content, match[1])
+				}
+				configurations = append(configurations, detoxConfiguration{name: key, platform: detoxPlatform(key, typ)})
+				key, start = "", i+1
+			}
+		case ',':
+			if depth == 1 {
+				start = i + 1
+			}
+		}
+	}
+
+	sort.Slice(configurations, func(i, j int) bool {
+		return configurations[i].name < configurations[j].name
+	})
+	return configurations
+}
+
+// detoxJSDeviceType returns the type of the device defined by the alias in the devices section of a JavaScript Detox config.
+func detoxJSDeviceType(content, alias string) string {
+	pattern := regexp.MustCompile(`['"]?\b` + regexp.QuoteMeta(alias) + `['"]?\s*:\s*\{[^}]*\btype\s*:\s*['"](ios|android)\.`)
+	if match := pattern.FindStringSubmatch(content); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// detoxPlatform returns the platform of the configuration by its device type (ios.simulator, android.emulator),
+// or by its name if the device type is unknown.
+func detoxPlatform(name, deviceType string) string {
+	for _, value := range []string{deviceType, strings.ToLower(name)} {
+		switch {
+		case strings.HasPrefix(value, iosPlatform):
+			return iosPlatform
+		case strings.HasPrefix(value, androidPlatform):
+			return androidPlatform
+		}
+	}
+	return ""
+}
+
+// appendWorkflow appends the workflow running the end-to-end tests of the app in the workdir.
+// The prepareNativeStepList generates the native projects (Expo prebuild) if required,
+// the Maestro flows run on the app built from the native projects of the nativePlatforms (Android preferred).
+func (tests e2eTests) appendWorkflow(configBuilder *models.ConfigBuilderModel, jsPackageManager utility.JSPackageManager, workdir string, installPods bool, prepareNativeStepList []bitriseModels.StepListItemModel, nativePlatforms []string, caches ...steps.DependencyCache) {
+	scriptStepListItem := func(title, script string) bitriseModels.StepListItemModel {
+		inputs := []envmanModels.EnvironmentItemModel{
+			{"content": "#!/usr/bin/env bash\nset -ex\n\n" + script},
+		}
+		if workdir != "" {
+			inputs = append(inputs, envmanModels.EnvironmentItemModel{"working_dir": workdir})
+		}
+		return steps.ScriptSteplistItem(title, inputs...)
+	}
+
+	configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+	configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, jsPackageManager.InstallStepListItem(workdir))
+	configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, prepareNativeStepList...)
+
+	// detox builds the app by the build command of the configuration
+	hasIOSSetup, hasEmulator := false, false
+	for _, configuration := range tests.detoxConfigurations {
+		buildScript := jsPackageManager.ExecCommand("detox build --configuration " + configuration.name)
+		testScript := jsPackageManager.ExecCommand("detox test --configuration " + configuration.name + " --cleanup --record-logs failing --artifacts-location \"$BITRISE_DEPLOY_DIR/detox\"")
+
+		switch configuration.platform {
+		case iosPlatform:
+			if !hasIOSSetup {
+				configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.ScriptSteplistItem(installAppleSimUtilsScriptTitle,
+					envmanModels.EnvironmentItemModel{"content": installAppleSimUtilsScript},
+				))
+				if installPods {
+					buildScript = "(cd ios && pod install)\n" + buildScript
+				}
+				hasIOSSetup = true
+			}
+		case androidPlatform:
+			testScript = strings.Replace(testScript, " --cleanup", " --headless --cleanup", 1)
+			if !hasEmulator {
+				// the emulator boots while the app is built
+				configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.AVDManagerStepListItem())
+			}
+		}
+
+		configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, scriptStepListItem(fmt.Sprintf(detoxBuildScriptTitleFormat, configuration.name), buildScript))
+		if configuration.platform == androidPlatform && !hasEmulator {
+			configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.WaitForAndroidEmulatorStepListItem())
+			hasEmulator = true
+		}
+		configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, scriptStepListItem(fmt.Sprintf(detoxTestScriptTitleFormat, configuration.name), testScript))
+	}
+
+	// maestro tests the app built from the native project
+	if tests.maestroFlowsDir != "" {
+		maestroScript := installMaestroScript
+		switch {
+		case sliceutil.IsStringInSlice(androidPlatform, nativePlatforms):
+			if !hasEmulator {
+				configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.AVDManagerStepListItem())
+			}
+			configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
+				envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: "$" + android.ProjectLocationInputEnvKey + "/gradlew"},
+			))
+			configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.AndroidBuildStepListItem(
+				envmanModels.EnvironmentItemModel{android.ProjectLocationInputKey: "$" + android.ProjectLocationInputEnvKey},
+				envmanModels.EnvironmentItemModel{android.ModuleInputKey: "$" + android.ModuleInputEnvKey},
+				envmanModels.EnvironmentItemModel{android.VariantInputKey: "$" + android.VariantInputEnvKey},
+			))
+			if !hasEmulator {
+				configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.WaitForAndroidEmulatorStepListItem())
+			}
+			maestroScript += maestroAndroidInstallScript
+		case sliceutil.IsStringInSlice(iosPlatform, nativePlatforms):
+			if installPods && !hasIOSSetup {
+				configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.CocoapodsInstallStepListItem())
+			}
+			configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.XcodeBuildForSimulatorStepListItem(
+				envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
+				envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
+				envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
+			))
+			maestroScript += maestroIOSInstallScript
+		default:
+			maestroScript = ""
+		}
+
+		if maestroScript != "" {
+			configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, scriptStepListItem(maestroTestScriptTitle, maestroScript+maestroTestCommand+tests.maestroFlowsDir))
+		}
+	}
+
+	configBuilder.AppendStepListItemsTo(E2ETestWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
+	configBuilder.SetWorkflowDescriptionTo(E2ETestWorkflowID, e2eTestWorkflowDescription)
+}
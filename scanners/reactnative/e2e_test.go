@@ -0,0 +1,183 @@
+package reactnative
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDetoxJSConfig = `/** @type {Detox.DetoxConfig} */
+module.exports = {
+  testRunner: {
+    args: { $0: 'jest', config: 'e2e/jest.config.js' },
+  },
+  apps: {
+    'ios.release': { type: 'ios.app', binaryPath: 'ios/build/Build/Products/Release-iphonesimulator/App.app' },
+    'android.release': { type: 'android.apk', binaryPath: 'android/app/build/outputs/apk/release/app-release.apk' },
+  },
+  devices: {
+    simulator: {
+      type: 'ios.simulator',
+      device: { type: 'iPhone 15' },
+    },
+    emulator: {
+      type: 'android.emulator',
+      device: { avdName: 'Pixel_API_34' },
+    },
+  },
+  configurations: {
+    'ios.sim.release': {
+      device: 'simulator',
+      app: 'ios.release',
+    },
+    "android.emu.release": {
+      device: "emulator",
+      app: "android.release",
+    },
+    attached: {
+      device: { type: 'android.attached', device: { adbName: '.*' } },
+      app: 'android.release',
+    },
+  },
+};
+`
+
+const testDetoxJSONConfig = `{
+  "devices": {
+    "simulator": { "type": "ios.simulator", "device": { "type": "iPhone 15" } }
+  },
+  "configurations": {
+    "ios.sim.debug": { "device": "simulator", "app": "ios.debug" },
+    "android.emu.debug": { "device": { "type": "android.emulator", "device": { "avdName": "Pixel" } }, "app": "android.debug" },
+    "legacy": { "type": "ios.simulator", "binaryPath": "App.app" }
+  }
+}`
+
+func TestDetectE2ETests(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  e2eTests
+	}{
+		{
+			name:  "no end-to-end tests",
+			files: map[string]string{"package.json": `{"name": "app"}`},
+			want:  e2eTests{},
+		},
+		{
+			name: "javascript detox config",
+			files: map[string]string{
+				"package.json": `{"name": "app"}`,
+				".detoxrc.js":  testDetoxJSConfig,
+			},
+			want: e2eTests{detoxConfigurations: []detoxConfiguration{
+				{name: "android.emu.release", platform: androidPlatform},
+				{name: "attached", platform: androidPlatform},
+				{name: "ios.sim.release", platform: iosPlatform},
+			}},
+		},
+		{
+			name: "json detox config",
+			files: map[string]string{
+				"package.json":      `{"name": "app"}`,
+				"detox.config.json": testDetoxJSONConfig,
+			},
+			want: e2eTests{detoxConfigurations: []detoxConfiguration{
+				{name: "android.emu.debug", platform: androidPlatform},
+				{name: "ios.sim.debug", platform: iosPlatform},
+				{name: "legacy", platform: iosPlatform},
+			}},
+		},
+		{
+			name: "detox config in package.json",
+			files: map[string]string{
+				"package.json": `{"name": "app", "detox": {"configurations": {"e2e": {"device": {"type": "ios.simulator"}}}}}`,
+			},
+			want: e2eTests{detoxConfigurations: []detoxConfiguration{
+				{name: "e2e", platform: iosPlatform},
+			}},
+		},
+		{
+			name: "detox config file path in package.json",
+			files: map[string]string{
+				"package.json": `{"name": "app", "detox": "e2e/detox.config.js"}`,
+			},
+			want: e2eTests{},
+		},
+		{
+			name: "maestro flows",
+			files: map[string]string{
+				"package.json":        `{"name": "app"}`,
+				".maestro/login.yaml": "appId: com.sample.app\n---\n- launchApp\n",
+			},
+			want: e2eTests{maestroFlowsDir: ".maestro"},
+		},
+		{
+			name: "maestro dir without flows",
+			files: map[string]string{
+				"package.json":       `{"name": "app"}`,
+				".maestro/README.md": "# Flows",
+			},
+			want: e2eTests{},
+		},
+		{
+			name: "detox and maestro",
+			files: map[string]string{
+				"package.json":      `{"name": "app"}`,
+				".detoxrc.json":     testDetoxJSONConfig,
+				".maestro/home.yml": "appId: com.sample.app\n---\n- launchApp\n",
+			},
+			want: e2eTests{
+				detoxConfigurations: []detoxConfiguration{
+					{name: "android.emu.debug", platform: androidPlatform},
+					{name: "ios.sim.debug", platform: iosPlatform},
+					{name: "legacy", platform: iosPlatform},
+				},
+				maestroFlowsDir: ".maestro",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			for name, content := range tt.files {
+				pth := filepath.Join(projectDir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+				require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+			}
+
+			got, err := detectE2ETests(projectDir)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, len(tt.want.detoxConfigurations) > 0 || tt.want.maestroFlowsDir != "", got.isDetected())
+		})
+	}
+}
+
+func TestDetoxPlatform(t *testing.T) {
+	tests := []struct {
+		name       string
+		deviceType string
+		want       string
+	}{
+		{name: "e2e", deviceType: "ios.simulator", want: iosPlatform},
+		{name: "e2e", deviceType: "android.attached", want: androidPlatform},
+		{name: "ios.sim.debug", deviceType: "", want: iosPlatform},
+		{name: "Android.Emu.Release", deviceType: "", want: androidPlatform},
+		{name: "ios.sim.debug", deviceType: "android.emulator", want: androidPlatform},
+		{name: "e2e", deviceType: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"-"+tt.deviceType, func(t *testing.T) {
+			require.Equal(t, tt.want, detoxPlatform(tt.name, tt.deviceType))
+		})
+	}
+}
+
+func TestE2ETestsConfigName(t *testing.T) {
+	require.Equal(t, "react-native-android-ios-test-config", e2eTests{}.configName("react-native-android-ios-test-config"))
+	require.Equal(t, "react-native-android-ios-test-e2e-config", e2eTests{maestroFlowsDir: ".maestro"}.configName("react-native-android-ios-test-config"))
+}
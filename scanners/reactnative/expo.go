@@ -11,6 +11,7 @@ import (
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
 	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/bitrise-io/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/log"
 	"github.com/pkg/errors"
@@ -19,13 +20,14 @@ import (
 
 const (
 	expoConfigName        = "react-native-expo-config"
+	expoEASConfigName     = "react-native-expo-eas-config"
 	expoDefaultConfigName = "default-" + expoConfigName
 )
 
 const (
-	bareIOSProjectPathInputTitle   = "The iOS project path generated by running 'expo eject' locally"
-	bareIOSprojectPathInputSummary = `Will add the Expo Eject Step to the Workflow to generate the native iOS project, so it can be built and archived.
-Run 'expo eject' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories. See https://docs.expo.io/bare/customizing/ for more details.
+	bareIOSProjectPathInputTitle   = "The iOS project path generated by running 'expo prebuild' locally"
+	bareIOSprojectPathInputSummary = `Will run 'expo prebuild' in the Workflow to generate the native iOS project, so it can be built and archived.
+Run 'npx expo prebuild' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories. See https://docs.expo.dev/workflow/prebuild/ for more details.
 For example: './ios/myproject.xcworkspace'.`
 )
 
@@ -33,13 +35,13 @@ const (
 	iosBundleIDInputTitle   = "iOS bundle identifier"
 	iosBundleIDInputSummary = `Key expo/ios/bundleIdentifier not present in 'app.json'.
 
-Will add the Expo Eject Step to the Workflow to generate the native iOS project, so the IPA can be exported.
-For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+Will run 'expo prebuild' in the Workflow to generate the native iOS project, so the IPA can be exported.
+For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
 For example: 'com.sample.myapp'.`
 	iosBundleIDInputSummaryDefault = `Optional, only needs to be entered if the key expo/ios/bundleIdentifier is not set in 'app.json'.
 
-Will add the Expo Eject Step to the Workflow to generate the native iOS project, so the IPA can be exported.
-For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+Will run 'expo prebuild' in the Workflow to generate the native iOS project, so the IPA can be exported.
+For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
 For example: 'com.sample.myapp'.`
 	iosBundleIDEnvKey = "EXPO_BARE_IOS_BUNLDE_ID"
 )
@@ -48,13 +50,13 @@ const (
 	androidPackageInputTitle   = "Android package name"
 	androidPackageInputSummary = `Key expo/android/package not present in 'app.json'.
 
-Will add the Expo Eject Step to the Workflow to generate the native Android project, so the bundle (AAB) can be built.
-For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+Will run 'expo prebuild' in the Workflow to generate the native Android project, so the bundle (AAB) can be built.
+For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
 For example: 'com.sample.myapp'.`
 	androidPackageInputSummaryDefault = `Optional, only needs to be entered if the key expo/android/package is not set in 'app.json'.
 
-Will add the Expo Eject Step to the Workflow to generate the native Android project, so the bundle (AAB) can be built.
-For your convenience, define it here temporarily. To set this value permanently run 'expo eject' in a local environment and commit 'app.json' changes.
+Will run 'expo prebuild' in the Workflow to generate the native Android project, so the bundle (AAB) can be built.
+For your convenience, define it here temporarily. To set this value permanently add it to 'app.json'.
 For example: 'com.sample.myapp'.`
 	androidPackageEnvKey = "EXPO_BARE_ANDROID_PACKAGE"
 )
@@ -63,32 +65,57 @@ const (
 	iosDevelopmentTeamInputTitle   = "iOS Development team ID"
 	iosDevelopmentTeamInputSummary = `The Apple Development Team that the iOS version of the app belongs to. Will be used to override code signing settings. See https://devcenter.bitrise.io/getting-started/getting-started-with-expo-apps/#signing-and-exporting-your-ios-app-for-deployment for more details.
 
-Will add the Expo Eject Step to the Workflow to generate the native iOS project, so it can be built and archived.
-Run 'expo eject' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories.
+Will run 'expo prebuild' in the Workflow to generate the native iOS project, so it can be built and archived.
+Run 'npx expo prebuild' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories.
 For example: '1MZX23ABCD4'.`
 	iosDevelopmentTeamEnv = "BITRISE_IOS_DEVELOPMENT_TEAM"
 )
 
 const (
 	projectRootDirInputTitle   = "Project root directory"
-	projectRootDirInputSummary = "The directory of the 'app.json' or 'package.json' file of your React Native project."
+	projectRootDirInputSummary = "The directory of the app config ('app.json', 'app.config.js' or 'app.config.ts') or 'package.json' file of your React Native project."
 )
 
 const (
 	schemeInputTitle   = "The iOS native project scheme name"
 	schemeInputSummary = `An Xcode scheme defines a collection of targets to build, a configuration to use when building, and a collection of tests to execute. You can change the scheme at any time.
 
-Will add the Expo Eject Step to the Workflow to generate the native iOS project, so it can be built and archived.
-Run 'expo eject' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories.`
+Will run 'expo prebuild' in the Workflow to generate the native iOS project, so it can be built and archived.
+Run 'npx expo prebuild' in a local environment to determine this value. This experiment then can be undone by deleting the ios and android directories.`
 )
 
 const wordirEnv = "WORKDIR"
 
 const (
-	expoBareAddIdentiferScriptTitle = "Set bundleIdentifier, packageName for Expo Eject"
-	expoAppJSONName                 = "app.json"
+	easBuildProfileInputTitle   = "EAS build profile"
+	easBuildProfileInputSummary = `The build profile of 'eas.json', which is used by EAS Build to build the app. You can change this at any time.
+
+The app is built on the Expo servers, the EXPO_TOKEN Secret has to be set to an Expo access token, see https://docs.expo.dev/accounts/programmatic-access/.`
+	easBuildProfileEnvKey = "EAS_BUILD_PROFILE"
+)
+
+const expoBareAddIdentiferScriptTitle = "Set bundleIdentifier, packageName for Expo prebuild"
+
+const (
+	expoPrebuildScriptTitle = "Generate the native projects with Expo prebuild"
+	expoPrebuildScript      = `#!/usr/bin/env bash
+set -ex
+
+npx expo prebuild`
 )
 
+const (
+	easBuildScriptTitle = "Build the app with EAS Build"
+	easBuildScript      = `#!/usr/bin/env bash
+set -ex
+
+npx eas-cli build --platform all --profile "$` + easBuildProfileEnvKey + `" --non-interactive --no-wait`
+)
+
+const easBuildWorkflowDescription = `Starts the build of the app on EAS Build, using the selected build profile of eas.json.
+
+Set the EXPO_TOKEN Secret to an Expo access token (https://docs.expo.dev/accounts/programmatic-access/) before running this workflow.`
+
 func expoBareAddIdentifiersScript(appJSONPath, androidEnvKey, iosEnvKey string) string {
 	return fmt.Sprintf(`#!/usr/bin/env bash
 set -ex
@@ -101,31 +128,55 @@ jq '.expo.android |= if has("package") or env.`+androidEnvKey+` == "" or env.`+a
 }
 
 // expoOptions implements ScannerInterface.Options function for Expo based React Native projects.
-func (scanner *Scanner) expoOptions() (models.OptionNode, models.Warnings, error) {
+func (scanner *Scanner) expoOptions(project project) (models.OptionNode, models.Warnings, error) {
 	warnings := models.Warnings{}
-	if scanner.expoSettings == nil {
+	if project.expoSettings == nil {
 		return models.OptionNode{}, warnings, errors.New("can not generate expo Options, expoSettings is nil")
 	}
-	if !scanner.expoSettings.isAndroid && !scanner.expoSettings.isIOS {
+	if !project.expoSettings.isAndroid && !project.expoSettings.isIOS {
 		return models.OptionNode{}, warnings, errors.New("can not generate expo Option, neither iOS or Android platform detected")
 	}
 
-	log.TPrintf("Project name: %v", scanner.expoSettings.name)
+	log.TPrintf("Project name: %v", project.expoSettings.name)
+	if project.expoSettings.isEASBuild() {
+		log.TPrintf("EAS build profiles: %v", project.expoSettings.easBuildProfiles)
+
+		easBuildProfileOption := models.NewOption(easBuildProfileInputTitle, easBuildProfileInputSummary, easBuildProfileEnvKey, models.TypeSelector)
+		for _, profile := range project.expoSettings.easBuildProfiles {
+			easBuildProfileOption.AddConfig(profile, models.NewConfigOption(project.expoConfigName(), nil))
+		}
+		return *easBuildProfileOption, warnings, nil
+	}
+
+	if !project.expoSettings.canSetIdentifiers() && !project.expoSettings.isAllIdentifierPresent() {
+		warnings = append(warnings, fmt.Sprintf("The iOS bundle identifier or the Android package name could not be read from %s. Make sure the app config sets them, as they are required by 'expo prebuild'.", filepath.Base(project.expoSettings.dynamicConfigPth)))
+	}
+
+	packageJSONDir := filepath.Dir(project.packageJSONPth)
+	relPackageJSONDir, err := utility.RelPath(scanner.searchDir, packageJSONDir)
+	if err != nil {
+		return models.OptionNode{}, warnings, fmt.Errorf("Failed to get relative package.json dir path, error: %s", err)
+	}
+	if relPackageJSONDir == "." {
+		// package.json placed in the search dir, no need to change-dir in the workflows
+		relPackageJSONDir = ""
+	}
+
 	var iosNode *models.OptionNode
 	var exportMethodOption *models.OptionNode
-	if scanner.expoSettings.isIOS { // ios options
+	if project.expoSettings.isIOS { // ios options
 		schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeOptionalSelector)
 
-		// predict the ejected project name
-		projectName := strings.ToLower(regexp.MustCompile(`(?i:[^a-z0-9])`).ReplaceAllString(scanner.expoSettings.name, ""))
+		// predict the name of the project generated by prebuild
+		projectName := strings.ToLower(regexp.MustCompile(`(?i:[^a-z0-9])`).ReplaceAllString(project.expoSettings.name, ""))
 		projectPathOption := models.NewOption(bareIOSProjectPathInputTitle, bareIOSprojectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeOptionalSelector)
 		if projectName != "" {
-			projectPathOption.AddOption(filepath.Join("./", "ios", projectName+".xcworkspace"), schemeOption)
+			projectPathOption.AddOption(filepath.Join(relPackageJSONDir, "ios", projectName+".xcworkspace"), schemeOption)
 		} else {
 			projectPathOption.AddOption("", schemeOption)
 		}
 
-		if scanner.expoSettings.bundleIdentifierIOS == "" { // bundle ID Option
+		if project.expoSettings.bundleIdentifierIOS == "" && project.expoSettings.canSetIdentifiers() { // bundle ID Option
 			iosNode = models.NewOption(iosBundleIDInputTitle, iosBundleIDInputSummary, iosBundleIDEnvKey, models.TypeUserInput)
 			iosNode.AddOption("", projectPathOption)
 		} else {
@@ -141,24 +192,20 @@ func (scanner *Scanner) expoOptions() (models.OptionNode, models.Warnings, error
 
 	var androidNode *models.OptionNode
 	var buildVariantOption *models.OptionNode
-	if scanner.expoSettings.isAndroid { // android options
-		packageJSONDir := filepath.Dir(scanner.packageJSONPth)
-		relPackageJSONDir, err := utility.RelPath(scanner.searchDir, packageJSONDir)
-		if err != nil {
-			return models.OptionNode{}, warnings, fmt.Errorf("Failed to get relative package.json dir path, error: %s", err)
-		}
-		if relPackageJSONDir == "." {
-			// package.json placed in the search dir, no need to change-dir in the workflows
-			relPackageJSONDir = ""
-		}
-
+	if project.expoSettings.isAndroid { // android options
 		var projectSettingNode *models.OptionNode
 		var moduleOption *models.OptionNode
-		if relPackageJSONDir == "" {
+		if relPackageJSONDir == "" || scanner.isMonorepo() {
+			// the project root directory of a monorepo app is selected by the parent option
+			androidDir := "./android"
+			if relPackageJSONDir != "" {
+				androidDir = filepath.Join(relPackageJSONDir, "android")
+			}
+
 			projectSettingNode = models.NewOption(android.ProjectLocationInputTitle, android.ProjectLocationInputSummary, android.ProjectLocationInputEnvKey, models.TypeSelector)
 
 			moduleOption = models.NewOption(android.ModuleInputTitle, android.ModuleInputSummary, android.ModuleInputEnvKey, models.TypeUserInput)
-			projectSettingNode.AddOption("./android", moduleOption)
+			projectSettingNode.AddOption(androidDir, moduleOption)
 		} else {
 			projectSettingNode = models.NewOption(projectRootDirInputTitle, projectRootDirInputSummary, wordirEnv, models.TypeSelector)
 
@@ -169,7 +216,7 @@ func (scanner *Scanner) expoOptions() (models.OptionNode, models.Warnings, error
 			projectLocationOption.AddOption(filepath.Join(relPackageJSONDir, "android"), moduleOption)
 		}
 
-		if scanner.expoSettings.packageNameAndroid == "" {
+		if project.expoSettings.packageNameAndroid == "" && project.expoSettings.canSetIdentifiers() {
 			androidNode = models.NewOption(androidPackageInputTitle, androidPackageInputSummary, androidPackageEnvKey, models.TypeUserInput)
 			androidNode.AddOption("", projectSettingNode)
 		} else {
@@ -195,91 +242,131 @@ func (scanner *Scanner) expoOptions() (models.OptionNode, models.Warnings, error
 		lastOption.ChildOptionMap = map[string]*models.OptionNode{}
 		if androidNode != nil {
 			// Android buildVariantOption is last
-			lastOption.AddConfig("Release", models.NewConfigOption(expoConfigName, nil))
+			lastOption.AddConfig("Release", models.NewConfigOption(project.expoConfigName(), nil))
 			continue
 		}
 
 		// iOS exportMethodOption is last
 		for _, exportMethod := range ios.IosExportMethods {
-			lastOption.AddConfig(exportMethod, models.NewConfigOption(expoConfigName, nil))
+			lastOption.AddConfig(exportMethod, models.NewConfigOption(project.expoConfigName(), nil))
 		}
 	}
 
 	return *rootNode, warnings, nil
 }
 
+// expoConfigName returns the name of the config generated for the Expo project.
+func (project project) expoConfigName() string {
+	if project.expoSettings.isEASBuild() {
+		return project.configName(expoEASConfigName)
+	}
+	return project.configName(expoConfigName)
+}
+
 // expoConfigs implements ScannerInterface.Configs function for Expo based React Native projects.
-func (scanner *Scanner) expoConfigs() (models.BitriseConfigMap, error) {
+func (scanner *Scanner) expoConfigs(project project, triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
 	configMap := models.BitriseConfigMap{}
 
 	// determine workdir
-	packageJSONDir := filepath.Dir(scanner.packageJSONPth)
-	relPackageJSONDir, err := utility.RelPath(scanner.searchDir, packageJSONDir)
+	packageJSONDir := filepath.Dir(project.packageJSONPth)
+	relPackageJSONDir, err := scanner.workdir(project)
 	if err != nil {
-		return models.BitriseConfigMap{}, fmt.Errorf("Failed to get relative package.json dir path, error: %s", err)
-	}
-	if relPackageJSONDir == "." {
-		// package.json placed in the search dir, no need to change-dir in the workflows
-		relPackageJSONDir = ""
+		return models.BitriseConfigMap{}, err
 	}
 	log.TPrintf("Working directory: %v", relPackageJSONDir)
 
-	workdirEnvList := []envmanModels.EnvironmentItemModel{}
-	if relPackageJSONDir != "" {
-		workdirEnvList = append(workdirEnvList, envmanModels.EnvironmentItemModel{workDirInputKey: relPackageJSONDir})
+	caches, err := dependencyCaches(scanner.searchDir, packageJSONDir, project.jsPackageManager, false)
+	if err != nil {
+		return models.BitriseConfigMap{}, fmt.Errorf("Failed to detect dependency caches, error: %s", err)
 	}
 
-	if !scanner.hasTest {
-		// if the project has no test script defined,
-		// we can only provide deploy like workflow,
-		// so that is going to be the primary workflow
-
-		configBuilder := models.NewDefaultConfigBuilder()
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
+	projectDir := relPackageJSONDir
+	if relPackageJSONDir == "" {
+		projectDir = "./"
+	}
 
-		if scanner.hasYarnLockFile {
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-		} else {
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-		}
+	configName := project.expoConfigName()
+	buildWorkflowDescription := deployWorkflowDescription
+	if project.expoSettings.isEASBuild() {
+		buildWorkflowDescription = easBuildWorkflowDescription
+	}
 
-		projectDir := relPackageJSONDir
-		if relPackageJSONDir == "" {
-			projectDir = "./"
-		}
+	// the steps generating the native projects
+	var prebuildStepList []bitriseModels.StepListItemModel
+	if !project.expoSettings.isEASBuild() && !project.expoSettings.isAllIdentifierPresent() && project.expoSettings.canSetIdentifiers() {
+		prebuildStepList = append(prebuildStepList, steps.ScriptSteplistItem(expoBareAddIdentiferScriptTitle,
+			envmanModels.EnvironmentItemModel{"content": expoBareAddIdentifiersScript(filepath.Join(projectDir, expoAppJSONName), androidPackageEnvKey, iosBundleIDEnvKey)},
+		))
+	}
+	prebuildStepList = append(prebuildStepList, steps.ScriptSteplistItem(expoPrebuildScriptTitle,
+		envmanModels.EnvironmentItemModel{"content": expoPrebuildScript},
+		envmanModels.EnvironmentItemModel{"working_dir": projectDir},
+	))
 
-		if !scanner.expoSettings.isAllIdentifierPresent() {
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ScriptSteplistItem(expoBareAddIdentiferScriptTitle,
-				envmanModels.EnvironmentItemModel{"content": expoBareAddIdentifiersScript(filepath.Join(projectDir, expoAppJSONName), androidPackageEnvKey, iosBundleIDEnvKey)},
+	// the steps building the app, on EAS Build or from the native projects generated by prebuild
+	appendBuildStepList := func(configBuilder *models.ConfigBuilderModel, workflowID models.WorkflowID) {
+		if project.expoSettings.isEASBuild() {
+			configBuilder.AppendStepListItemsTo(workflowID, steps.ScriptSteplistItem(easBuildScriptTitle,
+				envmanModels.EnvironmentItemModel{"content": easBuildScript},
+				envmanModels.EnvironmentItemModel{"working_dir": projectDir},
 			))
+			return
 		}
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ExpoDetachStepListItem(
-			envmanModels.EnvironmentItemModel{"project_path": projectDir},
-		))
+		configBuilder.AppendStepListItemsTo(workflowID, prebuildStepList...)
 
 		// android build
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
+		configBuilder.AppendStepListItemsTo(workflowID, steps.InstallMissingAndroidToolsStepListItem(
 			envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: "$" + android.ProjectLocationInputEnvKey + "/gradlew"},
 		))
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.AndroidBuildStepListItem(
+		configBuilder.AppendStepListItemsTo(workflowID, steps.AndroidBuildStepListItem(
 			envmanModels.EnvironmentItemModel{android.ProjectLocationInputKey: "$" + android.ProjectLocationInputEnvKey},
 			envmanModels.EnvironmentItemModel{android.ModuleInputKey: "$" + android.ModuleInputEnvKey},
 			envmanModels.EnvironmentItemModel{android.VariantInputKey: "$" + android.VariantInputEnvKey},
 		))
 
 		// ios build
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeArchiveStepListItem(
+		configBuilder.AppendStepListItemsTo(workflowID, steps.CertificateAndProfileInstallerStepListItem())
+		configBuilder.AppendStepListItemsTo(workflowID, steps.XcodeArchiveStepListItem(
 			envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
 			envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
 			envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
-			envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
+			envmanModels.EnvironmentItemModel{ios.DistributionMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
 			envmanModels.EnvironmentItemModel{"force_team_id": "$BITRISE_IOS_DEVELOPMENT_TEAM"},
 		))
+	}
+
+	// the end-to-end tests run on the app built from the native projects generated by prebuild
+	appendE2ETestWorkflow := func(configBuilder *models.ConfigBuilderModel) {
+		if !project.e2eTests.isDetected() {
+			return
+		}
+
+		// the native project options are not available for EAS Build
+		var nativePlatforms []string
+		if !project.expoSettings.isEASBuild() {
+			if project.expoSettings.isAndroid {
+				nativePlatforms = append(nativePlatforms, androidPlatform)
+			}
+			if project.expoSettings.isIOS {
+				nativePlatforms = append(nativePlatforms, iosPlatform)
+			}
+		}
+		project.e2eTests.appendWorkflow(configBuilder, project.jsPackageManager, relPackageJSONDir, false, prebuildStepList, nativePlatforms, caches...)
+	}
+
+	if !project.hasTest {
+		// if the project has no test script defined,
+		// we can only provide deploy like workflow,
+		// so that is going to be the primary workflow
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
-		configBuilder.SetWorkflowDescriptionTo(models.PrimaryWorkflowID, deployWorkflowDescription)
+		configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, project.jsPackageManager.InstallStepListItem(relPackageJSONDir))
+		appendBuildStepList(configBuilder, models.PrimaryWorkflowID)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
+		configBuilder.SetWorkflowDescriptionTo(models.PrimaryWorkflowID, buildWorkflowDescription)
+		appendE2ETestWorkflow(configBuilder)
 
 		bitriseDataModel, err := configBuilder.Generate(scannerName)
 		if err != nil {
@@ -291,68 +378,25 @@ func (scanner *Scanner) expoConfigs() (models.BitriseConfigMap, error) {
 			return models.BitriseConfigMap{}, err
 		}
 
-		configMap[expoConfigName] = string(data)
+		configMap[configName] = string(data)
 
 		return configMap, nil
 	}
 
 	// primary workflow
-	configBuilder := models.NewDefaultConfigBuilder()
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
-	if scanner.hasYarnLockFile {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "test"})...))
-	} else {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "test"})...))
-	}
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, project.jsPackageManager.InstallStepListItem(relPackageJSONDir))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, project.jsPackageManager.RunStepListItem("test", relPackageJSONDir))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 	// deploy workflow
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
-	if scanner.hasYarnLockFile {
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-	} else {
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-	}
-
-	projectDir := relPackageJSONDir
-	if relPackageJSONDir == "" {
-		projectDir = "./"
-	}
-
-	if !scanner.expoSettings.isAllIdentifierPresent() {
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ScriptSteplistItem(expoBareAddIdentiferScriptTitle,
-			envmanModels.EnvironmentItemModel{"content": expoBareAddIdentifiersScript(filepath.Join(projectDir, expoAppJSONName), androidPackageEnvKey, iosBundleIDEnvKey)},
-		))
-	}
-
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ExpoDetachStepListItem(
-		envmanModels.EnvironmentItemModel{"project_path": projectDir},
-	))
-
-	// android build
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
-		envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: "$" + android.ProjectLocationInputEnvKey + "/gradlew"},
-	))
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.AndroidBuildStepListItem(
-		envmanModels.EnvironmentItemModel{android.ProjectLocationInputKey: "$" + android.ProjectLocationInputEnvKey},
-		envmanModels.EnvironmentItemModel{android.ModuleInputKey: "$" + android.ModuleInputEnvKey},
-		envmanModels.EnvironmentItemModel{android.VariantInputKey: "$" + android.VariantInputEnvKey},
-	))
-
-	// ios build
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
-		envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
-		envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
-		envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
-		envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
-		envmanModels.EnvironmentItemModel{"force_team_id": "$BITRISE_IOS_DEVELOPMENT_TEAM"},
-	))
-
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
-	configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, project.jsPackageManager.InstallStepListItem(relPackageJSONDir))
+	appendBuildStepList(configBuilder, models.DeployWorkflowID)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
+	configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, buildWorkflowDescription)
+	appendE2ETestWorkflow(configBuilder)
 
 	bitriseDataModel, err := configBuilder.Generate(scannerName)
 	if err != nil {
@@ -364,7 +408,7 @@ func (scanner *Scanner) expoConfigs() (models.BitriseConfigMap, error) {
 		return models.BitriseConfigMap{}, err
 	}
 
-	configMap[expoConfigName] = string(data)
+	configMap[configName] = string(data)
 
 	return configMap, nil
 }
@@ -411,11 +455,11 @@ func (Scanner) expoDefaultOptions() models.OptionNode {
 }
 
 // expoDefaultConfigs implements ScannerInterface.DefaultConfigs function for Expo based React Native projects.
-func (Scanner) expoDefaultConfigs() (models.BitriseConfigMap, error) {
+func (Scanner) expoDefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
 	configMap := models.BitriseConfigMap{}
 
 	// primary workflow
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.YarnStepListItem(envmanModels.EnvironmentItemModel{workDirInputKey: "$WORKDIR"}, envmanModels.EnvironmentItemModel{"command": "install"}))
@@ -431,8 +475,9 @@ func (Scanner) expoDefaultConfigs() (models.BitriseConfigMap, error) {
 		envmanModels.EnvironmentItemModel{"content": expoBareAddIdentifiersScript(filepath.Join(".", expoAppJSONName), androidPackageEnvKey, iosBundleIDEnvKey)},
 	))
 
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ExpoDetachStepListItem(
-		envmanModels.EnvironmentItemModel{"project_path": "$WORKDIR"},
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ScriptSteplistItem(expoPrebuildScriptTitle,
+		envmanModels.EnvironmentItemModel{"content": expoPrebuildScript},
+		envmanModels.EnvironmentItemModel{"working_dir": "$WORKDIR"},
 	))
 
 	// android build
@@ -450,7 +495,7 @@ func (Scanner) expoDefaultConfigs() (models.BitriseConfigMap, error) {
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
 		envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
 		envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
-		envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
+		envmanModels.EnvironmentItemModel{ios.DistributionMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
 		envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
 	))
 
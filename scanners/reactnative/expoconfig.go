@@ -0,0 +1,118 @@
+package reactnative
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-xcode/xcodeproject/serialized"
+)
+
+const (
+	expoAppJSONName = "app.json"
+	easJSONName     = "eas.json"
+)
+
+// expoDynamicConfigNames are the JavaScript and TypeScript app configs, which take precedence over app.json.
+var expoDynamicConfigNames = []string{"app.config.ts", "app.config.js"}
+
+// findExpoDynamicConfig returns the path of the dynamic app config in the project dir, empty if not found.
+func findExpoDynamicConfig(projectDir string) (string, error) {
+	for _, name := range expoDynamicConfigNames {
+		pth := filepath.Join(projectDir, name)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return "", fmt.Errorf("failed to check if %s file (%s) exist: %s", name, pth, err)
+		} else if exist {
+			return pth, nil
+		}
+	}
+	return "", nil
+}
+
+// expoDynamicConfigValue returns the string literal assigned to the key in a dynamic app config,
+// empty if the value is computed (read from an env var, for example) or the key is assigned more than once
+// (by a plugin config or a conditional, for example), as the effective value can not be told.
+func expoDynamicConfigValue(content, key string) string {
+	// bundleIdentifier: 'com.sample.app' / "package": "com.sample.app" / name: `MyApp`
+	pattern := regexp.MustCompile(`["']?\b` + regexp.QuoteMeta(key) + `["']?\s*:\s*(?:'([^'\n]*)'|"([^"\n]*)"|` + "`([^`$\n]*)`" + `|\S)`)
+	matches := pattern.FindAllStringSubmatch(content, -1)
+	if len(matches) != 1 {
+		return ""
+	}
+	match := matches[0]
+	return match[1] + match[2] + match[3]
+}
+
+// expoDynamicConfigObject returns the content of the object literal assigned to the key in a dynamic app config,
+// like the ios: { ... } settings, empty if not found.
+func expoDynamicConfigObject(content, key string) string {
+	pattern := regexp.MustCompile(`["']?\b` + regexp.QuoteMeta(key) + `["']?\s*:\s*\{`)
+	loc := pattern.FindStringIndex(content)
+	if loc == nil {
+		return ""
+	}
+
+	depth := 0
+	for i := loc[1] - 1; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return content[loc[1]:i]
+			}
+		}
+	}
+	return content[loc[1]:]
+}
+
+// parseExpoDynamicConfig extracts the project name and the native identifiers, which are statically defined in the dynamic app config.
+// The native identifiers are looked up in the ios and android settings. The settings read from app.json are overridden.
+func parseExpoDynamicConfig(pth string, settings *expoSettings) error {
+	content, err := fileutil.ReadStringFromFile(pth)
+	if err != nil {
+		return err
+	}
+
+	settings.dynamicConfigPth = pth
+	if name := expoDynamicConfigValue(content, "name"); name != "" {
+		settings.name = name
+	}
+	if bundleID := expoDynamicConfigValue(expoDynamicConfigObject(content, "ios"), "bundleIdentifier"); bundleID != "" {
+		settings.bundleIdentifierIOS = bundleID
+	}
+	if packageName := expoDynamicConfigValue(expoDynamicConfigObject(content, "android"), "package"); packageName != "" {
+		settings.packageNameAndroid = packageName
+	}
+	return nil
+}
+
+// parseEASBuildProfiles returns the names of the build profiles defined in the eas.json file, in alphabetical order.
+func parseEASBuildProfiles(pth string) ([]string, error) {
+	content, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return nil, err
+	}
+
+	var eas serialized.Object
+	if err := json.Unmarshal(content, &eas); err != nil {
+		return nil, err
+	}
+
+	build, err := eas.Object("build")
+	if err != nil {
+		return nil, nil
+	}
+
+	var profiles []string
+	for profile := range build {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
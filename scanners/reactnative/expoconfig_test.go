@@ -0,0 +1,245 @@
+package reactnative
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testExpoDynamicConfig = `import { ExpoConfig } from 'expo/config';
+
+const config: ExpoConfig = {
+  name: "My App",
+  slug: 'my-app',
+  ios: {
+    bundleIdentifier: 'com.sample.app',
+  },
+  android: {
+    "package": ` + "`com.sample.app.android`" + `,
+  },
+};
+
+export default config;
+`
+
+func TestExpoDynamicConfigValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		want    string
+	}{
+		{
+			name:    "single quoted",
+			content: `ios: { bundleIdentifier: 'com.sample.app' }`,
+			key:     "bundleIdentifier",
+			want:    "com.sample.app",
+		},
+		{
+			name:    "double quoted key and value",
+			content: `"android": { "package": "com.sample.app" }`,
+			key:     "package",
+			want:    "com.sample.app",
+		},
+		{
+			name:    "template literal",
+			content: "name: `MyApp`,",
+			key:     "name",
+			want:    "MyApp",
+		},
+		{
+			name:    "interpolated template literal",
+			content: "name: `MyApp ${process.env.APP_VARIANT}`,",
+			key:     "name",
+			want:    "",
+		},
+		{
+			name:    "env var",
+			content: `bundleIdentifier: process.env.BUNDLE_ID,`,
+			key:     "bundleIdentifier",
+			want:    "",
+		},
+		{
+			name:    "key as a part of an other key",
+			content: `packageName: 'com.other', package: 'com.sample.app'`,
+			key:     "package",
+			want:    "com.sample.app",
+		},
+		{
+			name:    "assigned more than once",
+			content: `name: 'MyApp', plugins: [['expo-custom-plugin', { name: 'plugin' }]]`,
+			key:     "name",
+			want:    "",
+		},
+		{
+			name:    "assigned a literal and a computed value",
+			content: `name: IS_DEV ? process.env.DEV_NAME : 'MyApp'`,
+			key:     "name",
+			want:    "",
+		},
+		{
+			name:    "missing key",
+			content: `slug: 'my-app'`,
+			key:     "name",
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, expoDynamicConfigValue(tt.content, tt.key))
+		})
+	}
+}
+
+func TestExpoDynamicConfigObject(t *testing.T) {
+	require.Equal(t, `
+    bundleIdentifier: 'com.sample.app',
+  `, expoDynamicConfigObject(testExpoDynamicConfig, "ios"))
+	require.Equal(t, "", expoDynamicConfigObject(testExpoDynamicConfig, "web"))
+}
+
+func TestParseExpoDynamicConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		settings expoSettings
+		want     expoSettings
+	}{
+		{
+			name:     "overrides app.json",
+			content:  testExpoDynamicConfig,
+			settings: expoSettings{name: "app-json-name", isIOS: true, isAndroid: true, bundleIdentifierIOS: "com.app.json"},
+			want: expoSettings{
+				name:                "My App",
+				isIOS:               true,
+				isAndroid:           true,
+				bundleIdentifierIOS: "com.sample.app",
+				packageNameAndroid:  "com.sample.app.android",
+			},
+		},
+		{
+			name: "keeps app.json values of computed settings",
+			content: `export default ({ config }) => ({
+  ...config,
+  ios: { bundleIdentifier: process.env.BUNDLE_ID },
+});
+`,
+			settings: expoSettings{name: "app-json-name", isIOS: true, bundleIdentifierIOS: "com.app.json"},
+			want:     expoSettings{name: "app-json-name", isIOS: true, bundleIdentifierIOS: "com.app.json"},
+		},
+		{
+			name: "reads the identifiers from the platform settings",
+			content: `export default {
+  name: 'MyApp',
+  plugins: [['expo-share-extension', { bundleIdentifier: 'com.sample.app.share', package: 'com.sample.plugin' }]],
+  ios: { bundleIdentifier: 'com.sample.app' },
+  android: { package: 'com.sample.app' },
+};
+`,
+			settings: expoSettings{isIOS: true, isAndroid: true},
+			want:     expoSettings{name: "MyApp", isIOS: true, isAndroid: true, bundleIdentifierIOS: "com.sample.app", packageNameAndroid: "com.sample.app"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pth := filepath.Join(t.TempDir(), "app.config.ts")
+			require.NoError(t, ioutil.WriteFile(pth, []byte(tt.content), 0644))
+
+			settings := tt.settings
+			require.NoError(t, parseExpoDynamicConfig(pth, &settings))
+
+			tt.want.dynamicConfigPth = pth
+			require.Equal(t, tt.want, settings)
+		})
+	}
+}
+
+func TestFindExpoDynamicConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{
+			name:  "app.json only",
+			files: []string{"app.json"},
+			want:  "",
+		},
+		{
+			name:  "javascript config",
+			files: []string{"app.json", "app.config.js"},
+			want:  "app.config.js",
+		},
+		{
+			name:  "typescript config first",
+			files: []string{"app.config.js", "app.config.ts"},
+			want:  "app.config.ts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			for _, file := range tt.files {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(projectDir, file), []byte("{}"), 0644))
+			}
+
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(projectDir, tt.want)
+			}
+
+			got, err := findExpoDynamicConfig(projectDir)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestParseEASBuildProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "build profiles",
+			content: `{
+  "cli": { "version": ">= 5.0.0" },
+  "build": {
+    "production": {},
+    "development": { "developmentClient": true, "distribution": "internal" },
+    "preview": { "distribution": "internal" }
+  },
+  "submit": { "production": {} }
+}`,
+			want: []string{"development", "preview", "production"},
+		},
+		{
+			name:    "no build profiles",
+			content: `{"cli": {"version": ">= 5.0.0"}}`,
+			want:    nil,
+		},
+		{
+			name:    "invalid json",
+			content: `{"build": `,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pth := filepath.Join(t.TempDir(), easJSONName)
+			require.NoError(t, ioutil.WriteFile(pth, []byte(tt.content), 0644))
+
+			got, err := parseEASBuildProfiles(pth)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
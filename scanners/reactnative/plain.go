@@ -9,7 +9,6 @@ import (
 	"github.com/bitrise-io/bitrise-init/scanners/android"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
 	"github.com/bitrise-io/bitrise-init/steps"
-	"github.com/bitrise-io/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"gopkg.in/yaml.v2"
@@ -35,10 +34,10 @@ func configName(hasAndroidProject, hasIosProject, hasTest bool) string {
 }
 
 // options implements ScannerInterface.Options function for plain React Native projects.
-func (scanner *Scanner) options() (models.OptionNode, models.Warnings, error) {
+func (scanner *Scanner) options(project project) (models.OptionNode, models.Warnings, error) {
 	warnings := models.Warnings{}
 	var rootOption models.OptionNode
-	projectDir := filepath.Dir(scanner.packageJSONPth)
+	projectDir := filepath.Dir(project.packageJSONPth)
 
 	// android options
 	var androidOptions *models.OptionNode
@@ -46,14 +45,24 @@ func (scanner *Scanner) options() (models.OptionNode, models.Warnings, error) {
 	if exist, err := pathutil.IsDirExists(androidDir); err != nil {
 		return models.OptionNode{}, warnings, err
 	} else if exist {
-		if detected, err := scanner.androidScanner.DetectPlatform(scanner.searchDir); err != nil {
+		if detected, err := project.androidScanner.DetectPlatform(scanner.searchDir); err != nil {
 			return models.OptionNode{}, warnings, err
 		} else if detected {
-			// only the first match we need
-			scanner.androidScanner.ExcludeTest = true
-			scanner.androidScanner.ProjectRoots = []string{scanner.androidScanner.ProjectRoots[0]}
+			// only the first match we need, or the one of the app in a monorepo
+			project.androidScanner.ExcludeTest = true
+			absAndroidDir, err := pathutil.AbsPath(androidDir)
+			if err != nil {
+				return models.OptionNode{}, warnings, err
+			}
+			projectRoot := project.androidScanner.ProjectRoots[0]
+			for _, root := range project.androidScanner.ProjectRoots {
+				if scanner.isMonorepo() && root == absAndroidDir {
+					projectRoot = root
+				}
+			}
+			project.androidScanner.ProjectRoots = []string{projectRoot}
 
-			options, warns, _, err := scanner.androidScanner.Options()
+			options, warns, _, err := project.androidScanner.Options()
 			warnings = append(warnings, warns...)
 			if err != nil {
 				return models.OptionNode{}, warnings, err
@@ -69,16 +78,23 @@ func (scanner *Scanner) options() (models.OptionNode, models.Warnings, error) {
 	if exist, err := pathutil.IsDirExists(iosDir); err != nil {
 		return models.OptionNode{}, warnings, err
 	} else if exist {
-		if detected, err := scanner.iosScanner.DetectPlatform(scanner.searchDir); err != nil {
+		if detected, err := project.iosScanner.DetectPlatform(scanner.searchDir); err != nil {
 			return models.OptionNode{}, warnings, err
 		} else if detected {
-			scanner.iosScanner.SuppressPodFileParseError = true
-			options, warns, _, err := scanner.iosScanner.Options()
+			project.iosScanner.SuppressPodFileParseError = true
+			options, warns, _, err := project.iosScanner.Options()
 			warnings = append(warnings, warns...)
 			if err != nil {
 				return models.OptionNode{}, warnings, err
 			}
 
+			if scanner.isMonorepo() {
+				// the iOS scanner detects the projects of every app in the monorepo
+				if err := filterProjectPathOptions(&options, scanner.searchDir, projectDir); err != nil {
+					return models.OptionNode{}, warnings, err
+				}
+			}
+
 			iosOptions = &options
 		}
 	}
@@ -99,7 +115,7 @@ func (scanner *Scanner) options() (models.OptionNode, models.Warnings, error) {
 						return models.OptionNode{}, warnings, fmt.Errorf("no config for option: %s", child.String())
 					}
 
-					configName := configName(true, false, scanner.hasTest)
+					configName := project.configName(configName(true, false, project.hasTest))
 					child.Config = configName
 				}
 			}
@@ -121,7 +137,7 @@ func (scanner *Scanner) options() (models.OptionNode, models.Warnings, error) {
 					return models.OptionNode{}, warnings, fmt.Errorf("no config for option: %s", child.String())
 				}
 
-				configName := configName(scanner.androidScanner != nil, true, scanner.hasTest)
+				configName := project.configName(configName(project.androidScanner != nil, true, project.hasTest))
 				child.Config = configName
 			}
 		}
@@ -158,49 +174,55 @@ func (scanner *Scanner) defaultOptions() models.OptionNode {
 }
 
 // configs implements ScannerInterface.Configs function for plain React Native projects.
-func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
+func (scanner *Scanner) configs(project project, triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
 	configMap := models.BitriseConfigMap{}
 
-	packageJSONDir := filepath.Dir(scanner.packageJSONPth)
-	relPackageJSONDir, err := utility.RelPath(scanner.searchDir, packageJSONDir)
+	packageJSONDir := filepath.Dir(project.packageJSONPth)
+	relPackageJSONDir, err := scanner.workdir(project)
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+
+	caches, err := dependencyCaches(scanner.searchDir, packageJSONDir, project.jsPackageManager, true)
 	if err != nil {
-		return models.BitriseConfigMap{}, fmt.Errorf("Failed to get relative config.xml dir path, error: %s", err)
+		return models.BitriseConfigMap{}, fmt.Errorf("Failed to detect dependency caches, error: %s", err)
 	}
-	if relPackageJSONDir == "." {
-		// config.xml placed in the search dir, no need to change-dir in the workflows
-		relPackageJSONDir = ""
+
+	hasPodfile, err := pathutil.IsPathExists(filepath.Join(packageJSONDir, "ios", "Podfile"))
+	if err != nil {
+		return models.BitriseConfigMap{}, err
 	}
+	appendE2ETestWorkflow := func(configBuilder *models.ConfigBuilderModel) {
+		if !project.e2eTests.isDetected() {
+			return
+		}
 
-	workdirEnvList := []envmanModels.EnvironmentItemModel{}
-	if relPackageJSONDir != "" {
-		workdirEnvList = append(workdirEnvList, envmanModels.EnvironmentItemModel{workDirInputKey: relPackageJSONDir})
+		var nativePlatforms []string
+		if project.androidScanner != nil {
+			nativePlatforms = append(nativePlatforms, androidPlatform)
+		}
+		if project.iosScanner != nil {
+			nativePlatforms = append(nativePlatforms, iosPlatform)
+		}
+		project.e2eTests.appendWorkflow(configBuilder, project.jsPackageManager, relPackageJSONDir, hasPodfile, nil, nativePlatforms, caches...)
 	}
 
-	if scanner.hasTest {
-		configBuilder := models.NewDefaultConfigBuilder()
+	if project.hasTest {
+		configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 
 		// ci
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
-		if scanner.hasYarnLockFile {
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "test"})...))
-		} else {
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "test"})...))
-		}
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, project.jsPackageManager.InstallStepListItem(relPackageJSONDir))
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, project.jsPackageManager.RunStepListItem("test", relPackageJSONDir))
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 		// cd
 		configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
-		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
-		if scanner.hasYarnLockFile {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-		} else {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
-		}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, project.jsPackageManager.InstallStepListItem(relPackageJSONDir))
 
 		// android cd
-		if scanner.androidScanner != nil {
+		if project.androidScanner != nil {
 			projectLocationEnv := "$" + android.ProjectLocationInputEnvKey
 
 			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
@@ -211,9 +233,12 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 			))
 		}
 
+		// e2e tests
+		appendE2ETestWorkflow(configBuilder)
+
 		// ios cd
-		if scanner.iosScanner != nil {
-			for _, descriptor := range scanner.iosScanner.ConfigDescriptors {
+		if project.iosScanner != nil {
+			for _, descriptor := range project.iosScanner.ConfigDescriptors {
 				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 
 				if descriptor.MissingSharedSchemes {
@@ -235,11 +260,11 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
 					envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
 					envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
-					envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
+					envmanModels.EnvironmentItemModel{ios.DistributionMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
 					envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
 				))
 
-				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
+				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 				bitriseDataModel, err := configBuilder.Generate(scannerName)
 				if err != nil {
@@ -251,11 +276,11 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 					return models.BitriseConfigMap{}, err
 				}
 
-				configName := configName(scanner.androidScanner != nil, true, true)
+				configName := project.configName(configName(project.androidScanner != nil, true, true))
 				configMap[configName] = string(data)
 			}
 		} else {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 			bitriseDataModel, err := configBuilder.Generate(scannerName)
 			if err != nil {
@@ -267,17 +292,17 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 				return models.BitriseConfigMap{}, err
 			}
 
-			configName := configName(scanner.androidScanner != nil, false, true)
+			configName := project.configName(configName(project.androidScanner != nil, false, true))
 			configMap[configName] = string(data)
 		}
 	} else {
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 		configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, project.jsPackageManager.InstallStepListItem(relPackageJSONDir))
 
-		if scanner.androidScanner != nil {
+		if project.androidScanner != nil {
 			projectLocationEnv := "$" + android.ProjectLocationInputEnvKey
 
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
@@ -288,8 +313,10 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 			))
 		}
 
-		if scanner.iosScanner != nil {
-			for _, descriptor := range scanner.iosScanner.ConfigDescriptors {
+		appendE2ETestWorkflow(configBuilder)
+
+		if project.iosScanner != nil {
+			for _, descriptor := range project.iosScanner.ConfigDescriptors {
 				configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 
 				if descriptor.MissingSharedSchemes {
@@ -311,11 +338,11 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 				configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeArchiveStepListItem(
 					envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
 					envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
-					envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
+					envmanModels.EnvironmentItemModel{ios.DistributionMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
 					envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
 				))
 
-				configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+				configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 				bitriseDataModel, err := configBuilder.Generate(scannerName)
 				if err != nil {
@@ -327,11 +354,11 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 					return models.BitriseConfigMap{}, err
 				}
 
-				configName := configName(scanner.androidScanner != nil, true, false)
+				configName := project.configName(configName(project.androidScanner != nil, true, false))
 				configMap[configName] = string(data)
 			}
 		} else {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
 
 			bitriseDataModel, err := configBuilder.Generate(scannerName)
 			if err != nil {
@@ -343,7 +370,7 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 				return models.BitriseConfigMap{}, err
 			}
 
-			configName := configName(scanner.androidScanner != nil, false, false)
+			configName := project.configName(configName(project.androidScanner != nil, false, false))
 			configMap[configName] = string(data)
 		}
 	}
@@ -352,8 +379,8 @@ func (scanner *Scanner) configs() (models.BitriseConfigMap, error) {
 }
 
 // defaultConfigs implements ScannerInterface.DefaultConfigs function for plain React Native projects.
-func (scanner *Scanner) defaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func (scanner *Scanner) defaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 
 	// ci
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
@@ -381,7 +408,7 @@ func (scanner *Scanner) defaultConfigs() (models.BitriseConfigMap, error) {
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
 		envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
 		envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
-		envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
+		envmanModels.EnvironmentItemModel{ios.DistributionMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
 		envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
 	))
 
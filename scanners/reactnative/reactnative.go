@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/android"
@@ -12,6 +13,7 @@ import (
 	"github.com/bitrise-io/go-utils/fileutil"
 	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
 	"github.com/bitrise-io/go-xcode/xcodeproject/serialized"
 )
 
@@ -24,20 +26,44 @@ const (
 
 const (
 	isExpoCLIInputTitle   = "Was your React Native app created with the Expo CLI and using Managed Workflow?"
-	isExpoCLIInputSummary = "Will include *Expo Eject** Step if using Expo Managed Workflow (https://docs.expo.io/introduction/managed-vs-bare/). If ios/android native projects are present in the repository, choose No."
+	isExpoCLIInputSummary = "Will generate the native projects with Expo prebuild if using Expo Managed Workflow (https://docs.expo.dev/archive/managed-vs-bare/). If ios/android native projects are present in the repository, choose No."
 )
 
 // Scanner implements the project scanner for plain React Native and Expo based projects.
 type Scanner struct {
-	searchDir      string
+	searchDir string
+	// iosScanner and androidScanner detect the native projects of the package.json files,
+	// the options and configs of the apps are generated by the scanners of the projects.
 	iosScanner     *ios.Scanner
 	androidScanner *android.Scanner
 
-	hasTest         bool
-	hasYarnLockFile bool
-	packageJSONPth  string
+	// projects are the detected apps, more than one if the apps are the packages of a JavaScript monorepo.
+	projects []project
+}
+
+// project is a plain React Native or Expo based app.
+type project struct {
+	hasTest          bool
+	e2eTests         e2eTests
+	jsPackageManager utility.JSPackageManager
+	packageJSONPth   string
+	// appName identifies the app of a monorepo in the config names, empty if the app is not part of a monorepo.
+	appName string
 
 	expoSettings *expoSettings
+
+	iosScanner     *ios.Scanner
+	androidScanner *android.Scanner
+}
+
+// configName returns the name of the config generated for the project,
+// extended with the end-to-end tests and the app of the monorepo.
+func (project project) configName(name string) string {
+	name = project.e2eTests.configName(name)
+	if project.appName == "" {
+		return name
+	}
+	return strings.TrimSuffix(name, "-config") + "-" + project.appName + "-config"
 }
 
 // NewScanner creates a new scanner instance.
@@ -55,6 +81,10 @@ type expoSettings struct {
 	isIOS, isAndroid    bool
 	bundleIdentifierIOS string
 	packageNameAndroid  string
+	// dynamicConfigPth is the app.config.js or app.config.ts file, empty if the project is configured by app.json only.
+	dynamicConfigPth string
+	// easBuildProfiles are the build profiles defined in eas.json, the app is built by EAS Build if set.
+	easBuildProfiles []string
 }
 
 func (settings *expoSettings) isAllIdentifierPresent() bool {
@@ -62,6 +92,17 @@ func (settings *expoSettings) isAllIdentifierPresent() bool {
 		settings.isIOS && settings.bundleIdentifierIOS == "")
 }
 
+// canSetIdentifiers reports whether the missing native identifiers can be written to the app config.
+// The values of a dynamic app config can not be overridden.
+func (settings *expoSettings) canSetIdentifiers() bool {
+	return settings.dynamicConfigPth == ""
+}
+
+// isEASBuild reports whether the app is built by EAS Build, instead of building the native projects generated by prebuild.
+func (settings *expoSettings) isEASBuild() bool {
+	return len(settings.easBuildProfiles) > 0
+}
+
 // parseExpoProjectSettings reports whether a project is Expo based and it's settings, like targeted platforms
 func parseExpoProjectSettings(packageJSONPth string) (*expoSettings, error) {
 	packages, err := utility.ParsePackagesJSON(packageJSONPth)
@@ -73,59 +114,85 @@ func parseExpoProjectSettings(packageJSONPth string) (*expoSettings, error) {
 		return nil, nil
 	}
 
-	// app.json file is a required part of an expo projects and should be placed next to the root package.json file
-	appJSONPth := filepath.Join(filepath.Dir(packageJSONPth), "app.json")
+	// the app config (app.json, app.config.js or app.config.ts) is a required part of an expo projects and should be placed next to the root package.json file
+	projectDir := filepath.Dir(packageJSONPth)
+	dynamicConfigPth, err := findExpoDynamicConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	appJSONPth := filepath.Join(projectDir, expoAppJSONName)
 	exist, err := pathutil.IsPathExists(appJSONPth)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if app.json file (%s) exist: %s", appJSONPth, err)
 	}
-	if !exist {
+	if !exist && dynamicConfigPth == "" {
 		return nil, nil
 	}
 
-	appJSON, err := fileutil.ReadStringFromFile(appJSONPth)
-	if err != nil {
-		return nil, err
-	}
-	var app serialized.Object
-	if err := json.Unmarshal([]byte(appJSON), &app); err != nil {
-		return nil, err
+	// expo/ios and expo/android entry is optional
+	settings := &expoSettings{
+		isIOS:     true,
+		isAndroid: true,
 	}
 
-	expoObj, err := app.Object("expo")
-	if err != nil {
-		log.Warnf("%s", fmt.Errorf("app.json file (%s) has no 'expo' entry, not an Expo project", appJSONPth))
-		return nil, nil
-	}
-	projectName, err := expoObj.String("name")
-	if err != nil || projectName == "" {
-		log.Debugf("%s", fmt.Errorf("app.json file (%s) has no 'expo/name' entry, can not guess iOS project path, will ask for it during project configuration", appJSONPth))
-	}
-	iosObj, err := expoObj.Object("ios")
-	if err != nil {
-		log.TDebugf("%s", fmt.Errorf("app.json file (%s) has no no 'expo/ios entry', assuming iOS is targeted by Expo", appJSONPth))
-	}
-	bundleID, err := iosObj.String("bundleIdentifier")
-	if err != nil || bundleID == "" {
-		log.TDebugf("%s", fmt.Errorf("app.json file (%s) has no no 'expo/ios/bundleIdentifier' entry, will ask for it during project configuration", appJSONPth))
+	if exist {
+		appJSON, err := fileutil.ReadStringFromFile(appJSONPth)
+		if err != nil {
+			return nil, err
+		}
+		var app serialized.Object
+		if err := json.Unmarshal([]byte(appJSON), &app); err != nil {
+			return nil, err
+		}
+
+		expoObj, err := app.Object("expo")
+		if err != nil && dynamicConfigPth == "" {
+			log.Warnf("%s", fmt.Errorf("app.json file (%s) has no 'expo' entry, not an Expo project", appJSONPth))
+			return nil, nil
+		}
+		projectName, err := expoObj.String("name")
+		if err != nil || projectName == "" {
+			log.Debugf("%s", fmt.Errorf("app.json file (%s) has no 'expo/name' entry, can not guess iOS project path, will ask for it during project configuration", appJSONPth))
+		}
+		iosObj, err := expoObj.Object("ios")
+		if err != nil {
+			log.TDebugf("%s", fmt.Errorf("app.json file (%s) has no no 'expo/ios entry', assuming iOS is targeted by Expo", appJSONPth))
+		}
+		bundleID, err := iosObj.String("bundleIdentifier")
+		if err != nil || bundleID == "" {
+			log.TDebugf("%s", fmt.Errorf("app.json file (%s) has no no 'expo/ios/bundleIdentifier' entry, will ask for it during project configuration", appJSONPth))
+		}
+		androidObj, err := expoObj.Object("android")
+		if err != nil {
+			log.TDebugf("%s", fmt.Errorf("app.json file (%s) has no 'expo/android' entry, assuming Android is targeted by Expo", appJSONPth))
+		}
+		packageName, err := androidObj.String("package")
+		if err != nil || packageName == "" {
+			log.TDebugf("%s", fmt.Errorf("app.json file (%s) has no no 'expo/android/package' entry, will ask for it during project configuration", appJSONPth))
+		}
+
+		settings.name = projectName
+		settings.bundleIdentifierIOS = bundleID
+		settings.packageNameAndroid = packageName
 	}
-	androidObj, err := expoObj.Object("android")
-	if err != nil {
-		log.TDebugf("%s", fmt.Errorf("app.json file (%s) has no 'expo/android' entry, assuming Android is targeted by Expo", appJSONPth))
+
+	if dynamicConfigPth != "" {
+		if err := parseExpoDynamicConfig(dynamicConfigPth, settings); err != nil {
+			return nil, fmt.Errorf("failed to read app config (%s): %s", dynamicConfigPth, err)
+		}
 	}
-	packageName, err := androidObj.String("package")
-	if err != nil || packageName == "" {
-		log.TDebugf("%s", fmt.Errorf("app.json file (%s) has no no 'expo/android/package' entry, will ask for it during project configuration", appJSONPth))
+
+	easJSONPth := filepath.Join(projectDir, easJSONName)
+	if exist, err := pathutil.IsPathExists(easJSONPth); err != nil {
+		return nil, fmt.Errorf("failed to check if eas.json file (%s) exist: %s", easJSONPth, err)
+	} else if exist {
+		if settings.easBuildProfiles, err = parseEASBuildProfiles(easJSONPth); err != nil {
+			log.TWarnf("Failed to read the build profiles of eas.json file (%s): %s", easJSONPth, err)
+		}
 	}
 
-	// expo/ios and expo/android entry is optional
-	return &expoSettings{
-		name:                projectName,
-		isIOS:               true,
-		isAndroid:           true,
-		packageNameAndroid:  packageName,
-		bundleIdentifierIOS: bundleID,
-	}, nil
+	return settings, nil
 }
 
 // hasNativeProjects reports whether the project directory contains ios and android native project.
@@ -176,9 +243,7 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	log.TPrintf("%d package.json file detected", len(packageJSONPths))
 	log.TPrintf("Filter relevant package.json files")
 
-	var expoSettings *expoSettings
-	var packageFile string
-
+	var candidates []project
 	for _, packageJSONPth := range packageJSONPths {
 		log.TPrintf("Checking: %s", packageJSONPth)
 
@@ -212,61 +277,160 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 
 		if expoPrefs != nil {
 			if !(ios || android) {
-				expoSettings = expoPrefs
-				packageFile = packageJSONPth
-				break
+				candidates = append(candidates, project{packageJSONPth: packageJSONPth, expoSettings: expoPrefs})
+				continue
 			}
-			log.TPrintf("Native ios/android project present, expo eject step will not be included.")
+			log.TPrintf("Native ios/android project present, expo prebuild step will not be included.")
 		}
 
 		if ios || android {
-			packageFile = packageJSONPth
-			break
+			candidates = append(candidates, project{packageJSONPth: packageJSONPth})
 		}
 	}
 
-	if packageFile == "" {
+	if len(candidates) == 0 {
 		return false, nil
 	}
 
-	scanner.expoSettings = expoSettings
-	scanner.packageJSONPth = packageFile
+	// every app of a monorepo is offered, otherwise the first app is used
+	scanner.projects = candidates[:1]
+	if packageDirs, err := workspacePackageDirs(searchDir); err != nil {
+		log.TWarnf("Failed to read the workspaces of the monorepo: %s", err)
+	} else if len(packageDirs) > 0 {
+		var workspaceProjects []project
+		for _, candidate := range candidates {
+			projectDir, err := pathutil.AbsPath(filepath.Dir(candidate.packageJSONPth))
+			if err != nil {
+				return false, err
+			}
+			if sliceutil.IsStringInSlice(projectDir, packageDirs) {
+				workspaceProjects = append(workspaceProjects, candidate)
+			}
+		}
+		if len(workspaceProjects) > 1 {
+			log.TPrintf("%d apps found in the monorepo workspaces", len(workspaceProjects))
+			for i, workspaceProject := range workspaceProjects {
+				relProjectDir, err := utility.RelPath(searchDir, filepath.Dir(workspaceProject.packageJSONPth))
+				if err != nil {
+					return false, err
+				}
+				workspaceProjects[i].appName = strings.Replace(filepath.ToSlash(relProjectDir), "/", "-", -1)
+			}
+			scanner.projects = workspaceProjects
+		}
+	}
+
+	for i := range scanner.projects {
+		project := &scanner.projects[i]
 
-	// determine Js dependency manager
-	if scanner.hasYarnLockFile, err = containsYarnLock(filepath.Dir(scanner.packageJSONPth)); err != nil {
-		return false, err
+		// the native scanners of the apps keep the state of their own app
+		project.iosScanner = ios.NewScanner()
+		project.iosScanner.ExcludeAppIcon = true
+		project.androidScanner = android.NewScanner()
+		project.androidScanner.ExcludeAppIcon = true
+
+		// determine Js dependency manager
+		if project.jsPackageManager, err = utility.DetectJSPackageManager(searchDir, filepath.Dir(project.packageJSONPth)); err != nil {
+			return false, err
+		}
+		log.TPrintf("Js dependency manager for %s: %s", project.packageJSONPth, project.jsPackageManager)
+
+		packages, err := utility.ParsePackagesJSON(project.packageJSONPth)
+		if err != nil {
+			return false, err
+		}
+
+		if _, found := packages.Scripts["test"]; found {
+			project.hasTest = true
+		}
+		log.TPrintf("Test script found in package.json: %v", project.hasTest)
+
+		if project.e2eTests, err = detectE2ETests(filepath.Dir(project.packageJSONPth)); err != nil {
+			log.TWarnf("Failed to detect end-to-end tests: %s", err)
+		} else if project.e2eTests.isDetected() {
+			log.TPrintf("End-to-end tests found: %s", project.e2eTests)
+		}
+	}
+
+	return true, nil
+}
+
+// isMonorepo reports whether several apps of a JavaScript monorepo are detected,
+// the app is selected by the project root directory option.
+func (scanner *Scanner) isMonorepo() bool {
+	return len(scanner.projects) > 1
+}
+
+// workdir returns the directory of the app relative to the search dir, used by the generated steps,
+// the project root directory env var in case of a monorepo.
+func (scanner *Scanner) workdir(project project) (string, error) {
+	if scanner.isMonorepo() {
+		return "$" + wordirEnv, nil
 	}
-	log.TPrintf("Js dependency manager for %s is yarn: %t", scanner.packageJSONPth, scanner.hasYarnLockFile)
 
-	packages, err := utility.ParsePackagesJSON(scanner.packageJSONPth)
+	relPackageJSONDir, err := utility.RelPath(scanner.searchDir, filepath.Dir(project.packageJSONPth))
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("Failed to get relative package.json dir path, error: %s", err)
+	}
+	if relPackageJSONDir == "." {
+		// package.json placed in the search dir, no need to change-dir in the workflows
+		relPackageJSONDir = ""
 	}
+	return relPackageJSONDir, nil
+}
 
-	if _, found := packages.Scripts["test"]; found {
-		scanner.hasTest = true
+// Options implements ScannerInterface.Options function.
+func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	if !scanner.isMonorepo() {
+		options, warnings, err := scanner.projectOptions(scanner.projects[0])
+		return options, warnings, nil, err
 	}
-	log.TPrintf("Test script found in package.json: %v", scanner.hasTest)
 
-	return true, nil
+	warnings := models.Warnings{}
+	projectRootDirOption := models.NewOption(projectRootDirInputTitle, projectRootDirInputSummary, wordirEnv, models.TypeSelector)
+	for _, project := range scanner.projects {
+		relPackageJSONDir, err := utility.RelPath(scanner.searchDir, filepath.Dir(project.packageJSONPth))
+		if err != nil {
+			return models.OptionNode{}, warnings, nil, err
+		}
+
+		options, warns, err := scanner.projectOptions(project)
+		warnings = append(warnings, warns...)
+		if err != nil {
+			return models.OptionNode{}, warnings, nil, err
+		}
+		projectRootDirOption.AddOption(relPackageJSONDir, &options)
+	}
+	return *projectRootDirOption, warnings, nil, nil
 }
 
-// Options implements ScannerInterface.Options function.
-func (scanner *Scanner) Options() (options models.OptionNode, warnings models.Warnings, icons models.Icons, err error) {
-	if scanner.expoSettings != nil {
-		options, warnings, err = scanner.expoOptions()
-	} else {
-		options, warnings, err = scanner.options()
+func (scanner *Scanner) projectOptions(project project) (models.OptionNode, models.Warnings, error) {
+	if project.expoSettings != nil {
+		return scanner.expoOptions(project)
 	}
-	return
+	return scanner.options(project)
 }
 
 // Configs implements ScannerInterface.Configs function.
-func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	if scanner.expoSettings != nil {
-		return scanner.expoConfigs()
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configMap := models.BitriseConfigMap{}
+	for _, project := range scanner.projects {
+		var configs models.BitriseConfigMap
+		var err error
+		if project.expoSettings != nil {
+			configs, err = scanner.expoConfigs(project, triggerPreset)
+		} else {
+			configs, err = scanner.configs(project, triggerPreset)
+		}
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+
+		for name, config := range configs {
+			configMap[name] = config
+		}
 	}
-	return scanner.configs()
+	return configMap, nil
 }
 
 // DefaultOptions implements ScannerInterface.DefaultOptions function.
@@ -283,10 +447,10 @@ func (scanner *Scanner) DefaultOptions() models.OptionNode {
 }
 
 // DefaultConfigs implements ScannerInterface.DefaultConfigs function.
-func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
+func (scanner *Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
 	configMap := models.BitriseConfigMap{}
 
-	configs, err := scanner.defaultConfigs()
+	configs, err := scanner.defaultConfigs(triggerPreset)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
@@ -295,7 +459,7 @@ func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		configMap[k] = v
 	}
 
-	expoConfigs, err := scanner.expoDefaultConfigs()
+	expoConfigs, err := scanner.expoDefaultConfigs(triggerPreset)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
@@ -1,9 +1,9 @@
 package reactnative
 
 import (
-	"fmt"
 	"path/filepath"
 
+	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/bitrise-io/bitrise-init/utility"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
@@ -40,11 +40,30 @@ func CollectPackageJSONFiles(searchDir string) ([]string, error) {
 	return relevantPackageFileList, nil
 }
 
-func containsYarnLock(absPackageJSONDir string) (bool, error) {
-	if exist, err := pathutil.IsPathExists(filepath.Join(absPackageJSONDir, "yarn.lock")); err != nil {
-		return false, fmt.Errorf("Failed to check if yarn.lock file exists in the workdir: %s", err)
-	} else if exist {
-		return true, nil
+// dependencyCaches returns the key-based dependency caches of the project:
+// the js dependencies (installed in the workspace root if the project is part of a monorepo),
+// and the native dependencies if the native projects are part of the repository.
+func dependencyCaches(searchDir, packageJSONDir string, jsPackageManager utility.JSPackageManager, hasNativeProjects bool) ([]steps.DependencyCache, error) {
+	jsProjectDirs := []string{packageJSONDir}
+	if jsPackageManager.InstallDir != "" {
+		jsProjectDirs = append(jsProjectDirs, jsPackageManager.InstallDir)
 	}
-	return false, nil
+	caches, err := utility.DetectDependencyCaches(searchDir, jsProjectDirs, utility.JSDependencyManagers...)
+	if err != nil {
+		return nil, err
+	}
+	if !hasNativeProjects {
+		return caches, nil
+	}
+
+	androidCaches, err := utility.DetectDependencyCaches(searchDir, []string{filepath.Join(packageJSONDir, "android")}, utility.GradleDependencyManager)
+	if err != nil {
+		return nil, err
+	}
+	iosCaches, err := utility.DetectDependencyCaches(searchDir, []string{filepath.Join(packageJSONDir, "ios")}, utility.CocoaPodsDependencyManager)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(caches, androidCaches...), iosCaches...), nil
 }
@@ -0,0 +1,177 @@
+package reactnative
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	pnpmWorkspaceYamlName = "pnpm-workspace.yaml"
+	nxJSONName            = "nx.json"
+	nxDefaultAppsDir      = "apps"
+)
+
+// packageJSONWorkspaces is the workspaces field of a package.json, used by npm, Yarn and Bun:
+// a list of package patterns, or an object with the list (Yarn classic).
+type packageJSONWorkspaces struct {
+	Patterns []string
+}
+
+// UnmarshalJSON ...
+func (workspaces *packageJSONWorkspaces) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &workspaces.Patterns); err == nil {
+		return nil
+	}
+
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	workspaces.Patterns = object.Packages
+	return nil
+}
+
+// workspacePackagePatterns returns the package patterns of the JavaScript monorepo in the searchDir,
+// read from the workspaces field of package.json, pnpm-workspace.yaml or nx.json (in this order).
+func workspacePackagePatterns(searchDir string) ([]string, error) {
+	packageJSONPth := filepath.Join(searchDir, "package.json")
+	if exist, err := pathutil.IsPathExists(packageJSONPth); err != nil {
+		return nil, err
+	} else if exist {
+		content, err := fileutil.ReadBytesFromFile(packageJSONPth)
+		if err != nil {
+			return nil, err
+		}
+		var packageJSON struct {
+			Workspaces packageJSONWorkspaces `json:"workspaces"`
+		}
+		if err := json.Unmarshal(content, &packageJSON); err != nil {
+			return nil, err
+		}
+		if len(packageJSON.Workspaces.Patterns) > 0 {
+			return packageJSON.Workspaces.Patterns, nil
+		}
+	}
+
+	pnpmWorkspacePth := filepath.Join(searchDir, pnpmWorkspaceYamlName)
+	if exist, err := pathutil.IsPathExists(pnpmWorkspacePth); err != nil {
+		return nil, err
+	} else if exist {
+		content, err := fileutil.ReadBytesFromFile(pnpmWorkspacePth)
+		if err != nil {
+			return nil, err
+		}
+		var pnpmWorkspace struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal(content, &pnpmWorkspace); err != nil {
+			return nil, err
+		}
+		if len(pnpmWorkspace.Packages) > 0 {
+			return pnpmWorkspace.Packages, nil
+		}
+	}
+
+	nxJSONPth := filepath.Join(searchDir, nxJSONName)
+	if exist, err := pathutil.IsPathExists(nxJSONPth); err != nil {
+		return nil, err
+	} else if exist {
+		content, err := fileutil.ReadBytesFromFile(nxJSONPth)
+		if err != nil {
+			return nil, err
+		}
+		var nx struct {
+			WorkspaceLayout struct {
+				AppsDir string `json:"appsDir"`
+			} `json:"workspaceLayout"`
+		}
+		if err := json.Unmarshal(content, &nx); err != nil {
+			return nil, err
+		}
+		appsDir := nx.WorkspaceLayout.AppsDir
+		if appsDir == "" {
+			appsDir = nxDefaultAppsDir
+		}
+		return []string{appsDir + "/*"}, nil
+	}
+
+	return nil, nil
+}
+
+// workspacePackageDirs returns the absolute paths of the package directories of the JavaScript monorepo in the searchDir,
+// nil if the searchDir is not a monorepo.
+func workspacePackageDirs(searchDir string) ([]string, error) {
+	patterns, err := workspacePackagePatterns(searchDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var included, excluded []string
+	for _, pattern := range patterns {
+		list := &included
+		if strings.HasPrefix(pattern, "!") {
+			list = &excluded
+			pattern = strings.TrimPrefix(pattern, "!")
+		}
+
+		// packages/** is matched as the direct children of packages
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+		pattern = strings.Replace(pattern, "**", "*", -1)
+
+		matches, err := filepath.Glob(filepath.Join(searchDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			absMatch, err := pathutil.AbsPath(match)
+			if err != nil {
+				return nil, err
+			}
+			*list = append(*list, absMatch)
+		}
+	}
+
+	var dirs []string
+	for _, dir := range included {
+		isExcluded := false
+		for _, excludedDir := range excluded {
+			isExcluded = isExcluded || excludedDir == dir
+		}
+		if !isExcluded {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// filterProjectPathOptions removes the native project paths of the options, which are not in the projectDir.
+// It returns an error if no project path is left.
+func filterProjectPathOptions(options *models.OptionNode, searchDir, projectDir string) error {
+	absProjectDir, err := pathutil.AbsPath(projectDir)
+	if err != nil {
+		return err
+	}
+
+	for pth := range options.ChildOptionMap {
+		absPth := pth
+		if !filepath.IsAbs(absPth) {
+			absPth = filepath.Join(searchDir, pth)
+		}
+		if !strings.HasPrefix(absPth, absProjectDir+string(filepath.Separator)) {
+			delete(options.ChildOptionMap, pth)
+		}
+	}
+	if len(options.ChildOptionMap) == 0 {
+		return fmt.Errorf("no native project found in %s", projectDir)
+	}
+	return nil
+}
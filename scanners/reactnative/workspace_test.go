@@ -0,0 +1,214 @@
+package reactnative
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspacePackagePatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "not a monorepo",
+			files: map[string]string{"package.json": `{"name": "app"}`},
+			want:  nil,
+		},
+		{
+			name:  "npm, Yarn and Bun workspaces",
+			files: map[string]string{"package.json": `{"private": true, "workspaces": ["apps/*", "packages/*"]}`},
+			want:  []string{"apps/*", "packages/*"},
+		},
+		{
+			name:  "Yarn classic workspaces object",
+			files: map[string]string{"package.json": `{"workspaces": {"packages": ["apps/*"], "nohoist": ["**/react-native"]}}`},
+			want:  []string{"apps/*"},
+		},
+		{
+			name: "pnpm workspace",
+			files: map[string]string{
+				"package.json":        `{"name": "monorepo"}`,
+				"pnpm-workspace.yaml": "packages:\n  - 'apps/*'\n  - '!apps/legacy'\n",
+			},
+			want: []string{"apps/*", "!apps/legacy"},
+		},
+		{
+			name: "package.json workspaces before pnpm workspace",
+			files: map[string]string{
+				"package.json":        `{"workspaces": ["mobile/*"]}`,
+				"pnpm-workspace.yaml": "packages:\n  - 'apps/*'\n",
+			},
+			want: []string{"mobile/*"},
+		},
+		{
+			name: "Nx default apps dir",
+			files: map[string]string{
+				"package.json": `{"name": "monorepo"}`,
+				"nx.json":      `{"npmScope": "sample"}`,
+			},
+			want: []string{"apps/*"},
+		},
+		{
+			name:  "Nx custom apps dir",
+			files: map[string]string{"nx.json": `{"workspaceLayout": {"appsDir": "projects"}}`},
+			want:  []string{"projects/*"},
+		},
+		{
+			name:    "invalid workspaces",
+			files:   map[string]string{"package.json": `{"workspaces": "apps/*"}`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchDir := t.TempDir()
+			for name, content := range tt.files {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(searchDir, name), []byte(content), 0644))
+			}
+
+			got, err := workspacePackagePatterns(searchDir)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWorkspacePackageDirs(t *testing.T) {
+	tests := []struct {
+		name        string
+		packageJSON string
+		dirs        []string
+		want        []string
+	}{
+		{
+			name:        "not a monorepo",
+			packageJSON: `{"name": "app"}`,
+			dirs:        []string{"apps/mobile"},
+			want:        nil,
+		},
+		{
+			name:        "direct children",
+			packageJSON: `{"workspaces": ["apps/*", "packages/*"]}`,
+			dirs:        []string{"apps/mobile", "apps/web", "packages/ui", "tools/scripts"},
+			want:        []string{"apps/mobile", "apps/web", "packages/ui"},
+		},
+		{
+			name:        "relative and double star patterns",
+			packageJSON: `{"workspaces": ["./apps/**/", "packages/ui"]}`,
+			dirs:        []string{"apps/mobile", "packages/ui", "packages/config"},
+			want:        []string{"apps/mobile", "packages/ui"},
+		},
+		{
+			name:        "excluded package",
+			packageJSON: `{"workspaces": ["apps/*", "!apps/legacy"]}`,
+			dirs:        []string{"apps/legacy", "apps/mobile"},
+			want:        []string{"apps/mobile"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchDir := t.TempDir()
+			require.NoError(t, ioutil.WriteFile(filepath.Join(searchDir, "package.json"), []byte(tt.packageJSON), 0644))
+			for _, dir := range tt.dirs {
+				require.NoError(t, os.MkdirAll(filepath.Join(searchDir, dir), 0755))
+			}
+
+			var want []string
+			for _, dir := range tt.want {
+				want = append(want, filepath.Join(searchDir, dir))
+			}
+
+			got, err := workspacePackageDirs(searchDir)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestFilterProjectPathOptions(t *testing.T) {
+	searchDir := t.TempDir()
+	projectDir := filepath.Join(searchDir, "apps", "mobile")
+
+	newOptions := func() *models.OptionNode {
+		options := models.NewOption("Project path", "", "BITRISE_PROJECT_PATH", models.TypeSelector)
+		for _, pth := range []string{"apps/mobile/ios/Mobile.xcworkspace", "apps/mobile-legacy/ios/Legacy.xcworkspace", filepath.Join(searchDir, "apps/mobile/ios/Other.xcodeproj")} {
+			options.AddConfig(pth, nil)
+		}
+		return options
+	}
+
+	options := newOptions()
+	require.NoError(t, filterProjectPathOptions(options, searchDir, projectDir))
+
+	var got []string
+	for pth := range options.ChildOptionMap {
+		got = append(got, pth)
+	}
+	require.ElementsMatch(t, []string{"apps/mobile/ios/Mobile.xcworkspace", filepath.Join(searchDir, "apps/mobile/ios/Other.xcodeproj")}, got)
+
+	require.Error(t, filterProjectPathOptions(newOptions(), searchDir, filepath.Join(searchDir, "apps", "web")))
+}
+
+func TestMonorepoAppConfigs(t *testing.T) {
+	searchDir := t.TempDir()
+	files := map[string]string{
+		"package.json":                `{"private": true, "workspaces": ["apps/*"]}`,
+		"yarn.lock":                   "",
+		"apps/mobile/package.json":    `{"dependencies": {"expo": "^50.0.0", "react-native": "0.73.0"}}`,
+		"apps/mobile/app.json":        `{"expo": {"name": "Mobile", "ios": {"bundleIdentifier": "com.sample.mobile"}, "android": {"package": "com.sample.mobile"}}}`,
+		"apps/companion/package.json": `{"dependencies": {"expo": "^50.0.0", "react-native": "0.73.0"}, "scripts": {"test": "jest"}}`,
+		"apps/companion/app.json":     `{"expo": {"name": "Companion", "ios": {"bundleIdentifier": "com.sample.companion"}, "android": {"package": "com.sample.companion"}}}`,
+	}
+	for pth, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(searchDir, pth)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(searchDir, pth), []byte(content), 0644))
+	}
+
+	// the scanners work in the search dir
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(searchDir))
+	defer func() {
+		require.NoError(t, os.Chdir(wd))
+	}()
+
+	scanner := NewScanner()
+	detected, err := scanner.DetectPlatform(searchDir)
+	require.NoError(t, err)
+	require.True(t, detected)
+
+	options, _, _, err := scanner.Options()
+	require.NoError(t, err)
+	require.Equal(t, wordirEnv, options.EnvKey)
+
+	var optionConfigs []string
+	for _, option := range options.LastChilds() {
+		for _, child := range option.ChildOptionMap {
+			optionConfigs = append(optionConfigs, child.Config)
+		}
+	}
+
+	configs, err := scanner.Configs(models.TriggerPresetDefault)
+	require.NoError(t, err)
+
+	var configNames []string
+	for name := range configs {
+		configNames = append(configNames, name)
+	}
+	require.ElementsMatch(t, []string{"react-native-expo-apps-companion-config", "react-native-expo-apps-mobile-config"}, configNames)
+	for _, config := range optionConfigs {
+		require.Contains(t, configs, config)
+	}
+}
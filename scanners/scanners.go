@@ -8,7 +8,9 @@ import (
 	"github.com/bitrise-io/bitrise-init/scanners/flutter"
 	"github.com/bitrise-io/bitrise-init/scanners/ionic"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
+	"github.com/bitrise-io/bitrise-init/scanners/kmp"
 	"github.com/bitrise-io/bitrise-init/scanners/macos"
+	"github.com/bitrise-io/bitrise-init/scanners/maui"
 	"github.com/bitrise-io/bitrise-init/scanners/reactnative"
 	"github.com/bitrise-io/bitrise-init/scanners/xamarin"
 	"github.com/bitrise-io/bitrise-init/steps"
@@ -52,13 +54,17 @@ type ScannerInterface interface {
 
 	// BitriseConfigMap's each element is a bitrise config template which will be fulfilled with the user selected options.
 	// Every config's key should be the last option one of the OptionNode branches.
+	// Inputs:
+	// - triggerPreset: the preset of the configs' trigger map.
 	// Returns:
 	// - platform BitriseConfigMap
-	Configs() (models.BitriseConfigMap, error)
+	Configs(models.TriggerPreset) (models.BitriseConfigMap, error)
 
+	// Inputs:
+	// - triggerPreset: the preset of the configs' trigger map.
 	// Returns:
 	// - platform default BitriseConfigMap
-	DefaultConfigs() (models.BitriseConfigMap, error)
+	DefaultConfigs(models.TriggerPreset) (models.BitriseConfigMap, error)
 }
 
 // AutomationToolScanner contains additional methods (relative to ScannerInterface)
@@ -68,15 +74,38 @@ type AutomationToolScanner interface {
 	SetDetectedProjectTypes(projectTypes []string)
 }
 
+// MetadataProvider is implemented by the scanners, which report detected project properties (like tool versions).
+type MetadataProvider interface {
+	// Returns the metadata of the projects found by DetectPlatform,
+	// called after Configs().
+	Metadata() models.Metadata
+}
+
+// ExcludedDirsProvider is implemented by the scanners, which exclude the scanners named by ExcludedScannerNames
+// only from the directories of their projects, instead of the whole search dir.
+type ExcludedDirsProvider interface {
+	// Returns the directories of the projects found by DetectPlatform, relative to the search dir.
+	ExcludedDirs() []string
+}
+
+// DirExcluder is implemented by the scanners, which can skip the projects in some directories of the search dir.
+type DirExcluder interface {
+	// Sets the directories to skip, relative to the search dir,
+	// called before DetectPlatform.
+	ExcludeDirs(dirs []string)
+}
+
 // ProjectScanners ...
 var ProjectScanners = []ScannerInterface{
 	reactnative.NewScanner(),
 	flutter.NewScanner(),
 	ionic.NewScanner(),
 	cordova.NewScanner(),
+	kmp.NewScanner(),
 	ios.NewScanner(),
 	macos.NewScanner(),
 	android.NewScanner(),
+	maui.NewScanner(),
 	xamarin.NewScanner(),
 }
 
@@ -92,8 +121,8 @@ const CustomProjectType = "other"
 const CustomConfigName = "other-config"
 
 // CustomConfig ...
-func CustomConfig() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func CustomConfig(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
 
@@ -0,0 +1,224 @@
+package xamarin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+)
+
+// ProjectType ...
+type ProjectType string
+
+// ProjectTypes ...
+const (
+	ProjectTypeIOS       ProjectType = "ios"
+	ProjectTypeAndroid   ProjectType = "android"
+	ProjectTypeMac       ProjectType = "macos"
+	ProjectTypeNUnitTest ProjectType = "nunit"
+	ProjectTypeUITest    ProjectType = "uitest"
+	ProjectTypeOther     ProjectType = "other"
+)
+
+// The ProjectTypeGuids of the Xamarin app projects.
+const (
+	iosProjectTypeGUID     = "FEACFBD2-3405-455C-9665-78FE426C6842"
+	androidProjectTypeGUID = "EFBA0AD7-5A72-4C68-AF49-83D382785DCF"
+	macProjectTypeGUID     = "A3F8F2AB-B479-4A4A-A458-A89E7DC349F1"
+)
+
+const (
+	projectConfigurationStart = "GlobalSection(ProjectConfigurationPlatforms) = postSolution"
+
+	// iOS app projects create an ipa for the device platform only.
+	iosDevicePlatform = "iPhone"
+)
+
+// solutionProjectRegexp matches the project lines of a solution file, like:
+// Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "MyApp", "MyApp\MyApp.csproj", "{6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}"
+var solutionProjectRegexp = regexp.MustCompile(`^Project\("\{[^}]+\}"\)\s*=\s*"([^"]*)",\s*"([^"]+)",\s*"\{([^}]+)\}"`)
+
+// projectBuildRegexp matches the project configuration lines of a solution file, which map a solution configuration to a project configuration:
+// {6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}.Release|iPhone.Build.0 = Release|iPhone
+var projectBuildRegexp = regexp.MustCompile(`^\{([^}]+)\}\.(.+\|.+)\.Build\.0\s*=\s*(.+\|.+)$`)
+
+// SolutionProject is a project referenced by a solution file.
+type SolutionProject struct {
+	ID   string
+	Name string
+	// Pth is the path of the project file, relative to the solution file's dir.
+	Pth string
+	// Builds maps the solution configurations (Configuration|Platform), which build the project, to the project configurations.
+	Builds map[string]string
+}
+
+// Project is the analysis of a project file.
+type Project struct {
+	SolutionProject
+	Type         ProjectType
+	AssemblyName string
+	// IsSDKStyle is true for the projects using an MSBuild project SDK (<Project Sdk="...">), they can be built and tested by the dotnet CLI.
+	IsSDKStyle bool
+}
+
+type projectPropertyGroupModel struct {
+	ProjectTypeGuids   string `xml:"ProjectTypeGuids"`
+	OutputType         string `xml:"OutputType"`
+	AndroidApplication string `xml:"AndroidApplication"`
+	AssemblyName       string `xml:"AssemblyName"`
+}
+
+type projectReferenceModel struct {
+	Include string `xml:"Include,attr"`
+}
+
+type projectItemGroupModel struct {
+	References        []projectReferenceModel `xml:"Reference"`
+	PackageReferences []projectReferenceModel `xml:"PackageReference"`
+}
+
+type projectModel struct {
+	Sdk            string                      `xml:"Sdk,attr"`
+	PropertyGroups []projectPropertyGroupModel `xml:"PropertyGroup"`
+	ItemGroups     []projectItemGroupModel     `xml:"ItemGroup"`
+}
+
+// IsApp returns true for the iOS, Android and macOS app projects.
+func (project Project) IsApp() bool {
+	return project.Type == ProjectTypeIOS || project.Type == ProjectTypeAndroid || project.Type == ProjectTypeMac
+}
+
+// IsTest returns true for the NUnit and Xamarin.UITest test projects.
+func (project Project) IsTest() bool {
+	return project.Type == ProjectTypeNUnitTest || project.Type == ProjectTypeUITest
+}
+
+// BuildsArchive returns true if the solution configuration (Configuration|Platform) builds an archivable app of the project.
+func (project Project) BuildsArchive(solutionConfiguration string) bool {
+	projectConfiguration, ok := project.Builds[solutionConfiguration]
+	if !ok || !project.IsApp() {
+		return false
+	}
+	if project.Type == ProjectTypeIOS {
+		split := strings.Split(projectConfiguration, "|")
+		return strings.TrimSpace(split[len(split)-1]) == iosDevicePlatform
+	}
+	return true
+}
+
+// GetSolutionProjects returns the projects referenced by the solution file.
+func GetSolutionProjects(solutionFile string) ([]SolutionProject, error) {
+	content, err := fileutil.ReadStringFromFile(solutionFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []SolutionProject
+	projectIndexes := map[string]int{}
+	isProjectConfigurationSection := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if match := solutionProjectRegexp.FindStringSubmatch(line); len(match) == 4 {
+			projectIndexes[strings.ToUpper(match[3])] = len(projects)
+			projects = append(projects, SolutionProject{
+				ID:     match[3],
+				Name:   match[1],
+				Pth:    filepath.FromSlash(strings.Replace(match[2], "\\", "/", -1)),
+				Builds: map[string]string{},
+			})
+			continue
+		}
+
+		if strings.Contains(line, projectConfigurationStart) {
+			isProjectConfigurationSection = true
+			continue
+		}
+		if strings.Contains(line, solutionConfigurationEnd) {
+			isProjectConfigurationSection = false
+			continue
+		}
+
+		if isProjectConfigurationSection {
+			match := projectBuildRegexp.FindStringSubmatch(line)
+			if len(match) != 4 {
+				continue
+			}
+			if idx, ok := projectIndexes[strings.ToUpper(match[1])]; ok {
+				projects[idx].Builds[strings.TrimSpace(match[2])] = strings.TrimSpace(match[3])
+			}
+		}
+	}
+
+	return projects, nil
+}
+
+// AnalyzeProject reads the project file of the solution project, and determines its type.
+func AnalyzeProject(solutionFile string, solutionProject SolutionProject) (Project, error) {
+	content, err := fileutil.ReadStringFromFile(filepath.Join(filepath.Dir(solutionFile), solutionProject.Pth))
+	if err != nil {
+		return Project{}, err
+	}
+	return analyzeProjectContent(solutionProject, content)
+}
+
+func analyzeProjectContent(solutionProject SolutionProject, content string) (Project, error) {
+	var model projectModel
+	if err := xml.Unmarshal([]byte(content), &model); err != nil {
+		return Project{}, fmt.Errorf("failed to parse project file (%s), error: %s", solutionProject.Pth, err)
+	}
+
+	var typeGUIDs, outputType, androidApplication, assemblyName string
+	for _, group := range model.PropertyGroups {
+		if group.ProjectTypeGuids != "" {
+			typeGUIDs = strings.ToUpper(group.ProjectTypeGuids)
+		}
+		if group.OutputType != "" {
+			outputType = group.OutputType
+		}
+		if group.AndroidApplication != "" {
+			androidApplication = group.AndroidApplication
+		}
+		if group.AssemblyName != "" {
+			assemblyName = group.AssemblyName
+		}
+	}
+
+	references := map[string]bool{}
+	for _, group := range model.ItemGroups {
+		for _, reference := range append(group.References, group.PackageReferences...) {
+			// nunit.framework, Version=3.12.0.0, Culture=neutral, PublicKeyToken=2638cd05610744eb
+			name := strings.TrimSpace(strings.Split(reference.Include, ",")[0])
+			references[strings.ToLower(name)] = true
+		}
+	}
+
+	project := Project{
+		SolutionProject: solutionProject,
+		Type:            ProjectTypeOther,
+		AssemblyName:    assemblyName,
+		IsSDKStyle:      model.Sdk != "",
+	}
+	if project.AssemblyName == "" {
+		project.AssemblyName = strings.TrimSuffix(filepath.Base(solutionProject.Pth), filepath.Ext(solutionProject.Pth))
+	}
+
+	isExe := strings.EqualFold(outputType, "Exe")
+	switch {
+	case references["xamarin.uitest"]:
+		project.Type = ProjectTypeUITest
+	case references["nunit.framework"] || references["nunit"]:
+		project.Type = ProjectTypeNUnitTest
+	case strings.Contains(typeGUIDs, iosProjectTypeGUID) && isExe:
+		project.Type = ProjectTypeIOS
+	case strings.Contains(typeGUIDs, androidProjectTypeGUID) && strings.EqualFold(androidApplication, "true"):
+		project.Type = ProjectTypeAndroid
+	case strings.Contains(typeGUIDs, macProjectTypeGUID) && isExe:
+		project.Type = ProjectTypeMac
+	}
+
+	return project, nil
+}
@@ -6,16 +6,21 @@ import (
 	"github.com/bitrise-io/go-utils/pathutil"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/scanners/maui"
 	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/log"
 )
 
-const scannerName = "xamarin"
+// ScannerName ...
+const ScannerName = "xamarin"
 
 const (
 	defaultConfigName = "default-xamarin-config"
@@ -48,7 +53,20 @@ const (
 	xamarinMacLicenseInputKey     = "xamarin_mac_license"
 )
 
-func configName(hasNugetPackages, hasXamarinComponents bool) string {
+const (
+	// TestWorkflowID is the workflow running the NUnit tests of the solution.
+	TestWorkflowID models.WorkflowID = "test"
+
+	testWorkflowDescription = `Builds the NUnit test projects of the solution, and runs their tests.`
+
+	nunitTestsScriptTitle = "Run NUnit tests"
+	// nunitConsoleDir is the directory, where the NUnit console runner is installed by the test workflow.
+	nunitConsoleDir = "$HOME/.nunit"
+)
+
+// configName returns the name of the config, testSolution is the solution running NUnit tests in the test workflow (if any):
+// the test workflow runs the test projects of the solution, so each of these solutions gets its own config.
+func configName(hasNugetPackages, hasXamarinComponents bool, testSolution string) string {
 	name := "xamarin-"
 	if hasNugetPackages {
 		name = name + "nuget-"
@@ -56,9 +74,20 @@ func configName(hasNugetPackages, hasXamarinComponents bool) string {
 	if hasXamarinComponents {
 		name = name + "components-"
 	}
+	if testSolution != "" {
+		name = name + "test-" + solutionConfigNameComponent(testSolution) + "-"
+	}
 	return name + "config"
 }
 
+var configNameInvalidCharsRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// solutionConfigNameComponent returns the solution path (without the extension) in lowercase, its non alphanumeric characters replaced by "-".
+func solutionConfigNameComponent(solutionFile string) string {
+	name := strings.TrimSuffix(filepath.ToSlash(solutionFile), filepath.Ext(solutionFile))
+	return strings.Trim(configNameInvalidCharsRegexp.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
 //--------------------------------------------------
 // Scanner
 //--------------------------------------------------
@@ -74,6 +103,9 @@ type Scanner struct {
 	HasIosProject     bool
 	HasAndroidProject bool
 	HasMacProject     bool
+
+	// TestProjects maps the solution files to their NUnit and Xamarin.UITest projects, the project paths are relative to the search dir.
+	TestProjects map[string][]Project
 }
 
 // NewScanner ...
@@ -83,7 +115,7 @@ func NewScanner() *Scanner {
 
 // Name ...
 func (Scanner) Name() string {
-	return scannerName
+	return ScannerName
 }
 
 // DetectPlatform ...
@@ -102,14 +134,25 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 		return false, fmt.Errorf("failed to search for solution files, error: %s", err)
 	}
 
-	scanner.SolutionFiles = solutionFiles
+	scanner.SolutionFiles = nil
+	for _, solutionFile := range solutionFiles {
+		isMAUI, err := isMAUISolution(solutionFile)
+		if err != nil {
+			log.TWarnf("Failed to inspect the projects of solution (%s), error: %s", solutionFile, err)
+		}
+		if isMAUI {
+			log.TPrintf("Skipping %s, it contains a .NET MAUI project", solutionFile)
+			continue
+		}
+		scanner.SolutionFiles = append(scanner.SolutionFiles, solutionFile)
+	}
 
-	log.TPrintf("%d solution files detected", len(solutionFiles))
-	for _, file := range solutionFiles {
+	log.TPrintf("%d solution files detected", len(scanner.SolutionFiles))
+	for _, file := range scanner.SolutionFiles {
 		log.TPrintf("- %s", file)
 	}
 
-	if len(solutionFiles) == 0 {
+	if len(scanner.SolutionFiles) == 0 {
 		log.TPrintf("platform not detected")
 		return false, nil
 	}
@@ -119,6 +162,35 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	return true, nil
 }
 
+// isMAUISolution returns true if the solution references a .NET MAUI project, which is handled by the maui scanner.
+func isMAUISolution(solutionFile string) (bool, error) {
+	projects, err := GetSolutionProjects(solutionFile)
+	if err != nil {
+		return false, err
+	}
+
+	for _, project := range projects {
+		if filepath.Ext(project.Pth) != ".csproj" {
+			continue
+		}
+		pth := filepath.Join(filepath.Dir(solutionFile), project.Pth)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return false, err
+		} else if !exist {
+			continue
+		}
+
+		isMAUI, err := maui.IsMAUIProject(pth)
+		if err != nil {
+			return false, err
+		}
+		if isMAUI {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ExcludedScannerNames ...
 func (Scanner) ExcludedScannerNames() []string {
 	return []string{}
@@ -179,6 +251,9 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 			continue
 		}
 
+		configs, projectWarnings := scanner.inspectSolutionProjects(solutionFile, configs)
+		warnings = append(warnings, projectWarnings...)
+
 		if len(configs) > 0 {
 			log.TPrintf("%d configurations found", len(configs))
 			for config, platforms := range configs {
@@ -197,6 +272,14 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 		return models.OptionNode{}, warnings, nil, errors.New("No valid solution file found")
 	}
 
+	for _, solutionFile := range scanner.SolutionFiles {
+		for _, project := range scanner.TestProjects[solutionFile] {
+			if project.Type == ProjectTypeUITest {
+				warnings = append(warnings, fmt.Sprintf("The Xamarin.UITest project (%s) of solution (%s) is not run by the %s Workflow, as it needs a simulator or an emulator running the app.", project.Pth, solutionFile, TestWorkflowID))
+			}
+		}
+	}
+
 	// Check for solution projects
 	xamarinSolutionOption := models.NewOption(xamarinSolutionInputTitle, xamarinSolutionInputSummary, xamarinSolutionInputEnvKey, models.TypeSelector)
 
@@ -204,12 +287,14 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 		xamarinConfigurationOption := models.NewOption(xamarinConfigurationInputTitle, xamarinConfigurationInputSummary, xamarinConfigurationInputEnvKey, models.TypeSelector)
 		xamarinSolutionOption.AddOption(solutionFile, xamarinConfigurationOption)
 
+		name := configName(scanner.HasNugetPackages, scanner.HasXamarinComponents, scanner.testSolution(solutionFile))
+
 		for config, platforms := range configMap {
 			xamarinPlatformOption := models.NewOption(xamarinPlatformInputTitle, xamarinPlatformInputSummary, xamarinPlatformInputEnvKey, models.TypeSelector)
 			xamarinConfigurationOption.AddOption(config, xamarinPlatformOption)
 
 			for _, platform := range platforms {
-				configOption := models.NewConfigOption(configName(scanner.HasNugetPackages, scanner.HasXamarinComponents), nil)
+				configOption := models.NewConfigOption(name, nil)
 				xamarinPlatformOption.AddConfig(platform, configOption)
 			}
 		}
@@ -218,6 +303,84 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	return *xamarinSolutionOption, warnings, nil, nil
 }
 
+// inspectSolutionProjects analyzes the projects of the solution, collects its app and test projects,
+// and returns the solution configurations (and platforms), which build an archivable app.
+// If no app project is found, the solution configurations are returned unfiltered.
+func (scanner *Scanner) inspectSolutionProjects(solutionFile string, configs map[string][]string) (map[string][]string, models.Warnings) {
+	warnings := models.Warnings{}
+	if scanner.TestProjects == nil {
+		scanner.TestProjects = map[string][]Project{}
+	}
+
+	solutionProjects, err := GetSolutionProjects(solutionFile)
+	if err != nil {
+		log.TWarnf("Failed to get solution projects, error: %s", err)
+		return configs, append(warnings, fmt.Sprintf("Failed to get solution (%s) projects, error: %s", solutionFile, err))
+	}
+
+	var appProjects []Project
+	for _, solutionProject := range solutionProjects {
+		if filepath.Ext(solutionProject.Pth) != ".csproj" {
+			continue
+		}
+
+		project, err := AnalyzeProject(solutionFile, solutionProject)
+		if err != nil {
+			log.TWarnf("Failed to analyze project (%s), error: %s", solutionProject.Pth, err)
+			continue
+		}
+		log.TPrintf("- %s: %s project", project.Pth, project.Type)
+
+		switch {
+		case project.IsApp():
+			appProjects = append(appProjects, project)
+			scanner.HasIosProject = scanner.HasIosProject || project.Type == ProjectTypeIOS
+			scanner.HasAndroidProject = scanner.HasAndroidProject || project.Type == ProjectTypeAndroid
+			scanner.HasMacProject = scanner.HasMacProject || project.Type == ProjectTypeMac
+		case project.IsTest():
+			project.Pth = filepath.Join(filepath.Dir(solutionFile), project.Pth)
+			scanner.TestProjects[solutionFile] = append(scanner.TestProjects[solutionFile], project)
+		}
+	}
+
+	if len(appProjects) == 0 {
+		log.TWarnf("No app project found in %s", solutionFile)
+		return configs, append(warnings, fmt.Sprintf("No iOS, Android or macOS app project found in solution (%s), all of its configurations are offered.", solutionFile))
+	}
+
+	archivableConfigs := map[string][]string{}
+	for config, platforms := range configs {
+		for _, platform := range platforms {
+			for _, project := range appProjects {
+				if project.BuildsArchive(config + "|" + platform) {
+					archivableConfigs[config] = append(archivableConfigs[config], platform)
+					break
+				}
+			}
+		}
+	}
+	return archivableConfigs, warnings
+}
+
+// nunitTestProjects returns the NUnit test projects of the solution, which are run by the test workflow.
+func (scanner *Scanner) nunitTestProjects(solutionFile string) []Project {
+	var projects []Project
+	for _, project := range scanner.TestProjects[solutionFile] {
+		if project.Type == ProjectTypeNUnitTest {
+			projects = append(projects, project)
+		}
+	}
+	return projects
+}
+
+// testSolution returns the solution file if it has NUnit test projects, which are run by the test workflow of its config.
+func (scanner *Scanner) testSolution(solutionFile string) string {
+	if len(scanner.nunitTestProjects(solutionFile)) == 0 {
+		return ""
+	}
+	return solutionFile
+}
+
 // DefaultOptions ...
 func (Scanner) DefaultOptions() models.OptionNode {
 	xamarinSolutionOption := models.NewOption(xamarinSolutionInputTitle, xamarinSolutionInputSummary, xamarinSolutionInputEnvKey, models.TypeUserInput)
@@ -235,11 +398,73 @@ func (Scanner) DefaultOptions() models.OptionNode {
 }
 
 // Configs ...
-func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
+func (scanner *Scanner) Configs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	var solutionDirs []string
+	for _, solutionFile := range scanner.SolutionFiles {
+		solutionDirs = append(solutionDirs, filepath.Dir(solutionFile))
+	}
+	caches, err := utility.DetectDependencyCaches(".", solutionDirs, utility.NuGetDependencyManager)
+	if err != nil {
+		return models.BitriseConfigMap{}, fmt.Errorf("failed to detect dependency caches, error: %s", err)
+	}
+
+	configMap := models.BitriseConfigMap{}
+	for _, solutionFile := range scanner.SolutionFiles {
+		name := configName(scanner.HasNugetPackages, scanner.HasXamarinComponents, scanner.testSolution(solutionFile))
+		if _, ok := configMap[name]; ok {
+			continue
+		}
+
+		config, err := scanner.config(triggerPreset, caches, scanner.nunitTestProjects(solutionFile))
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+
+		configMap[name] = string(data)
+	}
+
+	return configMap, nil
+}
+
+// config generates the config of a solution, testProjects are the NUnit test projects of the solution.
+func (scanner *Scanner) config(triggerPreset models.TriggerPreset, caches []steps.DependencyCache, testProjects []Project) (models.BitriseDataModel, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, scanner.restoreStepList()...)
+
+	// XamarinArchive
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinArchiveStepListItem(
+		envmanModels.EnvironmentItemModel{xamarinSolutionInputKey: "$" + xamarinSolutionInputEnvKey},
+		envmanModels.EnvironmentItemModel{xamarinConfigurationInputKey: "$" + xamarinConfigurationInputEnvKey},
+		envmanModels.EnvironmentItemModel{xamarinPlatformInputKey: "$" + xamarinPlatformInputEnvKey},
+	))
+
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
+
+	// Test
+	if len(testProjects) > 0 {
+		configBuilder.AppendStepListItemsTo(TestWorkflowID, steps.DefaultPrepareStepList(false, caches...)...)
+		configBuilder.AppendStepListItemsTo(TestWorkflowID, scanner.restoreStepList()...)
+		configBuilder.AppendStepListItemsTo(TestWorkflowID, steps.ScriptSteplistItem(nunitTestsScriptTitle,
+			envmanModels.EnvironmentItemModel{"content": nunitTestsScript(testProjects)},
+		))
+		configBuilder.AppendStepListItemsTo(TestWorkflowID, steps.DefaultDeployStepList(false, caches...)...)
+		configBuilder.SetWorkflowDescriptionTo(TestWorkflowID, testWorkflowDescription)
+	}
+
+	return configBuilder.Generate(ScannerName)
+}
+
+// restoreStepList returns the steps activating the Xamarin licences of the app projects and restoring the dependencies.
+func (scanner *Scanner) restoreStepList() []bitriseModels.StepListItemModel {
+	var stepList []bitriseModels.StepListItemModel
 
 	// XamarinUserManagement
 	if scanner.HasXamarinComponents {
@@ -254,46 +479,57 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 			inputs = append(inputs, envmanModels.EnvironmentItemModel{xamarinMacLicenseInputKey: "yes"})
 		}
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinUserManagementStepListItem(inputs...))
+		stepList = append(stepList, steps.XamarinUserManagementStepListItem(inputs...))
 	}
 
 	// NugetRestore
 	if scanner.HasNugetPackages {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NugetRestoreStepListItem())
+		stepList = append(stepList, steps.NugetRestoreStepListItem())
 	}
 
 	// XamarinComponentsRestore
 	if scanner.HasXamarinComponents {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinComponentsRestoreStepListItem())
+		stepList = append(stepList, steps.XamarinComponentsRestoreStepListItem())
 	}
 
-	// XamarinArchive
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinArchiveStepListItem(
-		envmanModels.EnvironmentItemModel{xamarinSolutionInputKey: "$" + xamarinSolutionInputEnvKey},
-		envmanModels.EnvironmentItemModel{xamarinConfigurationInputKey: "$" + xamarinConfigurationInputEnvKey},
-		envmanModels.EnvironmentItemModel{xamarinPlatformInputKey: "$" + xamarinPlatformInputEnvKey},
-	))
-
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+	return stepList
+}
 
-	config, err := configBuilder.Generate(scannerName)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
+// nunitTestsScript returns the script building the NUnit test projects (in their default, Debug configuration), and running their tests.
+// SDK-style projects are tested by the dotnet CLI, the others are built by msbuild and their tests are run by the NUnit console runner.
+func nunitTestsScript(testProjects []Project) string {
+	script := `#!/usr/bin/env bash
+set -ex
+`
+	for _, project := range testProjects {
+		if !project.IsSDKStyle {
+			script += fmt.Sprintf(`
+nuget install NUnit.ConsoleRunner -ExcludeVersion -OutputDirectory "%s"
+`, nunitConsoleDir)
+			break
+		}
 	}
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
+	for _, project := range testProjects {
+		if project.IsSDKStyle {
+			script += fmt.Sprintf(`
+dotnet test "%s" --logger "trx;LogFileName=$BITRISE_DEPLOY_DIR/%s-TestResult.trx"
+`, project.Pth, project.AssemblyName)
+			continue
+		}
 
-	return models.BitriseConfigMap{
-		configName(scanner.HasNugetPackages, scanner.HasXamarinComponents): string(data),
-	}, nil
+		assemblyPth := filepath.Join(filepath.Dir(project.Pth), "bin", "Debug", project.AssemblyName+".dll")
+		script += fmt.Sprintf(`
+msbuild -restore "%s"
+mono "%s/NUnit.ConsoleRunner/tools/nunit3-console.exe" "%s" --result="$BITRISE_DEPLOY_DIR/%s-TestResult.xml"
+`, project.Pth, nunitConsoleDir, assemblyPth, project.AssemblyName)
+	}
+	return strings.TrimSuffix(script, "\n")
 }
 
 // DefaultConfigs ...
-func (Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func (Scanner) DefaultConfigs(triggerPreset models.TriggerPreset) (models.BitriseConfigMap, error) {
+	configBuilder := models.NewDefaultConfigBuilder(triggerPreset)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
@@ -309,7 +545,7 @@ func (Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 	))
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
 
-	config, err := configBuilder.Generate(scannerName)
+	config, err := configBuilder.Generate(ScannerName)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
@@ -1,6 +1,8 @@
 package xamarin
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -36,3 +38,203 @@ func TestFilterSolutionFiles(t *testing.T) {
 		require.Equal(t, 0, len(files))
 	}
 }
+
+func TestGetSolutionProjects(t *testing.T) {
+	solutionFile := filepath.Join(t.TempDir(), "XamarinApp.sln")
+	require.NoError(t, os.WriteFile(solutionFile, []byte(testSolutionContent), 0600))
+
+	projects, err := GetSolutionProjects(solutionFile)
+	require.NoError(t, err)
+	require.Equal(t, []SolutionProject{
+		{
+			ID:   "6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2",
+			Name: "XamarinApp.iOS",
+			Pth:  filepath.Join("XamarinApp.iOS", "XamarinApp.iOS.csproj"),
+			Builds: map[string]string{
+				"Debug|iPhoneSimulator":   "Debug|iPhoneSimulator",
+				"Release|iPhone":          "Release|iPhone",
+				"Release|iPhoneSimulator": "Release|iPhoneSimulator",
+			},
+		},
+		{
+			ID:     "0C2B3A4D-1E8F-4F6A-9B7C-5D3E2F1A0B9C",
+			Name:   "XamarinApp.Tests",
+			Pth:    filepath.Join("XamarinApp.Tests", "XamarinApp.Tests.csproj"),
+			Builds: map[string]string{"Debug|iPhoneSimulator": "Debug|Any CPU"},
+		},
+	}, projects)
+}
+
+func TestAnalyzeProjectContent(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantType     ProjectType
+		wantAssembly string
+		wantSDKStyle bool
+	}{
+		{
+			name: "iOS app",
+			content: `<Project ToolsVersion="4.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <PropertyGroup>
+    <ProjectTypeGuids>{FEACFBD2-3405-455C-9665-78FE426C6842};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>
+    <OutputType>Exe</OutputType>
+    <AssemblyName>XamarinApp</AssemblyName>
+  </PropertyGroup>
+</Project>`,
+			wantType:     ProjectTypeIOS,
+			wantAssembly: "XamarinApp",
+		},
+		{
+			name: "Android app",
+			content: `<Project ToolsVersion="4.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <PropertyGroup>
+    <ProjectTypeGuids>{EFBA0AD7-5A72-4C68-AF49-83D382785DCF};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>
+    <OutputType>Library</OutputType>
+    <AndroidApplication>True</AndroidApplication>
+  </PropertyGroup>
+</Project>`,
+			wantType:     ProjectTypeAndroid,
+			wantAssembly: "Project",
+		},
+		{
+			name: "Android library",
+			content: `<Project ToolsVersion="4.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <PropertyGroup>
+    <ProjectTypeGuids>{EFBA0AD7-5A72-4C68-AF49-83D382785DCF};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>
+    <OutputType>Library</OutputType>
+  </PropertyGroup>
+</Project>`,
+			wantType:     ProjectTypeOther,
+			wantAssembly: "Project",
+		},
+		{
+			name: "NUnit test project",
+			content: `<Project ToolsVersion="4.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <ItemGroup>
+    <Reference Include="nunit.framework, Version=3.12.0.0, Culture=neutral, PublicKeyToken=2638cd05610744eb" />
+  </ItemGroup>
+</Project>`,
+			wantType:     ProjectTypeNUnitTest,
+			wantAssembly: "Project",
+		},
+		{
+			name: "Xamarin.UITest project",
+			content: `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="NUnit" Version="3.13.3" />
+    <PackageReference Include="Xamarin.UITest" Version="4.1.1" />
+  </ItemGroup>
+</Project>`,
+			wantType:     ProjectTypeUITest,
+			wantAssembly: "Project",
+			wantSDKStyle: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := analyzeProjectContent(SolutionProject{Pth: filepath.Join("Project", "Project.csproj")}, tt.content)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantType, project.Type)
+			require.Equal(t, tt.wantAssembly, project.AssemblyName)
+			require.Equal(t, tt.wantSDKStyle, project.IsSDKStyle)
+		})
+	}
+}
+
+func TestProjectBuildsArchive(t *testing.T) {
+	project := Project{
+		SolutionProject: SolutionProject{Builds: map[string]string{
+			"Debug|iPhoneSimulator": "Debug|iPhoneSimulator",
+			"Release|iPhone":        "Release|iPhone",
+		}},
+		Type: ProjectTypeIOS,
+	}
+	require.True(t, project.BuildsArchive("Release|iPhone"))
+	require.False(t, project.BuildsArchive("Debug|iPhoneSimulator"))
+	require.False(t, project.BuildsArchive("Release|Any CPU"))
+}
+
+func TestNunitTestsScript(t *testing.T) {
+	script := nunitTestsScript([]Project{
+		{SolutionProject: SolutionProject{Pth: filepath.Join("App.Tests", "App.Tests.csproj")}, Type: ProjectTypeNUnitTest, AssemblyName: "App.Tests", IsSDKStyle: true},
+		{SolutionProject: SolutionProject{Pth: filepath.Join("Core.Tests", "Core.Tests.csproj")}, Type: ProjectTypeNUnitTest, AssemblyName: "Core.Tests"},
+	})
+	require.Equal(t, `#!/usr/bin/env bash
+set -ex
+
+nuget install NUnit.ConsoleRunner -ExcludeVersion -OutputDirectory "$HOME/.nunit"
+
+dotnet test "App.Tests/App.Tests.csproj" --logger "trx;LogFileName=$BITRISE_DEPLOY_DIR/App.Tests-TestResult.trx"
+
+msbuild -restore "Core.Tests/Core.Tests.csproj"
+mono "$HOME/.nunit/NUnit.ConsoleRunner/tools/nunit3-console.exe" "Core.Tests/bin/Debug/Core.Tests.dll" --result="$BITRISE_DEPLOY_DIR/Core.Tests-TestResult.xml"`, script)
+}
+
+func TestConfigName(t *testing.T) {
+	require.Equal(t, "xamarin-nuget-config", configName(true, false, ""))
+	require.Equal(t, "xamarin-nuget-test-apps-my-app-config", configName(true, false, filepath.Join("Apps", "My.App.sln")))
+}
+
+func TestInspectSolutionProjectsKeepsTestProjectsPerSolution(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(pth, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0700))
+		require.NoError(t, os.WriteFile(pth, []byte(content), 0600))
+	}
+	iosProjectContent := `<Project ToolsVersion="4.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <PropertyGroup>
+    <ProjectTypeGuids>{FEACFBD2-3405-455C-9665-78FE426C6842};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>
+    <OutputType>Exe</OutputType>
+  </PropertyGroup>
+</Project>`
+
+	// both solutions reference a test project, but only the first one's exists
+	testedSolution := filepath.Join(dir, "Tested", "XamarinApp.sln")
+	writeFile(testedSolution, testSolutionContent)
+	writeFile(filepath.Join(dir, "Tested", "XamarinApp.iOS", "XamarinApp.iOS.csproj"), iosProjectContent)
+	writeFile(filepath.Join(dir, "Tested", "XamarinApp.Tests", "XamarinApp.Tests.csproj"), `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="NUnit" Version="3.13.3" />
+  </ItemGroup>
+</Project>`)
+	untestedSolution := filepath.Join(dir, "Untested", "XamarinApp.sln")
+	writeFile(untestedSolution, testSolutionContent)
+	writeFile(filepath.Join(dir, "Untested", "XamarinApp.iOS", "XamarinApp.iOS.csproj"), iosProjectContent)
+
+	scanner := NewScanner()
+	for _, solutionFile := range []string{testedSolution, untestedSolution} {
+		configs, _ := scanner.inspectSolutionProjects(solutionFile, map[string][]string{"Release": {"iPhone"}})
+		require.Equal(t, map[string][]string{"Release": {"iPhone"}}, configs)
+	}
+
+	require.Len(t, scanner.nunitTestProjects(testedSolution), 1)
+	require.True(t, scanner.nunitTestProjects(testedSolution)[0].IsSDKStyle)
+	require.Empty(t, scanner.nunitTestProjects(untestedSolution))
+	require.Equal(t, testedSolution, scanner.testSolution(testedSolution))
+	require.Equal(t, "", scanner.testSolution(untestedSolution))
+}
+
+const testSolutionContent = `Microsoft Visual Studio Solution File, Format Version 12.00
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "XamarinApp.iOS", "XamarinApp.iOS\XamarinApp.iOS.csproj", "{6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "XamarinApp.Tests", "XamarinApp.Tests\XamarinApp.Tests.csproj", "{0C2B3A4D-1E8F-4F6A-9B7C-5D3E2F1A0B9C}"
+EndProject
+Global
+	GlobalSection(SolutionConfigurationPlatforms) = preSolution
+		Debug|iPhoneSimulator = Debug|iPhoneSimulator
+		Release|iPhone = Release|iPhone
+		Release|iPhoneSimulator = Release|iPhoneSimulator
+	EndGlobalSection
+	GlobalSection(ProjectConfigurationPlatforms) = postSolution
+		{6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}.Debug|iPhoneSimulator.ActiveCfg = Debug|iPhoneSimulator
+		{6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}.Debug|iPhoneSimulator.Build.0 = Debug|iPhoneSimulator
+		{6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}.Release|iPhone.ActiveCfg = Release|iPhone
+		{6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}.Release|iPhone.Build.0 = Release|iPhone
+		{6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}.Release|iPhoneSimulator.ActiveCfg = Release|iPhoneSimulator
+		{6B8A3FD1-5B6A-4A4E-B6D4-2C4F3DE1E0D2}.Release|iPhoneSimulator.Build.0 = Release|iPhoneSimulator
+		{0C2B3A4D-1E8F-4F6A-9B7C-5D3E2F1A0B9C}.Debug|iPhoneSimulator.ActiveCfg = Debug|Any CPU
+		{0C2B3A4D-1E8F-4F6A-9B7C-5D3E2F1A0B9C}.Debug|iPhoneSimulator.Build.0 = Debug|Any CPU
+	EndGlobalSection
+EndGlobal
+`
@@ -0,0 +1,49 @@
+package steps
+
+import (
+	"fmt"
+	"strings"
+
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+)
+
+// DependencyCache is a key-based cache of a dependency manager.
+// The cache key is derived from the checksum of the KeyFiles (lockfiles or glob patterns),
+// so the cache is only invalidated if the dependencies change.
+type DependencyCache struct {
+	Name     string
+	KeyFiles []string
+	Paths    []string
+}
+
+// Key returns the cache key template of the dependency cache.
+func (cache DependencyCache) Key() string {
+	var files []string
+	for _, file := range cache.KeyFiles {
+		files = append(files, fmt.Sprintf("%q", file))
+	}
+	return fmt.Sprintf(`{{ .OS }}-{{ .Arch }}-%s-{{ checksum %s }}`, cache.Name, strings.Join(files, " "))
+}
+
+// FallbackKey returns the cache key prefix used to restore the latest cache, if no exact match exists.
+func (cache DependencyCache) FallbackKey() string {
+	return fmt.Sprintf(`{{ .OS }}-{{ .Arch }}-%s-`, cache.Name)
+}
+
+// RestoreCacheStepList returns a restore-cache step for each dependency cache.
+func RestoreCacheStepList(caches ...DependencyCache) []bitriseModels.StepListItemModel {
+	var stepList []bitriseModels.StepListItemModel
+	for _, cache := range caches {
+		stepList = append(stepList, RestoreCacheStepListItem(cache.Key(), cache.FallbackKey()))
+	}
+	return stepList
+}
+
+// SaveCacheStepList returns a save-cache step for each dependency cache.
+func SaveCacheStepList(caches ...DependencyCache) []bitriseModels.StepListItemModel {
+	var stepList []bitriseModels.StepListItemModel
+	for _, cache := range caches {
+		stepList = append(stepList, SaveCacheStepListItem(cache.Key(), cache.Paths...))
+	}
+	return stepList
+}
@@ -28,6 +28,48 @@ const (
 	AndroidBuildVersion = "0"
 )
 
+const (
+	// AndroidBuildForUITestingID ...
+	AndroidBuildForUITestingID = "android-build-for-ui-testing"
+	// AndroidBuildForUITestingVersion ...
+	AndroidBuildForUITestingVersion = "0"
+)
+
+const (
+	// AndroidInstrumentedTestID ...
+	AndroidInstrumentedTestID = "android-instrumented-test"
+	// AndroidInstrumentedTestVersion ...
+	AndroidInstrumentedTestVersion = "0"
+)
+
+const (
+	// AVDManagerID ...
+	AVDManagerID = "avd-manager"
+	// AVDManagerVersion ...
+	AVDManagerVersion = "1"
+)
+
+const (
+	// WaitForAndroidEmulatorID ...
+	WaitForAndroidEmulatorID = "wait-for-android-emulator"
+	// WaitForAndroidEmulatorVersion ...
+	WaitForAndroidEmulatorVersion = "1"
+)
+
+const (
+	// GradleRunnerID ...
+	GradleRunnerID = "gradle-runner"
+	// GradleRunnerVersion ...
+	GradleRunnerVersion = "2"
+)
+
+const (
+	// SetJavaVersionID ...
+	SetJavaVersionID = "set-java-version"
+	// SetJavaVersionVersion ...
+	SetJavaVersionVersion = "1"
+)
+
 const (
 	// GitCloneID ...
 	GitCloneID = "git-clone"
@@ -49,6 +91,20 @@ const (
 	CachePushVersion = "2"
 )
 
+const (
+	// RestoreCacheID ...
+	RestoreCacheID = "restore-cache"
+	// RestoreCacheVersion ...
+	RestoreCacheVersion = "2"
+)
+
+const (
+	// SaveCacheID ...
+	SaveCacheID = "save-cache"
+	// SaveCacheVersion ...
+	SaveCacheVersion = "1"
+)
+
 const (
 	// CertificateAndProfileInstallerID ...
 	CertificateAndProfileInstallerID = "certificate-and-profile-installer"
@@ -125,7 +181,7 @@ const (
 	// XcodeArchiveID ...
 	XcodeArchiveID = "xcode-archive"
 	// XcodeArchiveVersion ...
-	XcodeArchiveVersion = "3"
+	XcodeArchiveVersion = "4"
 )
 
 const (
@@ -135,6 +191,13 @@ const (
 	XcodeTestVersion = "2"
 )
 
+const (
+	// XcodeBuildForSimulatorID ...
+	XcodeBuildForSimulatorID = "xcode-build-for-simulator"
+	// XcodeBuildForSimulatorVersion ...
+	XcodeBuildForSimulatorVersion = "0"
+)
+
 const (
 	// XamarinUserManagementID ...
 	XamarinUserManagementID = "xamarin-user-management"
@@ -1,6 +1,8 @@
 package steps
 
 import (
+	"strings"
+
 	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/pointers"
@@ -32,13 +34,16 @@ func stepListItem(stepIDComposite, title, runIf string, inputs ...envmanModels.E
 }
 
 // DefaultPrepareStepList ...
-func DefaultPrepareStepList(isIncludeCache bool) []bitriseModels.StepListItemModel {
+// If dependency caches are given, they are restored by key-based cache steps instead of the cache-pull step.
+func DefaultPrepareStepList(isIncludeCache bool, caches ...DependencyCache) []bitriseModels.StepListItemModel {
 	stepList := []bitriseModels.StepListItemModel{
 		ActivateSSHKeyStepListItem(),
 		GitCloneStepListItem(),
 	}
 
-	if isIncludeCache {
+	if len(caches) > 0 {
+		stepList = append(stepList, RestoreCacheStepList(caches...)...)
+	} else if isIncludeCache {
 		stepList = append(stepList, CachePullStepListItem())
 	}
 
@@ -46,12 +51,15 @@ func DefaultPrepareStepList(isIncludeCache bool) []bitriseModels.StepListItemMod
 }
 
 // DefaultDeployStepList ...
-func DefaultDeployStepList(isIncludeCache bool) []bitriseModels.StepListItemModel {
+// If dependency caches are given, they are saved by key-based cache steps instead of the cache-push step.
+func DefaultDeployStepList(isIncludeCache bool, caches ...DependencyCache) []bitriseModels.StepListItemModel {
 	stepList := []bitriseModels.StepListItemModel{
 		DeployToBitriseIoStepListItem(),
 	}
 
-	if isIncludeCache {
+	if len(caches) > 0 {
+		stepList = append(stepList, SaveCacheStepList(caches...)...)
+	} else if isIncludeCache {
 		stepList = append(stepList, CachePushStepListItem())
 	}
 
@@ -83,6 +91,42 @@ func AndroidBuildStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitri
 	return stepListItem(stepIDComposite, "", "", inputs...)
 }
 
+// AndroidBuildForUITestingStepListItem ...
+func AndroidBuildForUITestingStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(AndroidBuildForUITestingID, AndroidBuildForUITestingVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
+// AndroidInstrumentedTestStepListItem ...
+func AndroidInstrumentedTestStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(AndroidInstrumentedTestID, AndroidInstrumentedTestVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
+// AVDManagerStepListItem ...
+func AVDManagerStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(AVDManagerID, AVDManagerVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
+// WaitForAndroidEmulatorStepListItem ...
+func WaitForAndroidEmulatorStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(WaitForAndroidEmulatorID, WaitForAndroidEmulatorVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
+// GradleRunnerStepListItem ...
+func GradleRunnerStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(GradleRunnerID, GradleRunnerVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
+// SetJavaVersionStepListItem ...
+func SetJavaVersionStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(SetJavaVersionID, SetJavaVersionVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
 // GitCloneStepListItem ...
 func GitCloneStepListItem() bitriseModels.StepListItemModel {
 	stepIDComposite := stepIDComposite(GitCloneID, GitCloneVersion)
@@ -101,6 +145,21 @@ func CachePushStepListItem() bitriseModels.StepListItemModel {
 	return stepListItem(stepIDComposite, "", "")
 }
 
+// RestoreCacheStepListItem ...
+func RestoreCacheStepListItem(keys ...string) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(RestoreCacheID, RestoreCacheVersion)
+	return stepListItem(stepIDComposite, "", "", envmanModels.EnvironmentItemModel{"key": strings.Join(keys, "\n")})
+}
+
+// SaveCacheStepListItem ...
+func SaveCacheStepListItem(key string, paths ...string) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(SaveCacheID, SaveCacheVersion)
+	return stepListItem(stepIDComposite, "", "",
+		envmanModels.EnvironmentItemModel{"key": key},
+		envmanModels.EnvironmentItemModel{"paths": strings.Join(paths, "\n")},
+	)
+}
+
 // CertificateAndProfileInstallerStepListItem ...
 func CertificateAndProfileInstallerStepListItem() bitriseModels.StepListItemModel {
 	stepIDComposite := stepIDComposite(CertificateAndProfileInstallerID, CertificateAndProfileInstallerVersion)
@@ -126,9 +185,9 @@ func ScriptSteplistItem(title string, inputs ...envmanModels.EnvironmentItemMode
 }
 
 // SignAPKStepListItem ...
-func SignAPKStepListItem() bitriseModels.StepListItemModel {
+func SignAPKStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
 	stepIDComposite := stepIDComposite(SignAPKID, SignAPKVersion)
-	return stepListItem(stepIDComposite, "", `{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}`)
+	return stepListItem(stepIDComposite, "", `{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}`, inputs...)
 }
 
 // InstallMissingAndroidToolsStepListItem ....
@@ -173,6 +232,12 @@ func XcodeTestStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseM
 	return stepListItem(stepIDComposite, "", "", inputs...)
 }
 
+// XcodeBuildForSimulatorStepListItem ...
+func XcodeBuildForSimulatorStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(XcodeBuildForSimulatorID, XcodeBuildForSimulatorVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
 // XamarinUserManagementStepListItem ...
 func XamarinUserManagementStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
 	stepIDComposite := stepIDComposite(XamarinUserManagementID, XamarinUserManagementVersion)
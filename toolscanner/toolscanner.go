@@ -2,7 +2,6 @@ package toolscanner
 
 import (
 	"github.com/bitrise-io/bitrise-init/models"
-	bitriseModels "github.com/bitrise-io/bitrise/models"
 )
 
 // ProjectTypeEnvKey is the name of the enviroment variable used to substitute the project type for
@@ -16,8 +15,8 @@ const (
 )
 
 // AddProjectTypeToConfig returns the config filled in with every detected project type, that could be selected
-func AddProjectTypeToConfig(configName string, config bitriseModels.BitriseDataModel, detectedProjectTypes []string) map[string]bitriseModels.BitriseDataModel {
-	configMapWithProjecTypes := map[string]bitriseModels.BitriseDataModel{}
+func AddProjectTypeToConfig(configName string, config models.BitriseDataModel, detectedProjectTypes []string) map[string]models.BitriseDataModel {
+	configMapWithProjecTypes := map[string]models.BitriseDataModel{}
 	for _, projectType := range detectedProjectTypes {
 		configWithProjectType := config
 		configWithProjectType.ProjectType = projectType
@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	"github.com/bitrise-io/bitrise-init/models"
-	bitriseModels "github.com/bitrise-io/bitrise/models"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -143,35 +142,35 @@ func TestAddProjectTypeToOptions(t *testing.T) {
 }
 
 func TestAddProjectTypeToConfig(t *testing.T) {
-	const title = "abcd"
+	const stepLibSource = "https://github.com/bitrise-io/bitrise-steplib.git"
 	type args struct {
 		configName           string
-		config               bitriseModels.BitriseDataModel
+		config               models.BitriseDataModel
 		detectedProjectTypes []string
 	}
 	tests := []struct {
 		name string
 		args args
-		want map[string]bitriseModels.BitriseDataModel
+		want map[string]models.BitriseDataModel
 	}{
 		{
 			name: "2 project types",
 			args: args{
 				configName: "fastlane-config",
-				config: bitriseModels.BitriseDataModel{
-					Title:       title,
-					ProjectType: "other",
+				config: models.BitriseDataModel{
+					DefaultStepLibSource: stepLibSource,
+					ProjectType:          "other",
 				},
 				detectedProjectTypes: []string{"ios", "android"},
 			},
-			want: map[string]bitriseModels.BitriseDataModel{
-				"fastlane-config_ios": bitriseModels.BitriseDataModel{
-					Title:       title,
-					ProjectType: "ios",
+			want: map[string]models.BitriseDataModel{
+				"fastlane-config_ios": models.BitriseDataModel{
+					DefaultStepLibSource: stepLibSource,
+					ProjectType:          "ios",
 				},
-				"fastlane-config_android": bitriseModels.BitriseDataModel{
-					Title:       title,
-					ProjectType: "android",
+				"fastlane-config_android": models.BitriseDataModel{
+					DefaultStepLibSource: stepLibSource,
+					ProjectType:          "android",
 				},
 			},
 		},
@@ -0,0 +1,166 @@
+package utility
+
+import (
+	"path/filepath"
+
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// DependencyManager describes where a dependency manager stores its lockfiles and its dependencies.
+type DependencyManager struct {
+	Name string
+	// LockFiles are glob patterns relative to the project directory, used to detect the dependency manager.
+	LockFiles []string
+	// KeyFiles are checksum patterns relative to the project directory, the cache key is derived from.
+	// If empty, the detected lockfiles are used.
+	KeyFiles []string
+	// ProjectPaths are cached paths relative to the project directory.
+	ProjectPaths []string
+	// Paths are cached paths independent of the project directory.
+	Paths []string
+}
+
+// Dependency managers with key-based cache support.
+var (
+	CocoaPodsDependencyManager = DependencyManager{
+		Name:         "cocoapods",
+		LockFiles:    []string{"Podfile.lock"},
+		ProjectPaths: []string{"Pods"},
+	}
+	SwiftPackageManagerDependencyManager = DependencyManager{
+		Name: "spm",
+		LockFiles: []string{
+			"Package.resolved",
+			"*.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved",
+			"*.xcworkspace/xcshareddata/swiftpm/Package.resolved",
+		},
+		Paths: []string{"~/Library/Developer/Xcode/DerivedData/**/SourcePackages"},
+	}
+	BundlerDependencyManager = DependencyManager{
+		Name:         "bundler",
+		LockFiles:    []string{"Gemfile.lock"},
+		ProjectPaths: []string{"vendor/bundle"},
+	}
+	YarnDependencyManager = DependencyManager{
+		Name:         "yarn",
+		LockFiles:    []string{"yarn.lock"},
+		ProjectPaths: []string{"node_modules"},
+	}
+	NpmDependencyManager = DependencyManager{
+		Name:      "npm",
+		LockFiles: []string{"package-lock.json"},
+		Paths:     []string{"~/.npm"},
+	}
+	PnpmDependencyManager = DependencyManager{
+		Name:         "pnpm",
+		LockFiles:    []string{"pnpm-lock.yaml"},
+		ProjectPaths: []string{"node_modules"},
+	}
+	BunDependencyManager = DependencyManager{
+		Name:      "bun",
+		LockFiles: []string{"bun.lockb", "bun.lock"},
+		Paths:     []string{"~/.bun/install/cache"},
+	}
+	PubDependencyManager = DependencyManager{
+		Name:      "pub",
+		LockFiles: []string{"pubspec.lock"},
+		Paths:     []string{"~/.pub-cache"},
+	}
+	GradleDependencyManager = DependencyManager{
+		Name:      "gradle",
+		LockFiles: []string{"gradle/wrapper/gradle-wrapper.properties", "build.gradle", "build.gradle.kts"},
+		KeyFiles:  []string{"**/*.gradle*", "**/gradle-wrapper.properties"},
+		Paths:     []string{"~/.gradle/caches", "~/.gradle/wrapper"},
+	}
+	NuGetDependencyManager = DependencyManager{
+		Name:      "nuget",
+		LockFiles: []string{"packages.lock.json", "*/packages.lock.json"},
+		Paths:     []string{"~/.nuget/packages"},
+	}
+)
+
+// DetectDependencyCaches looks for the lockfiles of the given dependency managers in the project directories,
+// and returns a key-based dependency cache for every detected dependency manager.
+// The key files and cache paths are relative to the searchDir, which is the working directory of the build.
+func DetectDependencyCaches(searchDir string, projectDirs []string, managers ...DependencyManager) ([]steps.DependencyCache, error) {
+	var caches []steps.DependencyCache
+	for _, manager := range managers {
+		cache := steps.DependencyCache{Name: manager.Name}
+
+		for _, projectDir := range projectDirs {
+			if !filepath.IsAbs(projectDir) {
+				projectDir = filepath.Join(searchDir, projectDir)
+			}
+
+			relProjectDir, err := RelPath(searchDir, projectDir)
+			if err != nil {
+				return nil, err
+			}
+
+			var lockFiles []string
+			for _, pattern := range manager.LockFiles {
+				matches, err := filepath.Glob(filepath.Join(projectDir, pattern))
+				if err != nil {
+					return nil, err
+				}
+				for _, match := range matches {
+					relMatch, err := RelPath(searchDir, match)
+					if err != nil {
+						return nil, err
+					}
+					lockFiles = append(lockFiles, relMatch)
+				}
+			}
+			if len(lockFiles) == 0 {
+				continue
+			}
+
+			keyFiles := lockFiles
+			if len(manager.KeyFiles) > 0 {
+				keyFiles = nil
+				for _, pattern := range manager.KeyFiles {
+					keyFiles = append(keyFiles, filepath.Join(relProjectDir, pattern))
+				}
+			}
+			cache.KeyFiles = appendUnique(cache.KeyFiles, keyFiles...)
+
+			for _, pth := range manager.ProjectPaths {
+				cache.Paths = appendUnique(cache.Paths, filepath.Join(relProjectDir, pth))
+			}
+			cache.Paths = appendUnique(cache.Paths, manager.Paths...)
+		}
+
+		if len(cache.KeyFiles) > 0 {
+			caches = append(caches, cache)
+		}
+	}
+
+	return caches, nil
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		if !sliceutil.IsStringInSlice(item, list) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// MergeDependencyCaches merges the dependency caches of the same dependency manager into a single cache.
+func MergeDependencyCaches(caches ...steps.DependencyCache) []steps.DependencyCache {
+	var merged []steps.DependencyCache
+	indexByName := map[string]int{}
+	for _, cache := range caches {
+		idx, ok := indexByName[cache.Name]
+		if !ok {
+			indexByName[cache.Name] = len(merged)
+			merged = append(merged, steps.DependencyCache{Name: cache.Name})
+			idx = len(merged) - 1
+		}
+		merged[idx].KeyFiles = appendUnique(merged[idx].KeyFiles, cache.KeyFiles...)
+		merged[idx].Paths = appendUnique(merged[idx].Paths, cache.Paths...)
+	}
+	return merged
+}
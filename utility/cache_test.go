@@ -0,0 +1,64 @@
+package utility
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDependencyCaches(t *testing.T) {
+	searchDir, err := pathutil.NormalizedOSTempDirPath("__cache__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(searchDir))
+	}()
+
+	for _, file := range []string{
+		"app/yarn.lock",
+		"app/android/build.gradle",
+		"app/android/gradle/wrapper/gradle-wrapper.properties",
+		"other/yarn.lock",
+	} {
+		pth := filepath.Join(searchDir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, nil, 0644))
+	}
+
+	caches, err := DetectDependencyCaches(searchDir, []string{"app", "other"}, YarnDependencyManager, NpmDependencyManager)
+	require.NoError(t, err)
+	require.Equal(t, []steps.DependencyCache{
+		{
+			Name:     "yarn",
+			KeyFiles: []string{"app/yarn.lock", "other/yarn.lock"},
+			Paths:    []string{"app/node_modules", "other/node_modules"},
+		},
+	}, caches)
+
+	caches, err = DetectDependencyCaches(searchDir, []string{"app/android"}, GradleDependencyManager)
+	require.NoError(t, err)
+	require.Equal(t, []steps.DependencyCache{
+		{
+			Name:     "gradle",
+			KeyFiles: []string{"app/android/**/*.gradle*", "app/android/**/gradle-wrapper.properties"},
+			Paths:    []string{"~/.gradle/caches", "~/.gradle/wrapper"},
+		},
+	}, caches)
+	require.Equal(t, `{{ .OS }}-{{ .Arch }}-gradle-{{ checksum "app/android/**/*.gradle*" "app/android/**/gradle-wrapper.properties" }}`, caches[0].Key())
+}
+
+func TestMergeDependencyCaches(t *testing.T) {
+	merged := MergeDependencyCaches(
+		steps.DependencyCache{Name: "pub", KeyFiles: []string{"a/pubspec.lock"}, Paths: []string{"~/.pub-cache"}},
+		steps.DependencyCache{Name: "gradle", KeyFiles: []string{"a/android/**/*.gradle*"}, Paths: []string{"~/.gradle/caches"}},
+		steps.DependencyCache{Name: "pub", KeyFiles: []string{"b/pubspec.lock"}, Paths: []string{"~/.pub-cache"}},
+	)
+	require.Equal(t, []steps.DependencyCache{
+		{Name: "pub", KeyFiles: []string{"a/pubspec.lock", "b/pubspec.lock"}, Paths: []string{"~/.pub-cache"}},
+		{Name: "gradle", KeyFiles: []string{"a/android/**/*.gradle*"}, Paths: []string{"~/.gradle/caches"}},
+	}, merged)
+}
@@ -0,0 +1,267 @@
+package utility
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/steps"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+// JavaScript package manager names.
+const (
+	NpmPackageManager  = "npm"
+	YarnPackageManager = "yarn"
+	PnpmPackageManager = "pnpm"
+	BunPackageManager  = "bun"
+)
+
+const (
+	yarnrcYmlName     = ".yarnrc.yml"
+	packageJSONName   = "package.json"
+	jsInstallTitle    = "Install JavaScript dependencies"
+	jsRunScriptFormat = "Run %s script"
+)
+
+// jsLockFiles are the lockfiles of the package managers, in order of precedence in the same directory.
+var jsLockFiles = []struct {
+	name    string
+	manager string
+}{
+	{"pnpm-lock.yaml", PnpmPackageManager},
+	{"bun.lockb", BunPackageManager},
+	{"bun.lock", BunPackageManager},
+	{"yarn.lock", YarnPackageManager},
+	{"package-lock.json", NpmPackageManager},
+	{"npm-shrinkwrap.json", NpmPackageManager},
+}
+
+// JSDependencyManagers are the dependency managers of the JavaScript package managers, with key-based cache support.
+var JSDependencyManagers = []DependencyManager{YarnDependencyManager, NpmDependencyManager, PnpmDependencyManager, BunDependencyManager}
+
+// JSPackageManager is the package manager of a JavaScript project,
+// detected by the packageManager field of package.json and the lockfiles in the project and its parent directories.
+type JSPackageManager struct {
+	Name string
+	// Version is the version set in the packageManager field of package.json, empty if not set.
+	Version string
+	// IsYarnBerry is set for Yarn 2 and later, which is installed by Corepack and can use Plug'n'Play.
+	IsYarnBerry bool
+	// LockFilePth is the absolute path of the lockfile, empty if not found.
+	LockFilePth string
+	// InstallDir is the directory of the lockfile relative to the search dir, if it is a parent directory of the package.json
+	// (the root of a monorepo workspace), empty otherwise.
+	InstallDir string
+}
+
+// DetectJSPackageManager detects the package manager of the package.json in the packageJSONDir.
+// The lockfile is looked for in the packageJSONDir and its parent directories up to the searchDir,
+// the packageManager field of the package.json files takes precedence over the lockfile.
+func DetectJSPackageManager(searchDir, packageJSONDir string) (JSPackageManager, error) {
+	absSearchDir, err := pathutil.AbsPath(searchDir)
+	if err != nil {
+		return JSPackageManager{}, err
+	}
+	absPackageJSONDir, err := pathutil.AbsPath(packageJSONDir)
+	if err != nil {
+		return JSPackageManager{}, err
+	}
+
+	var manager JSPackageManager
+	packageManagerField := ""
+	rootDir := absPackageJSONDir
+	for dir := absPackageJSONDir; ; dir = filepath.Dir(dir) {
+		if packageManagerField == "" {
+			if packageManagerField, err = readPackageManagerField(filepath.Join(dir, packageJSONName)); err != nil {
+				return JSPackageManager{}, err
+			}
+		}
+
+		for _, lockFile := range jsLockFiles {
+			pth := filepath.Join(dir, lockFile.name)
+			if exist, err := pathutil.IsPathExists(pth); err != nil {
+				return JSPackageManager{}, err
+			} else if exist {
+				manager.Name = lockFile.manager
+				manager.LockFilePth = pth
+				break
+			}
+		}
+		if manager.LockFilePth != "" {
+			rootDir = dir
+			break
+		}
+
+		if dir == absSearchDir || !strings.HasPrefix(dir, absSearchDir) || filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	if packageManagerField != "" {
+		// yarn@3.6.1 / pnpm@8.6.0+sha256.01ba...
+		split := strings.SplitN(packageManagerField, "@", 2)
+		manager.Name = split[0]
+		if len(split) == 2 {
+			manager.Version = strings.SplitN(split[1], "+", 2)[0]
+		}
+	}
+	if manager.Name == "" {
+		manager.Name = NpmPackageManager
+	}
+
+	if manager.Name == YarnPackageManager {
+		if manager.Version != "" {
+			major, err := strconv.Atoi(strings.SplitN(manager.Version, ".", 2)[0])
+			manager.IsYarnBerry = err == nil && major >= 2
+		} else if exist, err := pathutil.IsPathExists(filepath.Join(rootDir, yarnrcYmlName)); err != nil {
+			return JSPackageManager{}, err
+		} else {
+			manager.IsYarnBerry = exist
+		}
+	}
+
+	if rootDir != absPackageJSONDir {
+		if manager.InstallDir, err = RelPath(absSearchDir, rootDir); err != nil {
+			return JSPackageManager{}, err
+		}
+	}
+
+	return manager, nil
+}
+
+func readPackageManagerField(packageJSONPth string) (string, error) {
+	if exist, err := pathutil.IsPathExists(packageJSONPth); err != nil {
+		return "", err
+	} else if !exist {
+		return "", nil
+	}
+
+	content, err := fileutil.ReadBytesFromFile(packageJSONPth)
+	if err != nil {
+		return "", err
+	}
+
+	var packageJSON struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if err := json.Unmarshal(content, &packageJSON); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %s", packageJSONPth, err)
+	}
+	return packageJSON.PackageManager, nil
+}
+
+// String ...
+func (manager JSPackageManager) String() string {
+	if manager.IsYarnBerry {
+		return "yarn berry"
+	}
+	return manager.Name
+}
+
+// hasStep reports whether the package manager has a dedicated step, otherwise its commands are run by Script steps.
+func (manager JSPackageManager) hasStep() bool {
+	return manager.Name == NpmPackageManager || (manager.Name == YarnPackageManager && !manager.IsYarnBerry)
+}
+
+// InstallCommand returns the command installing the dependencies, respecting the lockfile if present.
+func (manager JSPackageManager) InstallCommand() string {
+	hasLockFile := manager.LockFilePth != ""
+	switch {
+	case manager.Name == NpmPackageManager && hasLockFile:
+		return "npm ci"
+	case manager.IsYarnBerry && hasLockFile:
+		return "yarn install --immutable"
+	case (manager.Name == PnpmPackageManager || manager.Name == BunPackageManager) && hasLockFile:
+		return manager.Name + " install --frozen-lockfile"
+	default:
+		return manager.Name + " install"
+	}
+}
+
+// RunCommand returns the command running the package.json script.
+func (manager JSPackageManager) RunCommand(script string) string {
+	return manager.Name + " run " + script
+}
+
+// ExecCommand returns the command running a binary of the installed dependencies.
+func (manager JSPackageManager) ExecCommand(binary string) string {
+	switch manager.Name {
+	case YarnPackageManager:
+		return "yarn " + binary
+	case PnpmPackageManager:
+		return "pnpm exec " + binary
+	case BunPackageManager:
+		return "bunx " + binary
+	default:
+		return "npx " + binary
+	}
+}
+
+// setupScript returns the commands making the package manager available on the build machine.
+func (manager JSPackageManager) setupScript() string {
+	switch {
+	case manager.Name == PnpmPackageManager || manager.IsYarnBerry:
+		return "corepack enable\n"
+	case manager.Name == BunPackageManager:
+		return `if ! command -v bun &> /dev/null; then
+  curl -fsSL https://bun.sh/install | bash
+  envman add --key PATH --value "$HOME/.bun/bin:$PATH"
+  export PATH="$HOME/.bun/bin:$PATH"
+fi
+`
+	}
+	return ""
+}
+
+// InstallStepListItem returns the step installing the dependencies in the workdir (or in the workspace root).
+func (manager JSPackageManager) InstallStepListItem(workdir string) bitriseModels.StepListItemModel {
+	if manager.InstallDir != "" {
+		workdir = manager.InstallDir
+	}
+
+	if manager.hasStep() {
+		return manager.stepListItem("install", workdir)
+	}
+	return manager.scriptStepListItem(jsInstallTitle, manager.setupScript()+manager.InstallCommand(), workdir)
+}
+
+// RunStepListItem returns the step running the package.json script in the workdir.
+func (manager JSPackageManager) RunStepListItem(script, workdir string) bitriseModels.StepListItemModel {
+	if manager.hasStep() {
+		command := script
+		if manager.Name == NpmPackageManager && script != "test" {
+			command = "run " + script
+		}
+		return manager.stepListItem(command, workdir)
+	}
+	return manager.scriptStepListItem(fmt.Sprintf(jsRunScriptFormat, script), manager.RunCommand(script), workdir)
+}
+
+func (manager JSPackageManager) stepListItem(command, workdir string) bitriseModels.StepListItemModel {
+	var inputs []envmanModels.EnvironmentItemModel
+	if workdir != "" {
+		inputs = append(inputs, envmanModels.EnvironmentItemModel{"workdir": workdir})
+	}
+	inputs = append(inputs, envmanModels.EnvironmentItemModel{"command": command})
+
+	if manager.Name == YarnPackageManager {
+		return steps.YarnStepListItem(inputs...)
+	}
+	return steps.NpmStepListItem(inputs...)
+}
+
+func (manager JSPackageManager) scriptStepListItem(title, script, workdir string) bitriseModels.StepListItemModel {
+	inputs := []envmanModels.EnvironmentItemModel{
+		{"content": "#!/usr/bin/env bash\nset -ex\n\n" + script},
+	}
+	if workdir != "" {
+		inputs = append(inputs, envmanModels.EnvironmentItemModel{"working_dir": workdir})
+	}
+	return steps.ScriptSteplistItem(title, inputs...)
+}
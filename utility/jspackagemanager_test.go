@@ -0,0 +1,99 @@
+package utility
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectJSPackageManager(t *testing.T) {
+	tests := []struct {
+		name           string
+		files          map[string]string
+		packageJSONDir string
+		want           JSPackageManager
+		installCommand string
+	}{
+		{
+			name:           "no lockfile",
+			files:          map[string]string{"package.json": `{}`},
+			packageJSONDir: ".",
+			want:           JSPackageManager{Name: NpmPackageManager},
+			installCommand: "npm install",
+		},
+		{
+			name:           "npm",
+			files:          map[string]string{"package.json": `{}`, "package-lock.json": ""},
+			packageJSONDir: ".",
+			want:           JSPackageManager{Name: NpmPackageManager, LockFilePth: "package-lock.json"},
+			installCommand: "npm ci",
+		},
+		{
+			name:           "yarn classic",
+			files:          map[string]string{"package.json": `{}`, "yarn.lock": ""},
+			packageJSONDir: ".",
+			want:           JSPackageManager{Name: YarnPackageManager, LockFilePth: "yarn.lock"},
+			installCommand: "yarn install",
+		},
+		{
+			name:           "yarn berry by .yarnrc.yml",
+			files:          map[string]string{"package.json": `{}`, "yarn.lock": "", ".yarnrc.yml": "nodeLinker: pnp"},
+			packageJSONDir: ".",
+			want:           JSPackageManager{Name: YarnPackageManager, IsYarnBerry: true, LockFilePth: "yarn.lock"},
+			installCommand: "yarn install --immutable",
+		},
+		{
+			name:           "packageManager field",
+			files:          map[string]string{"package.json": `{"packageManager": "yarn@3.6.1+sha224.abc"}`},
+			packageJSONDir: ".",
+			want:           JSPackageManager{Name: YarnPackageManager, Version: "3.6.1", IsYarnBerry: true},
+			installCommand: "yarn install",
+		},
+		{
+			name:           "bun",
+			files:          map[string]string{"package.json": `{}`, "bun.lockb": ""},
+			packageJSONDir: ".",
+			want:           JSPackageManager{Name: BunPackageManager, LockFilePth: "bun.lockb"},
+			installCommand: "bun install --frozen-lockfile",
+		},
+		{
+			name: "pnpm workspace",
+			files: map[string]string{
+				"package.json":             `{"packageManager": "pnpm@8.6.0"}`,
+				"pnpm-lock.yaml":           "",
+				"apps/mobile/package.json": `{}`,
+			},
+			packageJSONDir: "apps/mobile",
+			want:           JSPackageManager{Name: PnpmPackageManager, Version: "8.6.0", LockFilePth: "pnpm-lock.yaml", InstallDir: "."},
+			installCommand: "pnpm install --frozen-lockfile",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchDir, err := pathutil.NormalizedOSTempDirPath("__js_package_manager__")
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, os.RemoveAll(searchDir))
+			}()
+
+			for file, content := range tt.files {
+				pth := filepath.Join(searchDir, file)
+				require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+				require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+			}
+
+			got, err := DetectJSPackageManager(searchDir, filepath.Join(searchDir, tt.packageJSONDir))
+			require.NoError(t, err)
+
+			if tt.want.LockFilePth != "" {
+				tt.want.LockFilePth = filepath.Join(searchDir, tt.want.LockFilePth)
+			}
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.installCommand, got.InstallCommand())
+		})
+	}
+}
@@ -55,3 +55,30 @@ func RelPath(basePth, pth string) (string, error) {
 
 	return filepath.Rel(absBasePth, absPth)
 }
+
+// IsInDirs returns true if the path is one of the dirs or is inside one of them,
+// the path and the dirs are relative to the same directory.
+func IsInDirs(pth string, dirs []string) bool {
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, pth)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExcludePathsInDirs returns the paths, which are not inside any of the dirs.
+func ExcludePathsInDirs(pths, dirs []string) []string {
+	if len(dirs) == 0 {
+		return pths
+	}
+
+	var filtered []string
+	for _, pth := range pths {
+		if !IsInDirs(pth, dirs) {
+			filtered = append(filtered, pth)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,35 @@
+package utility
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInDirs(t *testing.T) {
+	tests := []struct {
+		name string
+		pth  string
+		dirs []string
+		want bool
+	}{
+		{name: "search dir", pth: "ios/App.xcodeproj", dirs: []string{"."}, want: true},
+		{name: "the dir itself", pth: "kmp", dirs: []string{"kmp"}, want: true},
+		{name: "inside the dir", pth: "kmp/iosApp/iosApp.xcodeproj", dirs: []string{"other", "kmp"}, want: true},
+		{name: "sibling with the same prefix", pth: "kmp2/iosApp/iosApp.xcodeproj", dirs: []string{"kmp"}, want: false},
+		{name: "outside the dir", pth: "ios/App.xcodeproj", dirs: []string{"kmp"}, want: false},
+		{name: "no dirs", pth: "ios/App.xcodeproj", dirs: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsInDirs(tt.pth, tt.dirs))
+		})
+	}
+}
+
+func TestExcludePathsInDirs(t *testing.T) {
+	pths := []string{"kmp/iosApp/iosApp.xcodeproj", "ios/App.xcodeproj", "kmp2/App.xcodeproj"}
+	require.Equal(t, pths, ExcludePathsInDirs(pths, nil))
+	require.Equal(t, []string{"ios/App.xcodeproj", "kmp2/App.xcodeproj"}, ExcludePathsInDirs(pths, []string{"kmp"}))
+	require.Equal(t, []string(nil), ExcludePathsInDirs(pths, []string{"."}))
+}